@@ -72,6 +72,8 @@ func Basic(t *testing.T, opts BasicOpts) config.Config {
 	c.RetryMaxLimit = 3
 	c.LogBatchThresholdSeconds = 1
 	c.LogBatchSize = 100
+	// The mock server runs locally, which the egress policy blocks by default
+	c.Destinations.Egress.AllowCIDRs = []string{"127.0.0.0/8", "::1/128"}
 
 	// Setup cleanup
 	t.Cleanup(func() {
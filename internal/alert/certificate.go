@@ -0,0 +1,94 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyCertificateExpiry = "certificate_expiry" // Marks certificates already alerted on
+
+// CertificateExpiryData represents the data sent with a certificate expiry alert
+type CertificateExpiryData struct {
+	ExpiresAt   time.Time         `json:"expires_at"`
+	Destination *AlertDestination `json:"destination"`
+}
+
+// CertificateExpiryAlert represents an alert for a destination's client
+// certificate that is about to expire
+type CertificateExpiryAlert struct {
+	Topic     string                `json:"topic"`
+	Timestamp time.Time             `json:"timestamp"`
+	Data      CertificateExpiryData `json:"data"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (a CertificateExpiryAlert) MarshalJSON() ([]byte, error) {
+	type Alias CertificateExpiryAlert
+	return json.Marshal(Alias(a))
+}
+
+// NewCertificateExpiryAlert creates a new certificate expiry alert
+func NewCertificateExpiryAlert(data CertificateExpiryData) CertificateExpiryAlert {
+	return CertificateExpiryAlert{
+		Topic:     "alert.certificate_expiry",
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+}
+
+// CertificateExpiryMonitor alerts when destination certificates are close to
+// expiring
+type CertificateExpiryMonitor interface {
+	// Check sends an alert if the certificate expires within the threshold.
+	// Each certificate is alerted on once.
+	Check(ctx context.Context, destination *AlertDestination, expiresAt time.Time) error
+}
+
+type certificateExpiryMonitor struct {
+	client    *redis.Client
+	notifier  AlertNotifier
+	threshold time.Duration
+}
+
+// NewCertificateExpiryMonitor creates a monitor alerting through the notifier
+// on certificates expiring within the threshold
+func NewCertificateExpiryMonitor(client *redis.Client, notifier AlertNotifier, threshold time.Duration) CertificateExpiryMonitor {
+	return &certificateExpiryMonitor{
+		client:    client,
+		notifier:  notifier,
+		threshold: threshold,
+	}
+}
+
+func (m *certificateExpiryMonitor) Check(ctx context.Context, destination *AlertDestination, expiresAt time.Time) error {
+	if time.Until(expiresAt) > m.threshold {
+		return nil
+	}
+
+	// The key is kept until the certificate expires, so a replaced certificate
+	// with a different expiry is alerted on again.
+	ttl := max(time.Until(expiresAt)+24*time.Hour, time.Hour)
+	key := fmt.Sprintf("%s:%s:%s:%d", keyPrefixAlert, destination.ID, keyCertificateExpiry, expiresAt.Unix())
+	first, err := m.client.SetNX(ctx, key, 1, ttl).Result()
+	if err != nil {
+		return fmt.Errorf("failed to mark certificate expiry alert: %w", err)
+	}
+	if !first {
+		return nil
+	}
+
+	alert := NewCertificateExpiryAlert(CertificateExpiryData{
+		ExpiresAt:   expiresAt,
+		Destination: destination,
+	})
+	if err := m.notifier.Notify(ctx, alert); err != nil {
+		// Allow the alert to be sent again on the next check
+		m.client.Del(ctx, key)
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	return nil
+}
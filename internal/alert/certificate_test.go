@@ -0,0 +1,66 @@
+package alert_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hookdeck/outpost/internal/alert"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+)
+
+func TestCertificateExpiryMonitor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dest := &alert.AlertDestination{ID: "dest_1", TenantID: "tenant_1"}
+
+	t.Run("should alert once within the threshold", func(t *testing.T) {
+		t.Parallel()
+		notifier := &mockAlertNotifier{}
+		notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+		monitor := alert.NewCertificateExpiryMonitor(testutil.CreateTestRedisClient(t), notifier, 14*24*time.Hour)
+
+		expiresAt := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, monitor.Check(ctx, dest, expiresAt))
+		}
+
+		notifier.AssertNumberOfCalls(t, "Notify", 1)
+		certAlert := notifier.Calls[0].Arguments.Get(1).(alert.CertificateExpiryAlert)
+		assert.Equal(t, "alert.certificate_expiry", certAlert.Topic)
+		assert.Equal(t, dest, certAlert.Data.Destination)
+		assert.True(t, expiresAt.Equal(certAlert.Data.ExpiresAt))
+
+		// A renewed certificate that's also about to expire is alerted on again
+		require.NoError(t, monitor.Check(ctx, dest, expiresAt.Add(24*time.Hour)))
+		notifier.AssertNumberOfCalls(t, "Notify", 2)
+	})
+
+	t.Run("should not alert outside the threshold", func(t *testing.T) {
+		t.Parallel()
+		notifier := &mockAlertNotifier{}
+		monitor := alert.NewCertificateExpiryMonitor(testutil.CreateTestRedisClient(t), notifier, 14*24*time.Hour)
+
+		require.NoError(t, monitor.Check(ctx, dest, time.Now().Add(30*24*time.Hour)))
+		notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should retry the alert after a failed notification", func(t *testing.T) {
+		t.Parallel()
+		notifier := &mockAlertNotifier{}
+		notifier.On("Notify", mock.Anything, mock.Anything).Return(errors.New("callback unavailable")).Once()
+		notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+		monitor := alert.NewCertificateExpiryMonitor(testutil.CreateTestRedisClient(t), notifier, 14*24*time.Hour)
+
+		expiresAt := time.Now().Add(-time.Hour) // already expired
+		require.Error(t, monitor.Check(ctx, dest, expiresAt))
+		require.NoError(t, monitor.Check(ctx, dest, expiresAt))
+		require.NoError(t, monitor.Check(ctx, dest, expiresAt))
+		notifier.AssertNumberOfCalls(t, "Notify", 2)
+	})
+}
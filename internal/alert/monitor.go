@@ -80,6 +80,9 @@ type DeliveryAttempt struct {
 	Destination      *AlertDestination
 	Timestamp        time.Time
 	DeliveryResponse map[string]interface{}
+	// DisableDestination is set when the response means the destination is
+	// gone, e.g. a 410 Gone, so it's disabled without waiting for more failures
+	DisableDestination bool
 }
 
 type alertMonitor struct {
@@ -134,6 +137,10 @@ func (m *alertMonitor) HandleAttempt(ctx context.Context, attempt DeliveryAttemp
 		return m.store.ResetConsecutiveFailureCount(ctx, attempt.Destination.TenantID, attempt.Destination.ID)
 	}
 
+	if attempt.DisableDestination && m.disabler != nil {
+		return m.disableDestination(ctx, attempt)
+	}
+
 	// Get alert state
 	count, err := m.store.IncrementConsecutiveFailureCount(ctx, attempt.Destination.TenantID, attempt.Destination.ID)
 	if err != nil {
@@ -194,3 +201,44 @@ func (m *alertMonitor) HandleAttempt(ctx context.Context, attempt DeliveryAttemp
 
 	return nil
 }
+
+// disableDestination disables the destination right away and alerts about it
+func (m *alertMonitor) disableDestination(ctx context.Context, attempt DeliveryAttempt) error {
+	if err := m.disabler.DisableDestination(ctx, attempt.Destination.TenantID, attempt.Destination.ID); err != nil {
+		return fmt.Errorf("failed to disable destination: %w", err)
+	}
+
+	alert := NewDestinationDisabledAlert(DestinationDisabledData{
+		Event: AlertedEvent{
+			ID:       attempt.DeliveryEvent.Event.ID,
+			Topic:    attempt.DeliveryEvent.Event.Topic,
+			Metadata: attempt.DeliveryEvent.Event.Metadata,
+			Data:     attempt.DeliveryEvent.Event.Data,
+		},
+		Reason:           "disable_status_code",
+		Destination:      attempt.Destination,
+		DeliveryResponse: attempt.DeliveryResponse,
+	})
+	m.logger.Ctx(ctx).Audit("destination disabled",
+		zap.String("destination_id", attempt.Destination.ID),
+		zap.String("tenant_id", attempt.Destination.TenantID),
+		zap.String("topic", alert.Topic),
+	)
+
+	// The count restarts when the destination is enabled again
+	if err := m.store.ResetConsecutiveFailureCount(ctx, attempt.Destination.TenantID, attempt.Destination.ID); err != nil {
+		return fmt.Errorf("failed to reset consecutive failure count: %w", err)
+	}
+
+	if m.notifier != nil {
+		if err := m.notifier.Notify(ctx, alert); err != nil {
+			return fmt.Errorf("failed to send alert: %w", err)
+		}
+		m.logger.Ctx(ctx).Audit("alert sent",
+			zap.String("destination_id", attempt.Destination.ID),
+			zap.String("tenant_id", attempt.Destination.TenantID),
+			zap.String("topic", alert.Topic),
+		)
+	}
+	return nil
+}
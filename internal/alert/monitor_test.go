@@ -168,3 +168,50 @@ func TestAlertMonitor_ConsecutiveFailures_Reset(t *testing.T) {
 	// Verify the destination was never disabled
 	disabler.AssertNotCalled(t, "DisableDestination")
 }
+
+func TestAlertMonitor_DisableDestination(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	logger := testutil.CreateTestLogger(t)
+	redisClient := testutil.CreateTestRedisClient(t)
+	notifier := &mockAlertNotifier{}
+	notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+	disabler := &mockDestinationDisabler{}
+	disabler.On("DisableDestination", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	monitor := alert.NewAlertMonitor(
+		logger,
+		redisClient,
+		alert.WithNotifier(notifier),
+		alert.WithDisabler(disabler),
+		alert.WithAutoDisableFailureCount(20),
+	)
+
+	dest := &alert.AlertDestination{ID: "dest_1", TenantID: "tenant_1"}
+	event := &models.Event{Topic: "test.event"}
+	deliveryEvent := &models.DeliveryEvent{Event: *event}
+	attempt := alert.DeliveryAttempt{
+		Success:       false,
+		DeliveryEvent: deliveryEvent,
+		Destination:   dest,
+		DeliveryResponse: map[string]interface{}{
+			"status": 410,
+		},
+		Timestamp:          time.Now(),
+		DisableDestination: true,
+	}
+
+	require.NoError(t, monitor.HandleAttempt(ctx, attempt))
+
+	// Destination should be disabled on the first failure
+	disabler.AssertNumberOfCalls(t, "DisableDestination", 1)
+	disabler.AssertCalled(t, "DisableDestination", mock.Anything, dest.TenantID, dest.ID)
+
+	notifier.AssertNumberOfCalls(t, "Notify", 1)
+	disabledAlert, ok := notifier.Calls[0].Arguments.Get(1).(alert.DestinationDisabledAlert)
+	require.True(t, ok)
+	assert.Equal(t, "alert.destination_disabled", disabledAlert.Topic)
+	assert.Equal(t, "disable_status_code", disabledAlert.Data.Reason)
+	assert.Equal(t, dest, disabledAlert.Data.Destination)
+	assert.Equal(t, attempt.DeliveryResponse, disabledAlert.Data.DeliveryResponse)
+}
@@ -91,6 +91,37 @@ func NewConsecutiveFailureAlert(data ConsecutiveFailureData) ConsecutiveFailureA
 	}
 }
 
+// DestinationDisabledData represents the data sent with a destination disabled alert
+type DestinationDisabledData struct {
+	Event            AlertedEvent           `json:"event"`
+	Reason           string                 `json:"reason"`
+	Destination      *AlertDestination      `json:"destination"`
+	DeliveryResponse map[string]interface{} `json:"delivery_response"`
+}
+
+// DestinationDisabledAlert represents an alert for a destination disabled
+// because of a delivery response, e.g. a 410 Gone
+type DestinationDisabledAlert struct {
+	Topic     string                  `json:"topic"`
+	Timestamp time.Time               `json:"timestamp"`
+	Data      DestinationDisabledData `json:"data"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (a DestinationDisabledAlert) MarshalJSON() ([]byte, error) {
+	type Alias DestinationDisabledAlert
+	return json.Marshal(Alias(a))
+}
+
+// NewDestinationDisabledAlert creates a new destination disabled alert
+func NewDestinationDisabledAlert(data DestinationDisabledData) DestinationDisabledAlert {
+	return DestinationDisabledAlert{
+		Topic:     "alert.destination_disabled",
+		Timestamp: time.Now(),
+		Data:      data,
+	}
+}
+
 type httpAlertNotifier struct {
 	client      *http.Client
 	callbackURL string
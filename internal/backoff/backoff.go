@@ -1,6 +1,9 @@
 package backoff
 
-import "time"
+import (
+	"math/rand/v2"
+	"time"
+)
 
 type Backoff interface {
 	// Duration returns the duration to wait before retrying the operation.
@@ -45,3 +48,58 @@ var _ Backoff = &ConstantBackoff{}
 func (b *ConstantBackoff) Duration(retries int) time.Duration {
 	return b.Interval
 }
+
+// CappedExponentialBackoff is an ExponentialBackoff whose duration never exceeds Max.
+type CappedExponentialBackoff struct {
+	Interval time.Duration
+	Base     int
+	Max      time.Duration
+}
+
+var _ Backoff = &CappedExponentialBackoff{}
+
+func (b *CappedExponentialBackoff) Duration(retries int) time.Duration {
+	duration := b.Interval
+	for i := 0; i < retries; i++ {
+		// Stop early to avoid overflowing on large retry counts
+		if duration >= b.Max {
+			return b.Max
+		}
+		duration *= time.Duration(b.Base)
+	}
+	return min(duration, b.Max)
+}
+
+// ScheduledBackoff returns durations from an explicit schedule.
+// Retries past the end of the schedule reuse its last duration.
+type ScheduledBackoff struct {
+	Schedule []time.Duration
+}
+
+var _ Backoff = &ScheduledBackoff{}
+
+func (b *ScheduledBackoff) Duration(retries int) time.Duration {
+	if len(b.Schedule) == 0 {
+		return 0
+	}
+	if retries >= len(b.Schedule) {
+		return b.Schedule[len(b.Schedule)-1]
+	}
+	return b.Schedule[retries]
+}
+
+// JitterBackoff randomizes the duration of the wrapped Backoff by up to Factor
+// in either direction so that retries failing together don't fire together.
+// For example, a Factor of 0.2 turns a 10s duration into a value between 8s and 12s.
+type JitterBackoff struct {
+	Backoff Backoff
+	Factor  float64
+}
+
+var _ Backoff = &JitterBackoff{}
+
+func (b *JitterBackoff) Duration(retries int) time.Duration {
+	duration := b.Backoff.Duration(retries)
+	delta := float64(duration) * b.Factor
+	return time.Duration(float64(duration) - delta + rand.Float64()*2*delta)
+}
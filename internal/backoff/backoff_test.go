@@ -87,3 +87,56 @@ func TestBackoff_Constant(t *testing.T) {
 	}
 	testBackoff(t, "ConstantBackoff{Interval:30*time.Second}", bo, testCases)
 }
+
+func TestBackoff_CappedExponential(t *testing.T) {
+	bo := &backoff.CappedExponentialBackoff{
+		Interval: 30 * time.Second,
+		Base:     2,
+		Max:      5 * time.Minute,
+	}
+	testCases := []testCase{
+		{0, 30 * time.Second},
+		{1, 60 * time.Second},
+		{2, 120 * time.Second},
+		{3, 240 * time.Second},
+		{4, 300 * time.Second},
+		{5, 300 * time.Second},
+		{100, 300 * time.Second},
+	}
+	testBackoff(t, "CappedExponentialBackoff{Interval:30*time.Second,Base:2,Max:5*time.Minute}", bo, testCases)
+}
+
+func TestBackoff_Scheduled(t *testing.T) {
+	bo := &backoff.ScheduledBackoff{
+		Schedule: []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour},
+	}
+	testCases := []testCase{
+		{0, time.Minute},
+		{1, 5 * time.Minute},
+		{2, 30 * time.Minute},
+		{3, 2 * time.Hour},
+		{4, 12 * time.Hour},
+		{5, 12 * time.Hour},
+	}
+	testBackoff(t, "ScheduledBackoff{Schedule:[1m,5m,30m,2h,12h]}", bo, testCases)
+}
+
+func TestBackoff_Jitter(t *testing.T) {
+	t.Parallel()
+
+	bo := &backoff.JitterBackoff{
+		Backoff: &backoff.ConstantBackoff{Interval: 10 * time.Second},
+		Factor:  0.2,
+	}
+	for i := 0; i < 100; i++ {
+		duration := bo.Duration(i)
+		assert.GreaterOrEqual(t, duration, 8*time.Second)
+		assert.LessOrEqual(t, duration, 12*time.Second)
+	}
+
+	noJitter := &backoff.JitterBackoff{
+		Backoff: &backoff.ConstantBackoff{Interval: 10 * time.Second},
+		Factor:  0,
+	}
+	assert.Equal(t, 10*time.Second, noJitter.Duration(0))
+}
@@ -0,0 +1,197 @@
+package circuitbreaker
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+const (
+	keyPrefixCircuitBreaker = "circuitbreaker"
+
+	defaultFailureThreshold = 10
+	defaultOpenTimeout      = time.Minute
+	// stateTTL expires the state of destinations that stopped receiving deliveries.
+	stateTTL = 24 * time.Hour
+)
+
+type State string
+
+const (
+	// StateClosed allows deliveries.
+	StateClosed State = "closed"
+	// StateOpen rejects deliveries until the open timeout elapses.
+	StateOpen State = "open"
+	// StateHalfOpen allows a single trial delivery whose outcome closes or reopens the breaker.
+	StateHalfOpen State = "half_open"
+)
+
+// Status is the circuit breaker status of a destination.
+type Status struct {
+	State    State      `json:"state"`
+	Failures int        `json:"failures"`
+	OpenedAt *time.Time `json:"opened_at,omitempty"`
+	// RetryAt is when the next trial delivery is allowed while open.
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+}
+
+// CircuitBreaker tracks delivery failures per destination, shared across
+// every process using the same Redis. Destinations are identified by tenant
+// as destination IDs are only unique within a tenant.
+type CircuitBreaker interface {
+	// Allow returns whether a delivery may be attempted. When it's not
+	// allowed, it returns how long to wait before trying again.
+	Allow(ctx context.Context, tenantID, destinationID string) (bool, time.Duration, error)
+	RecordSuccess(ctx context.Context, tenantID, destinationID string) error
+	RecordFailure(ctx context.Context, tenantID, destinationID string) error
+	Status(ctx context.Context, tenantID, destinationID string) (*Status, error)
+}
+
+// Option configures a CircuitBreaker
+type Option func(cb *redisCircuitBreaker)
+
+// WithFailureThreshold sets the number of consecutive failures that opens the breaker.
+func WithFailureThreshold(threshold int) Option {
+	return func(cb *redisCircuitBreaker) {
+		if threshold > 0 {
+			cb.failureThreshold = threshold
+		}
+	}
+}
+
+// WithOpenTimeout sets how long the breaker stays open before allowing a trial delivery.
+func WithOpenTimeout(timeout time.Duration) Option {
+	return func(cb *redisCircuitBreaker) {
+		if timeout > 0 {
+			cb.openTimeout = timeout
+		}
+	}
+}
+
+type redisCircuitBreaker struct {
+	client           *redis.Client
+	failureThreshold int
+	openTimeout      time.Duration
+}
+
+// NewRedisCircuitBreaker creates a new Redis-backed circuit breaker
+func NewRedisCircuitBreaker(client *redis.Client, opts ...Option) CircuitBreaker {
+	cb := &redisCircuitBreaker{
+		client:           client,
+		failureThreshold: defaultFailureThreshold,
+		openTimeout:      defaultOpenTimeout,
+	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	return cb
+}
+
+// KEYS[1]: state hash with "state", "failures" and "opened_at" (ms)
+// KEYS[2]: trial key held by the trial delivery while half-open
+// ARGV: now (ms), open timeout (ms)
+//
+// Returns {1, 0} when allowed or {0, wait (ms)} when rejected.
+var allowScript = redis.NewScript(`
+local state = redis.call("HGET", KEYS[1], "state")
+if not state or state == "closed" then
+	return {1, 0}
+end
+
+local now = tonumber(ARGV[1])
+local timeout = tonumber(ARGV[2])
+if state == "open" then
+	local openedAt = tonumber(redis.call("HGET", KEYS[1], "opened_at"))
+	if now < openedAt + timeout then
+		return {0, openedAt + timeout - now}
+	end
+	redis.call("HSET", KEYS[1], "state", "half_open")
+end
+
+if redis.call("SET", KEYS[2], "1", "NX", "PX", timeout) then
+	return {1, 0}
+end
+return {0, timeout}
+`)
+
+// KEYS: same as allowScript
+// ARGV: now (ms), failure threshold, state TTL (ms)
+var recordFailureScript = redis.NewScript(`
+local state = redis.call("HGET", KEYS[1], "state") or "closed"
+local failures = redis.call("HINCRBY", KEYS[1], "failures", 1)
+if state == "half_open" or (state == "closed" and failures >= tonumber(ARGV[2])) then
+	redis.call("HSET", KEYS[1], "state", "open", "opened_at", ARGV[1])
+	redis.call("DEL", KEYS[2])
+end
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+return 1
+`)
+
+func (cb *redisCircuitBreaker) Allow(ctx context.Context, tenantID, destinationID string) (bool, time.Duration, error) {
+	result, err := allowScript.Run(ctx, cb.client, cb.keys(tenantID, destinationID),
+		time.Now().UnixMilli(), cb.openTimeout.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to check circuit breaker: %w", err)
+	}
+	if result[0] == 1 {
+		return true, 0, nil
+	}
+	// Spread out rejected deliveries so they don't all come back at once
+	wait := time.Duration(result[1]) * time.Millisecond
+	jitter := time.Duration(rand.Int64N(int64(cb.openTimeout)/10 + 1))
+	return false, wait + jitter, nil
+}
+
+func (cb *redisCircuitBreaker) RecordSuccess(ctx context.Context, tenantID, destinationID string) error {
+	return cb.client.Del(ctx, cb.keys(tenantID, destinationID)...).Err()
+}
+
+func (cb *redisCircuitBreaker) RecordFailure(ctx context.Context, tenantID, destinationID string) error {
+	err := recordFailureScript.Run(ctx, cb.client, cb.keys(tenantID, destinationID),
+		time.Now().UnixMilli(), cb.failureThreshold, stateTTL.Milliseconds(),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to record circuit breaker failure: %w", err)
+	}
+	return nil
+}
+
+func (cb *redisCircuitBreaker) Status(ctx context.Context, tenantID, destinationID string) (*Status, error) {
+	hash, err := cb.client.HGetAll(ctx, cb.keys(tenantID, destinationID)[0]).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{State: StateClosed}
+	if failures, err := strconv.Atoi(hash["failures"]); err == nil {
+		status.Failures = failures
+	}
+	if state := State(hash["state"]); state == StateOpen || state == StateHalfOpen {
+		status.State = state
+		if openedAtMs, err := strconv.ParseInt(hash["opened_at"], 10, 64); err == nil {
+			openedAt := time.UnixMilli(openedAtMs)
+			retryAt := openedAt.Add(cb.openTimeout)
+			status.OpenedAt = &openedAt
+			status.RetryAt = &retryAt
+			// The breaker only moves to half-open on the next delivery
+			if state == StateOpen && !time.Now().Before(retryAt) {
+				status.State = StateHalfOpen
+			}
+		}
+	}
+	return status, nil
+}
+
+// The destination is wrapped in a hash tag so that the keys of the scripts
+// map to the same slot on Redis Cluster.
+func (cb *redisCircuitBreaker) keys(tenantID, destinationID string) []string {
+	return []string{
+		fmt.Sprintf("%s:{%s:%s}", keyPrefixCircuitBreaker, tenantID, destinationID),
+		fmt.Sprintf("%s:{%s:%s}:trial", keyPrefixCircuitBreaker, tenantID, destinationID),
+	}
+}
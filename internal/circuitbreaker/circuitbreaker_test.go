@@ -0,0 +1,155 @@
+package circuitbreaker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/circuitbreaker"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("closed by default", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		cb := circuitbreaker.NewRedisCircuitBreaker(testutil.CreateTestRedisClient(t))
+		tenantID := uuid.New().String()
+		destinationID := uuid.New().String()
+
+		allowed, wait, err := cb.Allow(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+		assert.Zero(t, wait)
+
+		status, err := cb.Status(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.Equal(t, circuitbreaker.StateClosed, status.State)
+		assert.Zero(t, status.Failures)
+		assert.Nil(t, status.OpenedAt)
+	})
+
+	t.Run("opens after consecutive failures", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		cb := circuitbreaker.NewRedisCircuitBreaker(testutil.CreateTestRedisClient(t),
+			circuitbreaker.WithFailureThreshold(3),
+			circuitbreaker.WithOpenTimeout(time.Minute),
+		)
+		tenantID := uuid.New().String()
+		destinationID := uuid.New().String()
+
+		require.NoError(t, cb.RecordFailure(ctx, tenantID, destinationID))
+		require.NoError(t, cb.RecordFailure(ctx, tenantID, destinationID))
+		allowed, _, err := cb.Allow(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.True(t, allowed, "should allow below the threshold")
+
+		require.NoError(t, cb.RecordFailure(ctx, tenantID, destinationID))
+		allowed, wait, err := cb.Allow(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.False(t, allowed, "should reject once open")
+		assert.Greater(t, wait, 50*time.Second)
+
+		status, err := cb.Status(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.Equal(t, circuitbreaker.StateOpen, status.State)
+		assert.Equal(t, 3, status.Failures)
+		require.NotNil(t, status.OpenedAt)
+		require.NotNil(t, status.RetryAt)
+		assert.Equal(t, time.Minute, status.RetryAt.Sub(*status.OpenedAt))
+
+		// Other destinations aren't affected
+		allowed, _, err = cb.Allow(ctx, tenantID, uuid.New().String())
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("success resets failures", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		cb := circuitbreaker.NewRedisCircuitBreaker(testutil.CreateTestRedisClient(t),
+			circuitbreaker.WithFailureThreshold(2),
+		)
+		tenantID := uuid.New().String()
+		destinationID := uuid.New().String()
+
+		require.NoError(t, cb.RecordFailure(ctx, tenantID, destinationID))
+		require.NoError(t, cb.RecordSuccess(ctx, tenantID, destinationID))
+		require.NoError(t, cb.RecordFailure(ctx, tenantID, destinationID))
+
+		allowed, _, err := cb.Allow(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.True(t, allowed, "failures should be consecutive")
+	})
+
+	t.Run("half-open allows a single trial", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		cb := circuitbreaker.NewRedisCircuitBreaker(testutil.CreateTestRedisClient(t),
+			circuitbreaker.WithFailureThreshold(1),
+			circuitbreaker.WithOpenTimeout(50*time.Millisecond),
+		)
+		tenantID := uuid.New().String()
+		destinationID := uuid.New().String()
+
+		require.NoError(t, cb.RecordFailure(ctx, tenantID, destinationID))
+		time.Sleep(60 * time.Millisecond)
+
+		status, err := cb.Status(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.Equal(t, circuitbreaker.StateHalfOpen, status.State)
+
+		allowed, _, err := cb.Allow(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.True(t, allowed, "should allow a trial once the open timeout elapses")
+		allowed, wait, err := cb.Allow(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.False(t, allowed, "should reject while the trial is in flight")
+		assert.Positive(t, wait)
+
+		// A failed trial reopens the breaker
+		require.NoError(t, cb.RecordFailure(ctx, tenantID, destinationID))
+		status, err = cb.Status(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.Equal(t, circuitbreaker.StateOpen, status.State)
+
+		// A successful trial closes it
+		time.Sleep(60 * time.Millisecond)
+		allowed, _, err = cb.Allow(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+		require.NoError(t, cb.RecordSuccess(ctx, tenantID, destinationID))
+
+		status, err = cb.Status(ctx, tenantID, destinationID)
+		require.NoError(t, err)
+		assert.Equal(t, circuitbreaker.StateClosed, status.State)
+		assert.Zero(t, status.Failures)
+	})
+
+	t.Run("isolates tenants", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		cb := circuitbreaker.NewRedisCircuitBreaker(testutil.CreateTestRedisClient(t),
+			circuitbreaker.WithFailureThreshold(1),
+		)
+		destinationID := uuid.New().String()
+
+		require.NoError(t, cb.RecordFailure(ctx, "tenant_1", destinationID))
+		allowed, _, err := cb.Allow(ctx, "tenant_1", destinationID)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+
+		allowed, _, err = cb.Allow(ctx, "tenant_2", destinationID)
+		require.NoError(t, err)
+		assert.True(t, allowed, "same destination ID of another tenant shouldn't be affected")
+		status, err := cb.Status(ctx, "tenant_2", destinationID)
+		require.NoError(t, err)
+		assert.Equal(t, circuitbreaker.StateClosed, status.State)
+	})
+}
@@ -74,11 +74,19 @@ type Config struct {
 	// Delivery Retry
 	RetryIntervalSeconds int `yaml:"retry_interval_seconds" env:"RETRY_INTERVAL_SECONDS" desc:"Interval in seconds between delivery retry attempts for failed webhooks." required:"N"`
 	RetryMaxLimit        int `yaml:"retry_max_limit" env:"MAX_RETRY_LIMIT" desc:"Maximum number of retry attempts for a single event delivery before giving up." required:"N"`
+	RetryAfterMaxSeconds int `yaml:"retry_after_max_seconds" env:"RETRY_AFTER_MAX_SECONDS" desc:"Maximum delay in seconds honored when a destination asks to retry later, e.g. with a Retry-After header on a 429 or 503 response." required:"N"`
+
+	// Dead Letters
+	DeadLetterRetentionDays int `yaml:"dead_letter_retention_days" env:"DEAD_LETTER_RETENTION_DAYS" desc:"Number of days dead letters are kept before they expire." required:"N"`
 
 	// Event Delivery
 	MaxDestinationsPerTenant int `yaml:"max_destinations_per_tenant" env:"MAX_DESTINATIONS_PER_TENANT" desc:"Maximum number of destinations allowed per tenant/organization." required:"N"`
 	DeliveryTimeoutSeconds   int `yaml:"delivery_timeout_seconds" env:"DELIVERY_TIMEOUT_SECONDS" desc:"Timeout in seconds for HTTP requests made during event delivery to webhook destinations." required:"N"`
 
+	// Circuit Breaker
+	CircuitBreakerFailureThreshold int `yaml:"circuit_breaker_failure_threshold" env:"CIRCUIT_BREAKER_FAILURE_THRESHOLD" desc:"Number of consecutive failed deliveries after which a destination's circuit breaker opens and deliveries are rescheduled without being attempted. 0, the default, disables the circuit breaker." required:"N"`
+	CircuitBreakerOpenSeconds      int `yaml:"circuit_breaker_open_seconds" env:"CIRCUIT_BREAKER_OPEN_SECONDS" desc:"Time in seconds a destination's circuit breaker stays open before allowing a trial delivery." required:"N"`
+
 	// Destination Registry
 	DestinationMetadataPath string `yaml:"destination_metadata_path" env:"DESTINATION_METADATA_PATH" desc:"Path to the directory containing custom destination type definitions. Overrides 'destinations.metadata_path' if set." required:"N"`
 
@@ -106,6 +114,11 @@ var (
 	ErrMissingMQs            = errors.New("config validation error: message queue configuration is required")
 	ErrMissingAESSecret      = errors.New("config validation error: AES encryption secret is required")
 	ErrInvalidPortalProxyURL = errors.New("config validation error: invalid portal proxy url")
+	ErrInvalidWebhookMode    = errors.New("config validation error: invalid webhook mode, must be 'default' or 'standard'")
+	ErrInvalidEgressCIDR     = errors.New("config validation error: invalid egress CIDR")
+	ErrInvalidProxyURL       = errors.New("config validation error: invalid destination proxy url")
+	ErrInvalidStatusCodes    = errors.New("config validation error: invalid webhook status codes")
+	ErrInvalidVerification   = errors.New("config validation error: invalid webhook verification method, must be 'post' or 'get'")
 )
 
 func (c *Config) InitDefaults() {
@@ -149,8 +162,11 @@ func (c *Config) InitDefaults() {
 	c.LogMaxConcurrency = 1
 	c.RetryIntervalSeconds = 30
 	c.RetryMaxLimit = 10
+	c.RetryAfterMaxSeconds = 3600
+	c.DeadLetterRetentionDays = 30
 	c.MaxDestinationsPerTenant = 20
 	c.DeliveryTimeoutSeconds = 5
+	c.CircuitBreakerOpenSeconds = 60
 	c.LogBatchThresholdSeconds = 10
 	c.LogBatchSize = 1000
 
@@ -163,6 +179,8 @@ func (c *Config) InitDefaults() {
 			SignatureHeaderTemplate:  "t={{.Timestamp.Unix}},v0={{.Signatures | join \",\"}}",
 			SignatureEncoding:        "hex",
 			SignatureAlgorithm:       "hmac-sha256",
+			ResponseHeaders:          []string{"Content-Type", "Retry-After", "X-Request-Id"},
+			ResponseBodyMaxBytes:     64 * 1024,
 		},
 		AWSKinesis: DestinationAWSKinesisConfig{
 			MetadataInPayload: true,
@@ -173,6 +191,8 @@ func (c *Config) InitDefaults() {
 		CallbackURL:             "",
 		ConsecutiveFailureCount: 20,
 		AutoDisableDestination:  true,
+		DeadLetter:              false,
+		CertificateExpiryDays:   14,
 	}
 
 	c.Telemetry = TelemetryConfig{
@@ -350,6 +370,8 @@ type AlertConfig struct {
 	CallbackURL             string `yaml:"callback_url" env:"ALERT_CALLBACK_URL" desc:"URL to which Outpost will send a POST request when an alert is triggered (e.g., for destination failures)." required:"N"`
 	ConsecutiveFailureCount int    `yaml:"consecutive_failure_count" env:"ALERT_CONSECUTIVE_FAILURE_COUNT" desc:"Number of consecutive delivery failures for a destination before triggering an alert and potentially disabling it." required:"N"`
 	AutoDisableDestination  bool   `yaml:"auto_disable_destination" env:"ALERT_AUTO_DISABLE_DESTINATION" desc:"If true, automatically disables a destination after 'consecutive_failure_count' is reached." required:"N"`
+	DeadLetter              bool   `yaml:"dead_letter" env:"ALERT_DEAD_LETTER" desc:"If true, sends an alert to the 'callback_url' when a delivery exhausts its retries and is dead-lettered." required:"N"`
+	CertificateExpiryDays   int    `yaml:"certificate_expiry_days" env:"ALERT_CERTIFICATE_EXPIRY_DAYS" desc:"Number of days before a destination's client certificate expires to send an alert to the 'callback_url'. The alert is sent once per certificate, on a delivery to the destination. Set to 0 to disable." required:"N"`
 }
 
 // ConfigFilePath returns the path of the config file that was used
@@ -3,7 +3,11 @@ package config
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"net/url"
+	"slices"
+
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
 )
 
 // Validate checks if the configuration is valid
@@ -36,6 +40,10 @@ func (c *Config) Validate(flags Flags) error {
 		return err
 	}
 
+	if err := c.validateDestinations(); err != nil {
+		return err
+	}
+
 	if err := c.OpenTelemetry.Validate(); err != nil {
 		return err
 	}
@@ -136,3 +144,42 @@ func (c *Config) validatePortal() error {
 	}
 	return nil
 }
+
+// validateDestinations validates the destinations configuration
+func (c *Config) validateDestinations() error {
+	switch c.Destinations.Webhook.Mode {
+	case "", "default", "standard":
+	default:
+		return ErrInvalidWebhookMode
+	}
+
+	switch c.Destinations.Webhook.VerificationMethod {
+	case "", destwebhook.VerificationMethodPost, destwebhook.VerificationMethodGet:
+	default:
+		return ErrInvalidVerification
+	}
+
+	for _, codes := range []string{
+		c.Destinations.Webhook.SuccessStatusCodes,
+		c.Destinations.Webhook.NonRetryableStatusCodes,
+		c.Destinations.Webhook.DisableStatusCodes,
+	} {
+		if _, err := destwebhook.ParseStatusCodes(codes); err != nil {
+			return ErrInvalidStatusCodes
+		}
+	}
+
+	for _, cidr := range slices.Concat(c.Destinations.Egress.AllowCIDRs, c.Destinations.Egress.DenyCIDRs) {
+		if _, err := netip.ParsePrefix(cidr); err != nil {
+			return ErrInvalidEgressCIDR
+		}
+	}
+
+	if c.Destinations.Proxy.URL != "" {
+		proxyURL, err := url.Parse(c.Destinations.Proxy.URL)
+		if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+			return ErrInvalidProxyURL
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/alert"
+)
+
+// DeadLetterData represents the data sent with a dead-letter alert
+type DeadLetterData struct {
+	ID               string                 `json:"id"`
+	TenantID         string                 `json:"tenant_id"`
+	DestinationID    string                 `json:"destination_id"`
+	Attempts         int                    `json:"attempts"`
+	Event            alert.AlertedEvent     `json:"event"`
+	DeliveryResponse map[string]interface{} `json:"delivery_response"`
+}
+
+// DeadLetterAlert represents an alert for a delivery event that exhausted its retries
+type DeadLetterAlert struct {
+	Topic     string         `json:"topic"`
+	Timestamp time.Time      `json:"timestamp"`
+	Data      DeadLetterData `json:"data"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (a DeadLetterAlert) MarshalJSON() ([]byte, error) {
+	type Alias DeadLetterAlert
+	return json.Marshal(Alias(a))
+}
+
+// NewDeadLetterAlert creates a new dead-letter alert for the dead letter
+func NewDeadLetterAlert(deadLetter DeadLetter) DeadLetterAlert {
+	event := deadLetter.DeliveryEvent.Event
+	return DeadLetterAlert{
+		Topic:     "alert.dead_letter",
+		Timestamp: time.Now(),
+		Data: DeadLetterData{
+			ID:            deadLetter.ID,
+			TenantID:      deadLetter.TenantID,
+			DestinationID: deadLetter.DestinationID,
+			Attempts:      deadLetter.Attempts,
+			Event: alert.AlertedEvent{
+				ID:       event.ID,
+				Topic:    event.Topic,
+				Metadata: event.Metadata,
+				Data:     event.Data,
+			},
+			DeliveryResponse: deadLetter.Error,
+		},
+	}
+}
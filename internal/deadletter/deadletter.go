@@ -0,0 +1,300 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/alert"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+)
+
+const (
+	keyPrefixDeadLetter = "deadletter"
+	defaultListLimit    = 100
+	defaultRetention    = 30 * 24 * time.Hour
+	// defaultNotifyTimeout bounds the dead-letter alert, which is sent while
+	// the delivery is being handled.
+	defaultNotifyTimeout = 5 * time.Second
+)
+
+// DeadLetter is a delivery event that exhausted its retries.
+type DeadLetter struct {
+	// ID is the ID of the delivery event, which is stable across retries.
+	ID            string                 `json:"id"`
+	TenantID      string                 `json:"tenant_id"`
+	DestinationID string                 `json:"destination_id"`
+	EventID       string                 `json:"event_id"`
+	Attempts      int                    `json:"attempts"`
+	Provider      string                 `json:"provider"`
+	Error         map[string]interface{} `json:"error"`
+	DeliveryEvent models.DeliveryEvent   `json:"delivery_event"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// New creates a dead letter from the final delivery attempt of a delivery event.
+func New(deliveryEvent models.DeliveryEvent, provider string, errData map[string]interface{}) DeadLetter {
+	return DeadLetter{
+		ID:            deliveryEvent.ID,
+		TenantID:      deliveryEvent.Event.TenantID,
+		DestinationID: deliveryEvent.DestinationID,
+		EventID:       deliveryEvent.Event.ID,
+		Attempts:      deliveryEvent.Attempt + 1,
+		Provider:      provider,
+		Error:         errData,
+		DeliveryEvent: deliveryEvent,
+		CreatedAt:     time.Now(),
+	}
+}
+
+type ListRequest struct {
+	TenantID      string
+	DestinationID string
+	Next          string
+	Limit         int
+}
+
+type ListResponse struct {
+	Data  []DeadLetter
+	Next  string
+	Count int64
+}
+
+// Store persists dead letters.
+type Store interface {
+	Add(ctx context.Context, deadLetter DeadLetter) error
+	List(ctx context.Context, req ListRequest) (*ListResponse, error)
+	Retrieve(ctx context.Context, tenantID, id string) (*DeadLetter, error)
+	Delete(ctx context.Context, tenantID, id string) error
+	DeleteByDestination(ctx context.Context, tenantID, destinationID string) error
+}
+
+// Option configures a Store
+type Option func(s *redisStore)
+
+// WithNotifier sends an alert through the notifier whenever a dead letter is added.
+func WithNotifier(notifier alert.AlertNotifier) Option {
+	return func(s *redisStore) {
+		s.notifier = notifier
+	}
+}
+
+// WithRetention sets how long dead letters are kept before they expire.
+func WithRetention(retention time.Duration) Option {
+	return func(s *redisStore) {
+		if retention > 0 {
+			s.retention = retention
+		}
+	}
+}
+
+// WithLogger logs failures that don't prevent a dead letter from being stored.
+func WithLogger(logger *logging.Logger) Option {
+	return func(s *redisStore) {
+		s.logger = logger
+	}
+}
+
+type redisStore struct {
+	client        *redis.Client
+	notifier      alert.AlertNotifier
+	notifyTimeout time.Duration
+	retention     time.Duration
+	logger        *logging.Logger
+}
+
+// NewRedisStore creates a new Redis-backed dead-letter store
+func NewRedisStore(client *redis.Client, opts ...Option) Store {
+	s := &redisStore{client: client, notifyTimeout: defaultNotifyTimeout, retention: defaultRetention}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *redisStore) Add(ctx context.Context, deadLetter DeadLetter) error {
+	data, err := json.Marshal(deadLetter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	score := float64(deadLetter.CreatedAt.UnixMilli())
+	tenantIndexKey := s.tenantIndexKey(deadLetter.TenantID)
+	destinationIndexKey := s.destinationIndexKey(deadLetter.TenantID, deadLetter.DestinationID)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.entryKey(deadLetter.TenantID, deadLetter.ID), data, s.retention)
+		for _, indexKey := range []string{tenantIndexKey, destinationIndexKey} {
+			pipe.ZAdd(ctx, indexKey, redis.Z{Score: score, Member: deadLetter.ID})
+			s.trimIndex(ctx, pipe, indexKey)
+			pipe.Expire(ctx, indexKey, s.retention)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store dead letter: %w", err)
+	}
+
+	// The dead letter is stored at this point, so a failed notification
+	// shouldn't fail the delivery.
+	if s.notifier != nil {
+		notifyCtx, cancel := context.WithTimeout(ctx, s.notifyTimeout)
+		defer cancel()
+		if err := s.notifier.Notify(notifyCtx, NewDeadLetterAlert(deadLetter)); err != nil && s.logger != nil {
+			s.logger.Ctx(ctx).Error("failed to notify dead letter",
+				zap.Error(err),
+				zap.String("dead_letter_id", deadLetter.ID),
+				zap.String("tenant_id", deadLetter.TenantID),
+				zap.String("destination_id", deadLetter.DestinationID))
+		}
+	}
+	return nil
+}
+
+// trimIndex removes the index members of dead letters past the retention,
+// whose entries have already expired.
+func (s *redisStore) trimIndex(ctx context.Context, pipe redis.Pipeliner, indexKey string) {
+	cutoff := time.Now().Add(-s.retention).UnixMilli()
+	pipe.ZRemRangeByScore(ctx, indexKey, "-inf", fmt.Sprintf("(%d", cutoff))
+}
+
+func (s *redisStore) List(ctx context.Context, req ListRequest) (*ListResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	offset := 0
+	if req.Next != "" {
+		var err error
+		offset, err = strconv.Atoi(req.Next)
+		if err != nil || offset < 0 {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	indexKey := s.tenantIndexKey(req.TenantID)
+	if req.DestinationID != "" {
+		indexKey = s.destinationIndexKey(req.TenantID, req.DestinationID)
+	}
+
+	var countCmd *redis.IntCmd
+	if _, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		s.trimIndex(ctx, pipe, indexKey)
+		countCmd = pipe.ZCard(ctx, indexKey)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	count := countCmd.Val()
+	members, err := s.client.ZRevRange(ctx, indexKey, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(members))
+	for i, id := range members {
+		keys[i] = s.entryKey(req.TenantID, id)
+	}
+
+	deadLetters := make([]DeadLetter, 0, len(keys))
+	if len(keys) > 0 {
+		values, err := s.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			str, ok := value.(string)
+			if !ok {
+				continue
+			}
+			var deadLetter DeadLetter
+			if err := json.Unmarshal([]byte(str), &deadLetter); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+			}
+			deadLetters = append(deadLetters, deadLetter)
+		}
+	}
+
+	next := ""
+	if int64(offset+limit) < count {
+		next = strconv.Itoa(offset + limit)
+	}
+	return &ListResponse{
+		Data:  deadLetters,
+		Next:  next,
+		Count: count,
+	}, nil
+}
+
+func (s *redisStore) Retrieve(ctx context.Context, tenantID, id string) (*DeadLetter, error) {
+	data, err := s.client.Get(ctx, s.entryKey(tenantID, id)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var deadLetter DeadLetter
+	if err := json.Unmarshal([]byte(data), &deadLetter); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+	}
+	return &deadLetter, nil
+}
+
+func (s *redisStore) Delete(ctx context.Context, tenantID, id string) error {
+	deadLetter, err := s.Retrieve(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+	if deadLetter == nil {
+		return ErrDeadLetterNotFound
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.entryKey(tenantID, id))
+		pipe.ZRem(ctx, s.tenantIndexKey(tenantID), id)
+		pipe.ZRem(ctx, s.destinationIndexKey(tenantID, deadLetter.DestinationID), id)
+		return nil
+	})
+	return err
+}
+
+// DeleteByDestination discards all the dead letters of a destination, e.g.
+// when the destination is deleted.
+func (s *redisStore) DeleteByDestination(ctx context.Context, tenantID, destinationID string) error {
+	destinationIndexKey := s.destinationIndexKey(tenantID, destinationID)
+	ids, err := s.client.ZRange(ctx, destinationIndexKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			pipe.Del(ctx, s.entryKey(tenantID, id))
+			pipe.ZRem(ctx, s.tenantIndexKey(tenantID), id)
+		}
+		pipe.Del(ctx, destinationIndexKey)
+		return nil
+	})
+	return err
+}
+
+func (s *redisStore) entryKey(tenantID, id string) string {
+	return fmt.Sprintf("%s:%s:entry:%s", keyPrefixDeadLetter, tenantID, id)
+}
+
+func (s *redisStore) tenantIndexKey(tenantID string) string {
+	return fmt.Sprintf("%s:%s:index", keyPrefixDeadLetter, tenantID)
+}
+
+func (s *redisStore) destinationIndexKey(tenantID, destinationID string) string {
+	return fmt.Sprintf("%s:%s:destination:%s", keyPrefixDeadLetter, tenantID, destinationID)
+}
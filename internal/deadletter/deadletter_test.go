@@ -0,0 +1,260 @@
+package deadletter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/alert"
+	"github.com/hookdeck/outpost/internal/deadletter"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockNotifier struct {
+	alerts []alert.Alert
+	err    error
+}
+
+func (m *mockNotifier) Notify(ctx context.Context, a alert.Alert) error {
+	m.alerts = append(m.alerts, a)
+	return m.err
+}
+
+// blockingNotifier blocks until the context of the notification is done
+type blockingNotifier struct{}
+
+func (blockingNotifier) Notify(ctx context.Context, a alert.Alert) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func newDeadLetter(tenantID, destinationID string, createdAt time.Time) deadletter.DeadLetter {
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenantID),
+		testutil.EventFactory.WithDestinationID(destinationID),
+	)
+	deliveryEvent := models.NewDeliveryEvent(event, destinationID)
+	deliveryEvent.Attempt = 5
+	deadLetter := deadletter.New(deliveryEvent, "webhook", map[string]interface{}{
+		"error":   "publish_failed",
+		"message": "webhook returned 500",
+	})
+	deadLetter.CreatedAt = createdAt
+	return deadLetter
+}
+
+func TestRedisStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("add and retrieve", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		store := deadletter.NewRedisStore(testutil.CreateTestRedisClient(t))
+
+		deadLetter := newDeadLetter(uuid.New().String(), uuid.New().String(), time.Now())
+		require.NoError(t, store.Add(ctx, deadLetter))
+
+		retrieved, err := store.Retrieve(ctx, deadLetter.TenantID, deadLetter.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved)
+		assert.Equal(t, deadLetter.ID, retrieved.ID)
+		assert.Equal(t, 6, retrieved.Attempts)
+		assert.Equal(t, "webhook", retrieved.Provider)
+		assert.Equal(t, "publish_failed", retrieved.Error["error"])
+		assert.Equal(t, deadLetter.DeliveryEvent.Event.ID, retrieved.DeliveryEvent.Event.ID)
+
+		// Other tenants can't retrieve the dead letter
+		retrieved, err = store.Retrieve(ctx, uuid.New().String(), deadLetter.ID)
+		require.NoError(t, err)
+		assert.Nil(t, retrieved)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		store := deadletter.NewRedisStore(testutil.CreateTestRedisClient(t))
+
+		tenantID := uuid.New().String()
+		destinationID := uuid.New().String()
+		otherDestinationID := uuid.New().String()
+		now := time.Now()
+		deadLetters := []deadletter.DeadLetter{
+			newDeadLetter(tenantID, destinationID, now.Add(-3*time.Minute)),
+			newDeadLetter(tenantID, destinationID, now.Add(-2*time.Minute)),
+			newDeadLetter(tenantID, otherDestinationID, now.Add(-1*time.Minute)),
+		}
+		for _, deadLetter := range deadLetters {
+			require.NoError(t, store.Add(ctx, deadLetter))
+		}
+
+		response, err := store.List(ctx, deadletter.ListRequest{TenantID: tenantID, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), response.Count)
+		require.Len(t, response.Data, 2)
+		assert.Equal(t, deadLetters[2].ID, response.Data[0].ID, "should list newest first")
+		assert.Equal(t, deadLetters[1].ID, response.Data[1].ID)
+		require.NotEmpty(t, response.Next)
+
+		response, err = store.List(ctx, deadletter.ListRequest{TenantID: tenantID, Limit: 2, Next: response.Next})
+		require.NoError(t, err)
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, deadLetters[0].ID, response.Data[0].ID)
+		assert.Empty(t, response.Next)
+
+		response, err = store.List(ctx, deadletter.ListRequest{TenantID: tenantID, DestinationID: destinationID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), response.Count)
+		assert.Len(t, response.Data, 2)
+
+		_, err = store.List(ctx, deadletter.ListRequest{TenantID: tenantID, Next: "invalid"})
+		assert.ErrorIs(t, err, deadletter.ErrInvalidCursor)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		store := deadletter.NewRedisStore(testutil.CreateTestRedisClient(t))
+
+		deadLetter := newDeadLetter(uuid.New().String(), uuid.New().String(), time.Now())
+		require.NoError(t, store.Add(ctx, deadLetter))
+		require.NoError(t, store.Delete(ctx, deadLetter.TenantID, deadLetter.ID))
+
+		retrieved, err := store.Retrieve(ctx, deadLetter.TenantID, deadLetter.ID)
+		require.NoError(t, err)
+		assert.Nil(t, retrieved)
+
+		response, err := store.List(ctx, deadletter.ListRequest{TenantID: deadLetter.TenantID, DestinationID: deadLetter.DestinationID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), response.Count)
+		assert.Empty(t, response.Data)
+
+		err = store.Delete(ctx, deadLetter.TenantID, deadLetter.ID)
+		assert.ErrorIs(t, err, deadletter.ErrDeadLetterNotFound)
+	})
+
+	t.Run("notify", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		notifier := &mockNotifier{}
+		store := deadletter.NewRedisStore(testutil.CreateTestRedisClient(t), deadletter.WithNotifier(notifier))
+
+		deadLetter := newDeadLetter(uuid.New().String(), uuid.New().String(), time.Now())
+		require.NoError(t, store.Add(ctx, deadLetter))
+
+		require.Len(t, notifier.alerts, 1)
+		body, err := notifier.alerts[0].MarshalJSON()
+		require.NoError(t, err)
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "alert.dead_letter", payload["topic"])
+		data := payload["data"].(map[string]interface{})
+		assert.Equal(t, deadLetter.ID, data["id"])
+		assert.Equal(t, float64(6), data["attempts"])
+	})
+
+	t.Run("notify failure", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		notifier := &mockNotifier{err: errors.New("callback unavailable")}
+		store := deadletter.NewRedisStore(testutil.CreateTestRedisClient(t),
+			deadletter.WithNotifier(notifier),
+			deadletter.WithLogger(testutil.CreateTestLogger(t)),
+		)
+
+		deadLetter := newDeadLetter(uuid.New().String(), uuid.New().String(), time.Now())
+		require.NoError(t, store.Add(ctx, deadLetter), "notification failures should not fail the add")
+		assert.Len(t, notifier.alerts, 1)
+
+		retrieved, err := store.Retrieve(ctx, deadLetter.TenantID, deadLetter.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, retrieved)
+	})
+
+	t.Run("notify timeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		store := deadletter.NewRedisStore(testutil.CreateTestRedisClient(t),
+			deadletter.WithNotifier(blockingNotifier{}),
+			deadletter.WithNotifyTimeout(50*time.Millisecond),
+			deadletter.WithLogger(testutil.CreateTestLogger(t)),
+		)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- store.Add(ctx, newDeadLetter(uuid.New().String(), uuid.New().String(), time.Now()))
+		}()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("add should not wait on a hanging notification")
+		}
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		redisClient := testutil.CreateTestRedisClient(t)
+		store := deadletter.NewRedisStore(redisClient, deadletter.WithRetention(time.Hour))
+
+		tenantID := uuid.New().String()
+		destinationID := uuid.New().String()
+		expired := newDeadLetter(tenantID, destinationID, time.Now().Add(-2*time.Hour))
+		recent := newDeadLetter(tenantID, destinationID, time.Now())
+		require.NoError(t, store.Add(ctx, expired))
+		require.NoError(t, store.Add(ctx, recent))
+
+		for _, key := range []string{
+			"deadletter:" + tenantID + ":entry:" + recent.ID,
+			"deadletter:" + tenantID + ":index",
+			"deadletter:" + tenantID + ":destination:" + destinationID,
+		} {
+			ttl, err := redisClient.TTL(ctx, key).Result()
+			require.NoError(t, err)
+			assert.Equal(t, time.Hour, ttl, "key %s should expire with the retention", key)
+		}
+
+		for _, req := range []deadletter.ListRequest{
+			{TenantID: tenantID},
+			{TenantID: tenantID, DestinationID: destinationID},
+		} {
+			response, err := store.List(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), response.Count, "dead letters past the retention should be trimmed")
+			require.Len(t, response.Data, 1)
+			assert.Equal(t, recent.ID, response.Data[0].ID)
+		}
+	})
+
+	t.Run("delete by destination", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		store := deadletter.NewRedisStore(testutil.CreateTestRedisClient(t))
+
+		tenantID := uuid.New().String()
+		destinationID := uuid.New().String()
+		otherDestinationID := uuid.New().String()
+		require.NoError(t, store.Add(ctx, newDeadLetter(tenantID, destinationID, time.Now())))
+		require.NoError(t, store.Add(ctx, newDeadLetter(tenantID, destinationID, time.Now())))
+		other := newDeadLetter(tenantID, otherDestinationID, time.Now())
+		require.NoError(t, store.Add(ctx, other))
+
+		require.NoError(t, store.DeleteByDestination(ctx, tenantID, destinationID))
+
+		response, err := store.List(ctx, deadletter.ListRequest{TenantID: tenantID, DestinationID: destinationID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), response.Count)
+
+		response, err = store.List(ctx, deadletter.ListRequest{TenantID: tenantID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), response.Count, "other destinations should keep their dead letters")
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, other.ID, response.Data[0].ID)
+	})
+}
@@ -0,0 +1,10 @@
+package deadletter
+
+import "time"
+
+// WithNotifyTimeout overrides how long dead-letter alerts can take.
+func WithNotifyTimeout(timeout time.Duration) Option {
+	return func(s *redisStore) {
+		s.notifyTimeout = timeout
+	}
+}
@@ -9,11 +9,14 @@ import (
 	"github.com/hookdeck/outpost/internal/alert"
 	"github.com/hookdeck/outpost/internal/backoff"
 	"github.com/hookdeck/outpost/internal/consumer"
+	"github.com/hookdeck/outpost/internal/deadletter"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/idempotence"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/mqs"
+	"github.com/hookdeck/outpost/internal/ordering"
+	"github.com/hookdeck/outpost/internal/ratelimit"
 	"github.com/hookdeck/outpost/internal/redis"
 	"github.com/hookdeck/outpost/internal/scheduler"
 	"go.opentelemetry.io/otel/trace"
@@ -28,6 +31,8 @@ var (
 	errDestinationDisabled = errors.New("destination disabled")
 )
 
+const defaultMaxRetryAfter = time.Hour
+
 // Error types to distinguish between different stages of delivery
 type PreDeliveryError struct {
 	err error
@@ -66,19 +71,32 @@ func (e *PostDeliveryError) Unwrap() error {
 }
 
 type messageHandler struct {
-	eventTracer    DeliveryTracer
-	logger         *logging.Logger
-	logMQ          LogPublisher
-	entityStore    DestinationGetter
-	logStore       EventGetter
-	retryScheduler RetryScheduler
-	retryBackoff   backoff.Backoff
-	retryMaxLimit  int
-	idempotence    idempotence.Idempotence
-	publisher      Publisher
-	alertMonitor   AlertMonitor
+	eventTracer     DeliveryTracer
+	logger          *logging.Logger
+	logMQ           LogPublisher
+	entityStore     DestinationGetter
+	logStore        EventGetter
+	retryScheduler  RetryScheduler
+	retryBackoff    backoff.Backoff
+	retryMaxLimit   int
+	idempotence     idempotence.Idempotence
+	publisher       Publisher
+	alertMonitor    AlertMonitor
+	deadLetters     DeadLetterStore
+	rateLimiter     RateLimiter
+	orderingQueue   OrderingQueue
+	circuitBreaker  CircuitBreaker
+	maxRetryAfter   time.Duration
+	deliveryTimeout time.Duration
+	certMonitor     CertificateMonitor
+	batchSlots      chan struct{}
 }
 
+// maxBatchedDeliveries bounds the batched deliveries waiting for their batch
+// to be sent. Once reached, the consumer waits for a batch to be sent before
+// handing off more deliveries.
+const maxBatchedDeliveries = 10 * models.MaxBatchMaxSize
+
 type Publisher interface {
 	PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Delivery, error)
 }
@@ -108,6 +126,89 @@ type AlertMonitor interface {
 	HandleAttempt(ctx context.Context, attempt alert.DeliveryAttempt) error
 }
 
+type DeadLetterStore interface {
+	Add(ctx context.Context, deadLetter deadletter.DeadLetter) error
+}
+
+type RateLimiter interface {
+	Acquire(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Lease, time.Duration, error)
+}
+
+type OrderingQueue interface {
+	Acquire(ctx context.Context, group, key, id, payload string) (bool, string, error)
+	Release(ctx context.Context, group, key, id string) (string, error)
+}
+
+type CircuitBreaker interface {
+	Allow(ctx context.Context, tenantID, destinationID string) (bool, time.Duration, error)
+	RecordSuccess(ctx context.Context, tenantID, destinationID string) error
+	RecordFailure(ctx context.Context, tenantID, destinationID string) error
+}
+
+type CertificateMonitor interface {
+	Check(ctx context.Context, destination *models.Destination) error
+}
+
+// MessageHandlerOption configures optional dependencies of the message handler
+type MessageHandlerOption func(h *messageHandler)
+
+// WithDeadLetterStore records delivery events that exhausted their retries in the store.
+func WithDeadLetterStore(store DeadLetterStore) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.deadLetters = store
+	}
+}
+
+// WithRateLimiter enforces destination rate limits with the limiter.
+// Deliveries over the limit are deferred through the retry scheduler.
+func WithRateLimiter(limiter RateLimiter) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.rateLimiter = limiter
+	}
+}
+
+// WithOrderingQueue enables ordered delivery for destinations with an ordering key.
+// Deliveries are held in the queue while an earlier delivery with the same key
+// is pending or retrying.
+func WithOrderingQueue(queue OrderingQueue) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.orderingQueue = queue
+	}
+}
+
+// WithCircuitBreaker tracks destination failures with the circuit breaker.
+// While a destination's breaker is open, deliveries are deferred through the
+// retry scheduler without being attempted.
+func WithCircuitBreaker(breaker CircuitBreaker) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.circuitBreaker = breaker
+	}
+}
+
+// WithMaxRetryAfter caps the delay honored when a destination hints when to
+// retry, e.g. with a Retry-After header.
+func WithMaxRetryAfter(maxRetryAfter time.Duration) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.maxRetryAfter = maxRetryAfter
+	}
+}
+
+// WithDeliveryTimeout sets the default timeout of a delivery attempt, used to
+// size the in-flight slots of rate limited destinations.
+func WithDeliveryTimeout(timeout time.Duration) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.deliveryTimeout = timeout
+	}
+}
+
+// WithCertificateMonitor checks the destination's certificates after each
+// delivery attempt, e.g. to alert when they're about to expire.
+func WithCertificateMonitor(monitor CertificateMonitor) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.certMonitor = monitor
+	}
+}
+
 func NewMessageHandler(
 	logger *logging.Logger,
 	redisClient *redis.Client,
@@ -120,8 +221,9 @@ func NewMessageHandler(
 	retryBackoff backoff.Backoff,
 	retryMaxLimit int,
 	alertMonitor AlertMonitor,
+	opts ...MessageHandlerOption,
 ) consumer.MessageHandler {
-	return &messageHandler{
+	h := &messageHandler{
 		eventTracer:    eventTracer,
 		logger:         logger,
 		logMQ:          logMQ,
@@ -131,12 +233,28 @@ func NewMessageHandler(
 		retryScheduler: retryScheduler,
 		retryBackoff:   retryBackoff,
 		retryMaxLimit:  retryMaxLimit,
-		idempotence: idempotence.New(redisClient,
-			idempotence.WithTimeout(5*time.Second),
-			idempotence.WithSuccessfulTTL(24*time.Hour),
-		),
-		alertMonitor: alertMonitor,
+		alertMonitor:   alertMonitor,
+		maxRetryAfter:  defaultMaxRetryAfter,
+		batchSlots:     make(chan struct{}, maxBatchedDeliveries),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.idempotence = idempotence.New(redisClient,
+		idempotence.WithTimeout(h.idempotencyTimeout()),
+		idempotence.WithSuccessfulTTL(24*time.Hour),
+	)
+	return h
+}
+
+// idempotencyTimeout returns how long a delivery is marked as processing. It
+// covers the longest a delivery can take, including the time spent waiting
+// for its batch, so that a redelivered message isn't delivered twice.
+func (h *messageHandler) idempotencyTimeout() time.Duration {
+	if h.deliveryTimeout <= 0 {
+		return idempotence.DefaultTimeout + models.MaxBatchWindow
 	}
+	return max(idempotence.DefaultTimeout, h.deliveryTimeout+models.MaxBatchWindow)
 }
 
 func (h *messageHandler) Handle(ctx context.Context, msg *mqs.Message) error {
@@ -163,10 +281,75 @@ func (h *messageHandler) Handle(ctx context.Context, msg *mqs.Message) error {
 		return h.handleError(msg, &PreDeliveryError{err: err})
 	}
 
-	// Handle delivery
-	err = h.idempotence.Exec(ctx, idempotencyKeyFromDeliveryEvent(deliveryEvent), func(ctx context.Context) error {
+	// Enforce destination ordering
+	orderingKey, held, err := h.acquireOrdering(ctx, deliveryEvent, destination)
+	if err != nil {
+		return h.handleError(msg, &PreDeliveryError{err: err})
+	}
+	if held {
+		msg.Ack()
+		return nil
+	}
+
+	// Short-circuit deliveries to failing destinations
+	deferred, err := h.checkCircuitBreaker(ctx, deliveryEvent, destination)
+	if err != nil {
+		return h.handleError(msg, &PreDeliveryError{err: err})
+	}
+	if deferred {
+		msg.Ack()
+		return nil
+	}
+
+	// Enforce destination rate limit
+	lease, deferred, err := h.acquireRateLimit(ctx, deliveryEvent, destination)
+	if err != nil {
+		return h.handleError(msg, &PreDeliveryError{err: err})
+	}
+	if deferred {
+		msg.Ack()
+		return nil
+	}
+
+	// Batched deliveries wait for their batch to be sent, up to the batch
+	// window. They're handed off so that the consumer keeps receiving the other
+	// events of the batch instead of holding a worker per event. The message is
+	// acked or nacked once the batch is sent, so it's redelivered if the service
+	// stops in the meantime.
+	if !destination.Batch.IsZero() {
+		select {
+		case h.batchSlots <- struct{}{}:
+		case <-ctx.Done():
+			h.releaseRateLimit(ctx, deliveryEvent, lease)
+			msg.Nack()
+			return ctx.Err()
+		}
+		batchCtx := context.WithoutCancel(ctx)
+		go func() {
+			defer func() { <-h.batchSlots }()
+			if err := h.deliver(batchCtx, msg, deliveryEvent, destination, orderingKey, lease); err != nil {
+				h.logger.Ctx(batchCtx).Error("failed to handle batched delivery",
+					zap.Error(err),
+					zap.String("delivery_event_id", deliveryEvent.ID),
+					zap.String("destination_id", destination.ID))
+			}
+		}()
+		return nil
+	}
+
+	return h.deliver(ctx, msg, deliveryEvent, destination, orderingKey, lease)
+}
+
+// deliver publishes the delivery event once, then acks or nacks the message.
+func (h *messageHandler) deliver(ctx context.Context, msg *mqs.Message, deliveryEvent models.DeliveryEvent, destination *models.Destination, orderingKey string, lease *ratelimit.Lease) error {
+	defer h.releaseRateLimit(ctx, deliveryEvent, lease)
+
+	err := h.idempotence.Exec(ctx, idempotencyKeyFromDeliveryEvent(deliveryEvent), func(ctx context.Context) error {
 		return h.doHandle(ctx, deliveryEvent, destination)
 	})
+	if orderingKey != "" && h.shouldReleaseOrdering(deliveryEvent, destination, err) {
+		h.releaseOrdering(ctx, destination, orderingKey, deliveryEvent)
+	}
 	return h.handleError(msg, err)
 }
 
@@ -200,21 +383,25 @@ func (h *messageHandler) doHandle(ctx context.Context, deliveryEvent models.Deli
 			return &PreDeliveryError{err: err}
 		}
 
+		h.recordCircuitBreaker(ctx, deliveryEvent, err)
 		h.logger.Ctx(ctx).Error("failed to publish event",
 			zap.Error(err),
 			zap.String("delivery_event_id", deliveryEvent.ID),
 			zap.String("destination_id", destination.ID))
 		deliveryErr := &DeliveryError{err: err}
 
-		if h.shouldScheduleRetry(deliveryEvent, err) {
-			if retryErr := h.scheduleRetry(ctx, deliveryEvent); retryErr != nil {
+		if h.shouldScheduleRetry(deliveryEvent, destination, err) {
+			if retryErr := h.scheduleRetry(ctx, deliveryEvent, destination, err); retryErr != nil {
 				return h.logDeliveryResult(ctx, &deliveryEvent, destination, delivery, errors.Join(err, retryErr))
 			}
+		} else if h.shouldDeadLetter(deliveryEvent, err) {
+			h.deadLetter(ctx, deliveryEvent, delivery, err)
 		}
 		return h.logDeliveryResult(ctx, &deliveryEvent, destination, delivery, deliveryErr)
 	}
 
 	// Handle successful delivery
+	h.recordCircuitBreaker(ctx, deliveryEvent, nil)
 	if deliveryEvent.Manual {
 		logger := h.logger.Ctx(ctx)
 		if err := h.retryScheduler.Cancel(ctx, deliveryEvent.GetRetryID()); err != nil {
@@ -259,6 +446,9 @@ func (h *messageHandler) logDeliveryResult(ctx context.Context, deliveryEvent *m
 
 	// Call alert monitor in goroutine
 	go h.handleAlertAttempt(ctx, deliveryEvent, destination, err)
+	if h.certMonitor != nil {
+		go h.checkCertificate(ctx, destination)
+	}
 
 	// If we have a DeliveryError, return it as is
 	var delErr *DeliveryError
@@ -303,6 +493,7 @@ func (h *messageHandler) handleAlertAttempt(ctx context.Context, deliveryEvent *
 			var pubErr *destregistry.ErrDestinationPublishAttempt
 			if errors.As(delErr.err, &pubErr) {
 				attempt.DeliveryResponse = pubErr.Data
+				attempt.DisableDestination = pubErr.DisableDestination
 			} else {
 				attempt.DeliveryResponse = map[string]interface{}{
 					"error": delErr.err.Error(),
@@ -329,18 +520,260 @@ func (h *messageHandler) handleAlertAttempt(ctx context.Context, deliveryEvent *
 		zap.String("destination_id", destination.ID))
 }
 
-func (h *messageHandler) shouldScheduleRetry(deliveryEvent models.DeliveryEvent, err error) bool {
+func (h *messageHandler) checkCertificate(ctx context.Context, destination *models.Destination) {
+	if err := h.certMonitor.Check(ctx, destination); err != nil {
+		h.logger.Ctx(ctx).Error("failed to check destination certificate",
+			zap.Error(err),
+			zap.String("destination_id", destination.ID))
+	}
+}
+
+func (h *messageHandler) shouldScheduleRetry(deliveryEvent models.DeliveryEvent, destination *models.Destination, err error) bool {
 	if deliveryEvent.Manual {
 		return false
 	}
 	if !deliveryEvent.Event.EligibleForRetry {
 		return false
 	}
-	if _, ok := err.(*destregistry.ErrDestinationPublishAttempt); !ok {
+	pubErr, ok := err.(*destregistry.ErrDestinationPublishAttempt)
+	if !ok {
 		return false
 	}
+	// The destination classified the failure as permanent
+	if pubErr.NonRetryable || pubErr.DisableDestination {
+		return false
+	}
+	retryMaxLimit := h.retryMaxLimit
+	if maxAttempts, ok := destination.RetryPolicy.ResolvedMaxAttempts(); ok {
+		retryMaxLimit = maxAttempts
+	}
 	// Attempt starts at 0 for initial attempt, so we can compare directly
-	return deliveryEvent.Attempt < h.retryMaxLimit
+	return deliveryEvent.Attempt < retryMaxLimit
+}
+
+// acquireOrdering makes the delivery the head of its ordering key. When an
+// earlier delivery with the same key is pending or retrying, the delivery is
+// held until the earlier one is released and held is true.
+func (h *messageHandler) acquireOrdering(ctx context.Context, deliveryEvent models.DeliveryEvent, destination *models.Destination) (string, bool, error) {
+	if h.orderingQueue == nil || destination.OrderingKey == "" {
+		return "", false, nil
+	}
+	orderingValue := deliveryEvent.Event.Metadata[destination.OrderingKey]
+	if orderingValue == "" {
+		return "", false, nil
+	}
+	heldMessage := DeferredMessageFromDeliveryEvent(deliveryEvent)
+	heldMessageStr, err := heldMessage.ToString()
+	if err != nil {
+		return "", false, err
+	}
+	acquired, next, err := h.orderingQueue.Acquire(ctx, ordering.DestinationGroup(destination.TenantID, destination.ID), orderingValue, deliveryEvent.ID, heldMessageStr)
+	if err != nil {
+		return "", false, err
+	}
+	if acquired {
+		return orderingValue, false, nil
+	}
+
+	h.logger.Ctx(ctx).Info("delivery held by ordering",
+		zap.String("delivery_event_id", deliveryEvent.ID),
+		zap.String("destination_id", destination.ID),
+		zap.String("ordering_key", orderingValue))
+	if next != "" {
+		if err := h.retryScheduler.Schedule(ctx, next, 0); err != nil {
+			return "", false, err
+		}
+	}
+	return orderingValue, true, nil
+}
+
+// shouldReleaseOrdering returns true once the delivery won't be attempted again,
+// either because it succeeded or because it failed without a retry.
+func (h *messageHandler) shouldReleaseOrdering(deliveryEvent models.DeliveryEvent, destination *models.Destination, err error) bool {
+	if err == nil {
+		return true
+	}
+	if h.shouldNackError(err) {
+		return false
+	}
+	var pubErr *destregistry.ErrDestinationPublishAttempt
+	if errors.As(err, &pubErr) && h.shouldScheduleRetry(deliveryEvent, destination, pubErr) {
+		return false
+	}
+	return true
+}
+
+// releaseOrdering releases the ordering key and dispatches the next held delivery.
+func (h *messageHandler) releaseOrdering(ctx context.Context, destination *models.Destination, orderingKey string, deliveryEvent models.DeliveryEvent) {
+	ctx = context.WithoutCancel(ctx)
+	next, err := h.orderingQueue.Release(ctx, ordering.DestinationGroup(destination.TenantID, destination.ID), orderingKey, deliveryEvent.ID)
+	if err == nil && next != "" {
+		err = h.retryScheduler.Schedule(ctx, next, 0)
+	}
+	if err != nil {
+		h.logger.Ctx(ctx).Error("failed to release ordering",
+			zap.Error(err),
+			zap.String("delivery_event_id", deliveryEvent.ID),
+			zap.String("destination_id", deliveryEvent.DestinationID))
+	}
+}
+
+// acquireRateLimit reserves a delivery slot for the destination. When the
+// destination is over its limit, the delivery is deferred through the retry
+// scheduler without counting as an attempt and deferred is true.
+func (h *messageHandler) acquireRateLimit(ctx context.Context, deliveryEvent models.DeliveryEvent, destination *models.Destination) (*ratelimit.Lease, bool, error) {
+	if h.rateLimiter == nil || destination.RateLimit.IsZero() {
+		return nil, false, nil
+	}
+
+	// Destination IDs are only unique within a tenant
+	lease, wait, err := h.rateLimiter.Acquire(ctx, destination.TenantID+":"+destination.ID, ratelimit.Limit{
+		MaxRequestsPerSecond: destination.RateLimit.MaxRequestsPerSecond,
+		MaxInFlight:          destination.RateLimit.MaxInFlight,
+		LeaseTTL:             h.rateLimitLeaseTTL(destination),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if lease != nil {
+		return lease, false, nil
+	}
+
+	deferredMessage := DeferredMessageFromDeliveryEvent(deliveryEvent)
+	deferredMessageStr, err := deferredMessage.ToString()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := h.retryScheduler.Schedule(ctx, deferredMessageStr, wait); err != nil {
+		return nil, false, err
+	}
+
+	h.logger.Ctx(ctx).Info("delivery deferred by rate limit",
+		zap.String("delivery_event_id", deliveryEvent.ID),
+		zap.String("destination_id", destination.ID),
+		zap.Duration("wait", wait))
+	return nil, true, nil
+}
+
+// rateLimitLeaseTTL returns how long an in-flight slot is held if it's never
+// released. It's twice the longest a delivery to the destination can take,
+// including the time spent waiting for its batch. Zero falls back to the
+// lease TTL of the limiter.
+func (h *messageHandler) rateLimitLeaseTTL(destination *models.Destination) time.Duration {
+	timeout := h.deliveryTimeout
+	if destination.RetryPolicy != nil && destination.RetryPolicy.DeliveryTimeout > 0 {
+		timeout = time.Duration(destination.RetryPolicy.DeliveryTimeout)
+	}
+	if timeout <= 0 {
+		return 0
+	}
+	if !destination.Batch.IsZero() {
+		timeout += destination.Batch.ResolvedWindow()
+	}
+	return 2 * timeout
+}
+
+func (h *messageHandler) releaseRateLimit(ctx context.Context, deliveryEvent models.DeliveryEvent, lease *ratelimit.Lease) {
+	if err := lease.Release(context.WithoutCancel(ctx)); err != nil {
+		h.logger.Ctx(ctx).Error("failed to release rate limit",
+			zap.Error(err),
+			zap.String("delivery_event_id", deliveryEvent.ID),
+			zap.String("destination_id", deliveryEvent.DestinationID))
+	}
+}
+
+// checkCircuitBreaker defers the delivery through the retry scheduler when the
+// destination's circuit breaker rejects it, without counting as an attempt.
+// Manual retries bypass the breaker so that tenants can test a destination.
+func (h *messageHandler) checkCircuitBreaker(ctx context.Context, deliveryEvent models.DeliveryEvent, destination *models.Destination) (bool, error) {
+	if h.circuitBreaker == nil || deliveryEvent.Manual {
+		return false, nil
+	}
+
+	allowed, wait, err := h.circuitBreaker.Allow(ctx, destination.TenantID, destination.ID)
+	if err != nil {
+		return false, err
+	}
+	if allowed {
+		return false, nil
+	}
+
+	deferredMessage := DeferredMessageFromDeliveryEvent(deliveryEvent)
+	deferredMessageStr, err := deferredMessage.ToString()
+	if err != nil {
+		return false, err
+	}
+	if err := h.retryScheduler.Schedule(ctx, deferredMessageStr, wait); err != nil {
+		return false, err
+	}
+
+	h.logger.Ctx(ctx).Info("delivery deferred by circuit breaker",
+		zap.String("delivery_event_id", deliveryEvent.ID),
+		zap.String("destination_id", destination.ID),
+		zap.Duration("wait", wait))
+	return true, nil
+}
+
+// recordCircuitBreaker records the outcome of a delivery attempt, where a nil
+// publishErr is a success. Failures that aren't endpoint failures are skipped
+// as they say nothing about the health of the destination.
+func (h *messageHandler) recordCircuitBreaker(ctx context.Context, deliveryEvent models.DeliveryEvent, publishErr error) {
+	if h.circuitBreaker == nil {
+		return
+	}
+
+	var err error
+	if publishErr == nil {
+		err = h.circuitBreaker.RecordSuccess(ctx, deliveryEvent.Event.TenantID, deliveryEvent.DestinationID)
+	} else if !isEndpointFailure(publishErr) {
+		return
+	} else {
+		err = h.circuitBreaker.RecordFailure(ctx, deliveryEvent.Event.TenantID, deliveryEvent.DestinationID)
+	}
+	if err != nil {
+		h.logger.Ctx(ctx).Error("failed to record circuit breaker outcome",
+			zap.Error(err),
+			zap.String("delivery_event_id", deliveryEvent.ID),
+			zap.String("destination_id", deliveryEvent.DestinationID))
+	}
+}
+
+// isEndpointFailure returns false for failures that retrying won't fix, e.g. a
+// rejected payload, a destination that's gone or a transformation failure.
+// They're handled by not retrying and by the alert monitor instead.
+func isEndpointFailure(err error) bool {
+	var pubErr *destregistry.ErrDestinationPublishAttempt
+	if !errors.As(err, &pubErr) {
+		return true
+	}
+	return !pubErr.NonRetryable && !pubErr.DisableDestination &&
+		!errors.Is(pubErr.Err, destregistry.ErrTransformationFailed)
+}
+
+// shouldDeadLetter returns true if the delivery event failed its last automatic attempt.
+// Manual retries are never dead-lettered as they're initiated on demand.
+func (h *messageHandler) shouldDeadLetter(deliveryEvent models.DeliveryEvent, err error) bool {
+	if h.deadLetters == nil || deliveryEvent.Manual {
+		return false
+	}
+	_, ok := err.(*destregistry.ErrDestinationPublishAttempt)
+	return ok
+}
+
+func (h *messageHandler) deadLetter(ctx context.Context, deliveryEvent models.DeliveryEvent, delivery *models.Delivery, err error) {
+	pubErr := err.(*destregistry.ErrDestinationPublishAttempt)
+	deliveryEvent.Delivery = delivery
+	if err := h.deadLetters.Add(ctx, deadletter.New(deliveryEvent, pubErr.Provider, pubErr.Data)); err != nil {
+		h.logger.Ctx(ctx).Error("failed to dead-letter delivery event",
+			zap.Error(err),
+			zap.String("delivery_event_id", deliveryEvent.ID),
+			zap.String("destination_id", deliveryEvent.DestinationID))
+		return
+	}
+	h.logger.Ctx(ctx).Audit("delivery event dead-lettered",
+		zap.String("delivery_event_id", deliveryEvent.ID),
+		zap.String("destination_id", deliveryEvent.DestinationID),
+		zap.Int("attempt", deliveryEvent.Attempt))
 }
 
 func (h *messageHandler) shouldNackError(err error) bool {
@@ -387,8 +820,14 @@ func (h *messageHandler) shouldNackDeliveryError(err error) bool {
 	return true // Nack other delivery errors
 }
 
-func (h *messageHandler) scheduleRetry(ctx context.Context, deliveryEvent models.DeliveryEvent) error {
-	backoffDuration := h.retryBackoff.Duration(deliveryEvent.Attempt)
+func (h *messageHandler) scheduleRetry(ctx context.Context, deliveryEvent models.DeliveryEvent, destination *models.Destination, publishErr error) error {
+	backoffDuration := retryBackoffFromPolicy(destination.RetryPolicy, h.retryBackoff).Duration(deliveryEvent.Attempt)
+	backoffDuration = retryDelay(backoffDuration, publishErr, h.maxRetryAfter)
+	if h.orderingQueue != nil && destination.OrderingKey != "" {
+		// Later deliveries are held until this one is retried. The global
+		// backoff and Retry-After aren't validated against the limit.
+		backoffDuration = min(backoffDuration, models.MaxOrderedRetryInterval)
+	}
 
 	retryMessage := RetryMessageFromDeliveryEvent(deliveryEvent)
 	retryMessageStr, err := retryMessage.ToString()
@@ -467,5 +906,11 @@ func (h *messageHandler) ensurePublishableDestination(ctx context.Context, deliv
 			zap.Time("disabled_at", *destination.DisabledAt))
 		return nil, errDestinationDisabled
 	}
+	if destination.PendingVerification() {
+		h.logger.Ctx(ctx).Info("skipping destination pending verification",
+			zap.String("destination_id", destination.ID),
+			zap.String("destination_type", destination.Type))
+		return nil, errDestinationDisabled
+	}
 	return destination, nil
 }
@@ -3,15 +3,18 @@ package deliverymq_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hookdeck/outpost/internal/alert"
 	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/circuitbreaker"
 	"github.com/hookdeck/outpost/internal/deliverymq"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/ordering"
 	"github.com/hookdeck/outpost/internal/util/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -279,6 +282,612 @@ func TestMessageHandler_PublishError_EligibleForRetry(t *testing.T) {
 	assertAlertMonitor(t, alertMonitor, false, &destination, publishErr.Data)
 }
 
+func TestMessageHandler_PublishError_DestinationRetryPolicy(t *testing.T) {
+	// Test scenario:
+	// - Destination has a retry policy with a schedule of 2 retries
+	// - Publish fails on each attempt
+	// - Should schedule retries using the destination schedule and stop once
+	//   the schedule is exhausted, regardless of the global max limit
+	t.Parallel()
+
+	tenant := models.Tenant{ID: uuid.New().String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	destination.RetryPolicy = &models.RetryPolicy{
+		Schedule: []models.Duration{
+			models.Duration(time.Minute),
+			models.Duration(5 * time.Minute),
+		},
+	}
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+		testutil.EventFactory.WithEligibleForRetry(true),
+	)
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	eventGetter := newMockEventGetter()
+	eventGetter.registerEvent(&event)
+	retryScheduler := newMockRetryScheduler()
+	publishErr := &destregistry.ErrDestinationPublishAttempt{
+		Err:      errors.New("webhook returned 500"),
+		Provider: "webhook",
+		Data: map[string]interface{}{
+			"error":   "publish_failed",
+			"message": "webhook returned 500",
+		},
+	}
+	publisher := newMockPublisher([]error{publishErr, publishErr, publishErr})
+	logPublisher := newMockLogPublisher(nil)
+	alertMonitor := newMockAlertMonitor()
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		testutil.CreateTestRedisClient(t),
+		logPublisher,
+		destGetter,
+		eventGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		alertMonitor,
+	)
+
+	deliveryEvent := models.DeliveryEvent{
+		ID:            uuid.New().String(),
+		Event:         event,
+		DestinationID: destination.ID,
+	}
+	for attempt := 0; attempt < 3; attempt++ {
+		deliveryEvent.Attempt = attempt
+		_, msg := newDeliveryMockMessage(deliveryEvent)
+		_ = handler.Handle(context.Background(), msg)
+	}
+
+	assert.Equal(t, 3, publisher.Current(), "should attempt delivery 3 times")
+	assert.Equal(t, []time.Duration{time.Minute, 5 * time.Minute}, retryScheduler.delays,
+		"should schedule retries using the destination schedule")
+}
+
+func TestMessageHandler_PublishError_RetryAfter(t *testing.T) {
+	// Test scenario:
+	// - Publish fails with a hint of when to retry, e.g. a Retry-After header
+	// - Should schedule the retry no earlier than the hint, up to the max
+	// - Hints shorter than the backoff should not shorten it
+	t.Parallel()
+
+	tenant := models.Tenant{ID: uuid.New().String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	newPublishErr := func(retryAfter time.Duration) error {
+		return destregistry.NewErrDestinationPublishAttemptWithRetryAfter(
+			errors.New("webhook returned 429"),
+			"webhook",
+			map[string]interface{}{"status": 429},
+			retryAfter,
+		)
+	}
+
+	retryScheduler := newMockRetryScheduler()
+	publisher := newMockPublisher([]error{
+		newPublishErr(30 * time.Second),
+		newPublishErr(2 * time.Hour),
+		newPublishErr(500 * time.Millisecond),
+	})
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		testutil.CreateTestRedisClient(t),
+		newMockLogPublisher(nil),
+		&mockDestinationGetter{dest: &destination},
+		newMockEventGetter(),
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		newMockAlertMonitor(),
+		deliverymq.WithMaxRetryAfter(time.Hour),
+	)
+
+	for i := 0; i < 3; i++ {
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+			testutil.EventFactory.WithEligibleForRetry(true),
+		)
+		_, msg := newDeliveryMockMessage(models.NewDeliveryEvent(event, destination.ID))
+		_ = handler.Handle(context.Background(), msg)
+	}
+
+	assert.Equal(t, 3, publisher.Current())
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Hour, time.Second}, retryScheduler.delays)
+}
+
+func TestMessageHandler_PublishError_NonRetryable(t *testing.T) {
+	// Test scenario:
+	// - Publish fails with a response classified as non-retryable or as
+	//   disabling the destination, e.g. a 410 Gone
+	// - Should not schedule retries even though the event is eligible
+	// - Should pass the disable flag on to the alert monitor
+	t.Parallel()
+
+	tenant := models.Tenant{ID: uuid.New().String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+
+	retryScheduler := newMockRetryScheduler()
+	publisher := newMockPublisher([]error{
+		&destregistry.ErrDestinationPublishAttempt{
+			Err:          errors.New("webhook returned 422"),
+			Provider:     "webhook",
+			Data:         map[string]interface{}{"status": 422},
+			NonRetryable: true,
+		},
+		&destregistry.ErrDestinationPublishAttempt{
+			Err:                errors.New("webhook returned 410"),
+			Provider:           "webhook",
+			Data:               map[string]interface{}{"status": 410},
+			DisableDestination: true,
+		},
+	})
+	alertMonitor := newMockAlertMonitor()
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		testutil.CreateTestRedisClient(t),
+		newMockLogPublisher(nil),
+		&mockDestinationGetter{dest: &destination},
+		newMockEventGetter(),
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		alertMonitor,
+	)
+
+	for i := 0; i < 2; i++ {
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+			testutil.EventFactory.WithEligibleForRetry(true),
+		)
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryEvent(event, destination.ID))
+		_ = handler.Handle(context.Background(), msg)
+		assert.True(t, mockMsg.acked, "message should be acked")
+	}
+
+	assert.Equal(t, 2, publisher.Current())
+	assert.Empty(t, retryScheduler.schedules, "no retry should be scheduled")
+	alertMonitor.AssertCalled(t, "HandleAttempt", mock.Anything, mock.MatchedBy(func(attempt alert.DeliveryAttempt) bool {
+		return attempt.DisableDestination
+	}))
+}
+
+func TestMessageHandler_PublishError_DeadLetter(t *testing.T) {
+	// Test scenario:
+	// - Publish fails on the last attempt
+	// - Should not schedule a retry and should record a dead letter
+	// - A failed manual retry should not be dead-lettered
+	t.Parallel()
+
+	tenant := models.Tenant{ID: uuid.New().String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+		testutil.EventFactory.WithEligibleForRetry(true),
+	)
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	eventGetter := newMockEventGetter()
+	eventGetter.registerEvent(&event)
+	retryScheduler := newMockRetryScheduler()
+	publishErr := &destregistry.ErrDestinationPublishAttempt{
+		Err:      errors.New("webhook returned 500"),
+		Provider: "webhook",
+		Data: map[string]interface{}{
+			"error":   "publish_failed",
+			"message": "webhook returned 500",
+		},
+	}
+	publisher := newMockPublisher([]error{publishErr, publishErr})
+	logPublisher := newMockLogPublisher(nil)
+	deadLetters := &mockDeadLetterStore{}
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		testutil.CreateTestRedisClient(t),
+		logPublisher,
+		destGetter,
+		eventGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		2,
+		newMockAlertMonitor(),
+		deliverymq.WithDeadLetterStore(deadLetters),
+	)
+
+	deliveryEvent := models.DeliveryEvent{
+		ID:            uuid.New().String(),
+		Attempt:       2,
+		Event:         event,
+		DestinationID: destination.ID,
+	}
+	mockMsg, msg := newDeliveryMockMessage(deliveryEvent)
+	_ = handler.Handle(context.Background(), msg)
+
+	assert.True(t, mockMsg.acked, "message should be acked when dead-lettered")
+	assert.Empty(t, retryScheduler.schedules, "no retry should be scheduled")
+	require.Len(t, deadLetters.deadLetters, 1, "should record a dead letter")
+	deadLetter := deadLetters.deadLetters[0]
+	assert.Equal(t, deliveryEvent.ID, deadLetter.ID)
+	assert.Equal(t, tenant.ID, deadLetter.TenantID)
+	assert.Equal(t, destination.ID, deadLetter.DestinationID)
+	assert.Equal(t, event.ID, deadLetter.EventID)
+	assert.Equal(t, 3, deadLetter.Attempts)
+	assert.Equal(t, "webhook", deadLetter.Provider)
+	assert.Equal(t, publishErr.Data, deadLetter.Error)
+	assert.NotNil(t, deadLetter.DeliveryEvent.Delivery)
+
+	manualDeliveryEvent := models.NewManualDeliveryEvent(event, destination.ID)
+	_, msg = newDeliveryMockMessage(manualDeliveryEvent)
+	_ = handler.Handle(context.Background(), msg)
+	assert.Len(t, deadLetters.deadLetters, 1, "manual retries should not be dead-lettered")
+}
+
+func TestMessageHandler_RateLimited(t *testing.T) {
+	// Test scenario:
+	// - Destination has a rate limit that's reached
+	// - Should defer the delivery through the scheduler without publishing
+	// - The deferred message should keep the attempt and carry the full event
+	t.Parallel()
+
+	tenant := models.Tenant{ID: uuid.New().String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	destination.RateLimit = &models.RateLimit{MaxRequestsPerSecond: 10, MaxInFlight: 2}
+	destination.RetryPolicy = &models.RetryPolicy{DeliveryTimeout: models.Duration(time.Minute)}
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	eventGetter := newMockEventGetter()
+	eventGetter.registerEvent(&event)
+	retryScheduler := newMockRetryScheduler()
+	publisher := newMockPublisher(nil)
+	logPublisher := newMockLogPublisher(nil)
+	rateLimiter := &mockRateLimiter{wait: 300 * time.Millisecond}
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		testutil.CreateTestRedisClient(t),
+		logPublisher,
+		destGetter,
+		eventGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		newMockAlertMonitor(),
+		deliverymq.WithRateLimiter(rateLimiter),
+		deliverymq.WithDeliveryTimeout(5*time.Second),
+	)
+
+	deliveryEvent := models.DeliveryEvent{
+		ID:            uuid.New().String(),
+		Attempt:       1,
+		Event:         event,
+		DestinationID: destination.ID,
+	}
+	mockMsg, msg := newDeliveryMockMessage(deliveryEvent)
+	err := handler.Handle(context.Background(), msg)
+	require.NoError(t, err)
+
+	assert.True(t, mockMsg.acked, "deferred message should be acked")
+	assert.Equal(t, 0, publisher.Current(), "should not publish when rate limited")
+	assert.Empty(t, logPublisher.deliveries, "should not log a delivery when rate limited")
+	require.Len(t, rateLimiter.limits, 1)
+	assert.Equal(t, 10, rateLimiter.limits[0].MaxRequestsPerSecond)
+	assert.Equal(t, 2, rateLimiter.limits[0].MaxInFlight)
+	assert.Equal(t, 2*time.Minute, rateLimiter.limits[0].LeaseTTL, "lease should outlast the destination delivery timeout")
+	assert.Equal(t, tenant.ID+":"+destination.ID, rateLimiter.keys[0], "limits should be scoped to the tenant")
+	require.Len(t, retryScheduler.schedules, 1, "should defer the delivery")
+	assert.Equal(t, 300*time.Millisecond, retryScheduler.delays[0])
+
+	var deferred deliverymq.RetryMessage
+	require.NoError(t, deferred.FromString(retryScheduler.schedules[0]))
+	deferredEvent := deferred.ToDeliveryEvent()
+	assert.Equal(t, deliveryEvent.ID, deferredEvent.ID)
+	assert.Equal(t, 1, deferredEvent.Attempt, "deferral should not count as an attempt")
+	assert.Equal(t, event.Topic, deferredEvent.Event.Topic)
+
+	// Once the limit allows it, the deferred delivery is published
+	rateLimiter.wait = 0
+	_, msg = newDeliveryMockMessage(deferredEvent)
+	err = handler.Handle(context.Background(), msg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, publisher.Current(), "should publish once allowed")
+}
+
+func TestMessageHandler_Ordering(t *testing.T) {
+	// Test scenario:
+	// - Destination delivers in order by the "customer_id" metadata
+	// - First event fails and schedules a retry, capped to keep the head alive
+	// - Second event for the same customer is held, another customer isn't
+	// - Once the first event's retry succeeds, the second event is dispatched
+	t.Parallel()
+
+	tenant := models.Tenant{ID: uuid.New().String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	destination.OrderingKey = "customer_id"
+	newEvent := func(customerID string) models.Event {
+		return testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+			testutil.EventFactory.WithEligibleForRetry(true),
+			testutil.EventFactory.WithMetadata(map[string]string{"customer_id": customerID}),
+		)
+	}
+	first := newEvent("cus_1")
+	second := newEvent("cus_1")
+	other := newEvent("cus_2")
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	eventGetter := newMockEventGetter()
+	retryScheduler := newMockRetryScheduler()
+	publishErr := &destregistry.ErrDestinationPublishAttempt{
+		Err:      errors.New("webhook returned 500"),
+		Provider: "webhook",
+		Data: map[string]interface{}{
+			"error":   "publish_failed",
+			"message": "webhook returned 500",
+		},
+	}
+	publisher := newMockPublisher([]error{publishErr})
+
+	redisClient := testutil.CreateTestRedisClient(t)
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		redisClient,
+		newMockLogPublisher(nil),
+		destGetter,
+		eventGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 48 * time.Hour},
+		10,
+		newMockAlertMonitor(),
+		deliverymq.WithOrderingQueue(ordering.NewRedisQueue(redisClient)),
+	)
+	handle := func(deliveryEvent models.DeliveryEvent) {
+		_, msg := newDeliveryMockMessage(deliveryEvent)
+		_ = handler.Handle(context.Background(), msg)
+	}
+
+	firstDeliveryEvent := models.NewDeliveryEvent(first, destination.ID)
+	handle(firstDeliveryEvent)
+	assert.Equal(t, 1, publisher.Current(), "first event should be attempted")
+	require.Len(t, retryScheduler.schedules, 1, "first event should schedule a retry")
+	assert.Equal(t, models.MaxOrderedRetryInterval, retryScheduler.delays[0], "retry should be scheduled before the head expires")
+
+	handle(models.NewDeliveryEvent(second, destination.ID))
+	assert.Equal(t, 1, publisher.Current(), "second event should be held behind the first")
+	assert.Len(t, retryScheduler.schedules, 1)
+
+	handle(models.NewDeliveryEvent(other, destination.ID))
+	assert.Equal(t, 2, publisher.Current(), "other ordering keys should not be held")
+
+	// Retry the first event, which now succeeds
+	firstDeliveryEvent.Attempt = 1
+	handle(firstDeliveryEvent)
+	assert.Equal(t, 3, publisher.Current())
+	require.Len(t, retryScheduler.schedules, 2, "second event should be dispatched")
+	assert.Equal(t, time.Duration(0), retryScheduler.delays[1])
+
+	var dispatched deliverymq.RetryMessage
+	require.NoError(t, dispatched.FromString(retryScheduler.schedules[1]))
+	assert.Equal(t, second.ID, dispatched.EventID)
+
+	handle(dispatched.ToDeliveryEvent())
+	assert.Equal(t, 4, publisher.Current(), "dispatched event should be delivered")
+}
+
+func TestMessageHandler_CircuitBreaker(t *testing.T) {
+	// Test scenario:
+	// - Destination fails enough times to open its circuit breaker
+	// - Next delivery should be deferred through the scheduler without publishing
+	// - Manual retries should bypass the open breaker and close it on success
+	t.Parallel()
+
+	tenant := models.Tenant{ID: uuid.New().String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	newEvent := func() models.Event {
+		return testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+			testutil.EventFactory.WithEligibleForRetry(false),
+		)
+	}
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	eventGetter := newMockEventGetter()
+	retryScheduler := newMockRetryScheduler()
+	publishErr := &destregistry.ErrDestinationPublishAttempt{
+		Err:      errors.New("webhook returned 500"),
+		Provider: "webhook",
+		Data: map[string]interface{}{
+			"error":   "publish_failed",
+			"message": "webhook returned 500",
+		},
+	}
+	publisher := newMockPublisher([]error{publishErr, publishErr})
+
+	redisClient := testutil.CreateTestRedisClient(t)
+	breaker := circuitbreaker.NewRedisCircuitBreaker(redisClient,
+		circuitbreaker.WithFailureThreshold(2),
+		circuitbreaker.WithOpenTimeout(time.Minute),
+	)
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		redisClient,
+		newMockLogPublisher(nil),
+		destGetter,
+		eventGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		newMockAlertMonitor(),
+		deliverymq.WithCircuitBreaker(breaker),
+	)
+	handle := func(deliveryEvent models.DeliveryEvent) *mockMessage {
+		mockMsg, msg := newDeliveryMockMessage(deliveryEvent)
+		_ = handler.Handle(context.Background(), msg)
+		return mockMsg
+	}
+
+	handle(models.NewDeliveryEvent(newEvent(), destination.ID))
+	handle(models.NewDeliveryEvent(newEvent(), destination.ID))
+	assert.Equal(t, 2, publisher.Current())
+
+	status, err := breaker.Status(context.Background(), tenant.ID, destination.ID)
+	require.NoError(t, err)
+	assert.Equal(t, circuitbreaker.StateOpen, status.State)
+
+	deliveryEvent := models.NewDeliveryEvent(newEvent(), destination.ID)
+	mockMsg := handle(deliveryEvent)
+	assert.True(t, mockMsg.acked, "deferred message should be acked")
+	assert.Equal(t, 2, publisher.Current(), "should not publish while the breaker is open")
+	require.Len(t, retryScheduler.schedules, 1, "should defer the delivery")
+	assert.GreaterOrEqual(t, retryScheduler.delays[0], 50*time.Second)
+
+	var deferred deliverymq.RetryMessage
+	require.NoError(t, deferred.FromString(retryScheduler.schedules[0]))
+	assert.Equal(t, deliveryEvent.ID, deferred.ToDeliveryEvent().ID)
+
+	// Manual retries bypass the breaker and close it on success
+	manualDeliveryEvent := models.NewManualDeliveryEvent(newEvent(), destination.ID)
+	handle(manualDeliveryEvent)
+	assert.Equal(t, 3, publisher.Current(), "manual retry should publish")
+
+	status, err = breaker.Status(context.Background(), tenant.ID, destination.ID)
+	require.NoError(t, err)
+	assert.Equal(t, circuitbreaker.StateClosed, status.State)
+	assert.Equal(t, 0, status.Failures)
+}
+
+func TestMessageHandler_CircuitBreakerIgnoresNonEndpointFailures(t *testing.T) {
+	// Test scenario:
+	// - Deliveries fail for reasons unrelated to the endpoint health
+	// - The failures shouldn't count toward the circuit breaker
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		err  *destregistry.ErrDestinationPublishAttempt
+	}{
+		{
+			name: "transformation failure",
+			err: &destregistry.ErrDestinationPublishAttempt{
+				Err:          fmt.Errorf("%w: invalid expression", destregistry.ErrTransformationFailed),
+				Provider:     "webhook",
+				Data:         map[string]interface{}{"error": "transformation_failed"},
+				NonRetryable: true,
+			},
+		},
+		{
+			name: "non-retryable failure",
+			err: &destregistry.ErrDestinationPublishAttempt{
+				Err:          errors.New("webhook returned 400"),
+				Provider:     "webhook",
+				Data:         map[string]interface{}{"error": "publish_failed"},
+				NonRetryable: true,
+			},
+		},
+		{
+			name: "destination gone",
+			err: &destregistry.ErrDestinationPublishAttempt{
+				Err:                errors.New("webhook returned 410"),
+				Provider:           "webhook",
+				Data:               map[string]interface{}{"error": "publish_failed"},
+				DisableDestination: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tenant := models.Tenant{ID: uuid.New().String()}
+			destination := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("webhook"),
+				testutil.DestinationFactory.WithTenantID(tenant.ID),
+			)
+			event := testutil.EventFactory.Any(
+				testutil.EventFactory.WithTenantID(tenant.ID),
+				testutil.EventFactory.WithDestinationID(destination.ID),
+				testutil.EventFactory.WithEligibleForRetry(false),
+			)
+
+			redisClient := testutil.CreateTestRedisClient(t)
+			breaker := circuitbreaker.NewRedisCircuitBreaker(redisClient,
+				circuitbreaker.WithFailureThreshold(1),
+				circuitbreaker.WithOpenTimeout(time.Minute),
+			)
+			handler := deliverymq.NewMessageHandler(
+				testutil.CreateTestLogger(t),
+				redisClient,
+				newMockLogPublisher(nil),
+				&mockDestinationGetter{dest: &destination},
+				newMockEventGetter(),
+				newMockPublisher([]error{tc.err}),
+				testutil.NewMockEventTracer(nil),
+				newMockRetryScheduler(),
+				&backoff.ConstantBackoff{Interval: 1 * time.Second},
+				10,
+				newMockAlertMonitor(),
+				deliverymq.WithCircuitBreaker(breaker),
+			)
+			_, msg := newDeliveryMockMessage(models.NewDeliveryEvent(event, destination.ID))
+			_ = handler.Handle(context.Background(), msg)
+
+			status, err := breaker.Status(context.Background(), tenant.ID, destination.ID)
+			require.NoError(t, err)
+			assert.Equal(t, circuitbreaker.StateClosed, status.State)
+			assert.Equal(t, 0, status.Failures)
+		})
+	}
+}
+
 func TestMessageHandler_PublishError_NotEligible(t *testing.T) {
 	// Test scenario:
 	// - Publish returns ErrDestinationPublishAttempt
@@ -1093,6 +1702,71 @@ func TestManualDelivery_DestinationDisabled(t *testing.T) {
 	alertMonitor.AssertNotCalled(t, "HandleAttempt", mock.Anything, mock.Anything)
 }
 
+func TestMessageHandler_Batch(t *testing.T) {
+	// Test scenario:
+	// - Destination batches its deliveries
+	// - Handle should return without waiting for the batch to be sent
+	// - Message should be acked once the batch is sent
+	t.Parallel()
+
+	// Setup test data
+	tenant := models.Tenant{ID: uuid.New().String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	destination.Batch = &models.Batch{MaxSize: 10}
+
+	// Setup mocks
+	destGetter := &mockDestinationGetter{dest: &destination}
+	eventGetter := newMockEventGetter()
+	publisher := newBlockingPublisher()
+	logPublisher := newMockLogPublisher(nil)
+	alertMonitor := newMockAlertMonitor()
+
+	// Setup message handler
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		testutil.CreateTestRedisClient(t),
+		logPublisher,
+		destGetter,
+		eventGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		newMockRetryScheduler(),
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		alertMonitor,
+	)
+
+	// Handle messages while the batch is held
+	mockMsgs := make([]*mockMessage, 3)
+	for i := range mockMsgs {
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+		)
+		eventGetter.registerEvent(&event)
+		mockMsg, msg := newDeliveryMockMessage(models.DeliveryEvent{
+			ID:            uuid.New().String(),
+			Event:         event,
+			DestinationID: destination.ID,
+		})
+		require.NoError(t, handler.Handle(context.Background(), msg))
+		mockMsgs[i] = mockMsg
+	}
+	for _, mockMsg := range mockMsgs {
+		assert.False(t, mockMsg.Acked(), "message should not be acked before its batch is sent")
+	}
+
+	// Send the batch
+	close(publisher.release)
+	for _, mockMsg := range mockMsgs {
+		assert.Eventually(t, mockMsg.Acked, 5*time.Second, 10*time.Millisecond, "message should be acked once its batch is sent")
+	}
+	assert.Equal(t, 3, publisher.Current())
+}
+
 func TestMessageHandler_PublishSuccess(t *testing.T) {
 	// Test scenario:
 	// - Publish succeeds
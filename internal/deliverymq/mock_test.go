@@ -9,8 +9,10 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/hookdeck/outpost/internal/alert"
+	"github.com/hookdeck/outpost/internal/deadletter"
 	"github.com/hookdeck/outpost/internal/models"
 	mqs "github.com/hookdeck/outpost/internal/mqs"
+	"github.com/hookdeck/outpost/internal/ratelimit"
 	"github.com/hookdeck/outpost/internal/scheduler"
 	"github.com/stretchr/testify/mock"
 )
@@ -142,6 +144,7 @@ func (m *mockLogPublisher) Publish(ctx context.Context, deliveryEvent models.Del
 
 type mockRetryScheduler struct {
 	schedules    []string
+	delays       []time.Duration
 	taskIDs      []string
 	canceled     []string
 	scheduleResp []error
@@ -162,6 +165,7 @@ func newMockRetryScheduler() *mockRetryScheduler {
 
 func (m *mockRetryScheduler) Schedule(ctx context.Context, task string, delay time.Duration, opts ...scheduler.ScheduleOption) error {
 	m.schedules = append(m.schedules, task)
+	m.delays = append(m.delays, delay)
 
 	// Capture the task ID by applying the option
 	if len(opts) > 0 {
@@ -191,6 +195,7 @@ func (m *mockRetryScheduler) Cancel(ctx context.Context, taskID string) error {
 }
 
 type mockMessage struct {
+	mu     sync.Mutex
 	id     string
 	acked  bool
 	nacked bool
@@ -221,13 +226,42 @@ func (m *mockMessage) ID() string {
 }
 
 func (m *mockMessage) Ack() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.acked = true
 }
 
 func (m *mockMessage) Nack() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.nacked = true
 }
 
+func (m *mockMessage) Acked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.acked
+}
+
+// blockingPublisher holds each publish until released, like a batch waiting
+// for its window to elapse.
+type blockingPublisher struct {
+	*mockPublisher
+	release chan struct{}
+}
+
+func newBlockingPublisher() *blockingPublisher {
+	return &blockingPublisher{
+		mockPublisher: newMockPublisher(nil),
+		release:       make(chan struct{}),
+	}
+}
+
+func (m *blockingPublisher) PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Delivery, error) {
+	<-m.release
+	return m.mockPublisher.PublishEvent(ctx, destination, event)
+}
+
 func (m *mockMessage) Data() []byte {
 	return nil
 }
@@ -251,3 +285,28 @@ func newMockAlertMonitor() *mockAlertMonitor {
 	})).Return(nil)
 	return monitor
 }
+
+type mockDeadLetterStore struct {
+	deadLetters []deadletter.DeadLetter
+}
+
+func (m *mockDeadLetterStore) Add(ctx context.Context, deadLetter deadletter.DeadLetter) error {
+	m.deadLetters = append(m.deadLetters, deadLetter)
+	return nil
+}
+
+type mockRateLimiter struct {
+	wait   time.Duration
+	keys   []string
+	limits []ratelimit.Limit
+}
+
+// Acquire denies every request when wait is set.
+func (m *mockRateLimiter) Acquire(ctx context.Context, key string, limit ratelimit.Limit) (*ratelimit.Lease, time.Duration, error) {
+	m.keys = append(m.keys, key)
+	m.limits = append(m.limits, limit)
+	if m.wait > 0 {
+		return nil, m.wait, nil
+	}
+	return &ratelimit.Lease{}, 0, nil
+}
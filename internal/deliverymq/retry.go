@@ -3,7 +3,11 @@ package deliverymq
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"time"
 
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/redis"
 	"github.com/hookdeck/outpost/internal/scheduler"
@@ -31,6 +35,10 @@ type RetryMessage struct {
 	DestinationID   string
 	Attempt         int
 	Telemetry       *models.DeliveryEventTelemetry
+	// Event and Manual are only set on deferred deliveries, which are
+	// rescheduled shortly after and carry the full event to skip the lookup.
+	Event  *models.Event `json:",omitempty"`
+	Manual bool          `json:",omitempty"`
 }
 
 func (m *RetryMessage) ToString() (string, error) {
@@ -46,12 +54,17 @@ func (m *RetryMessage) FromString(str string) error {
 }
 
 func (m *RetryMessage) ToDeliveryEvent() models.DeliveryEvent {
+	event := models.Event{ID: m.EventID, TenantID: m.TenantID}
+	if m.Event != nil {
+		event = *m.Event
+	}
 	return models.DeliveryEvent{
 		ID:            m.DeliveryEventID,
 		Attempt:       m.Attempt,
 		DestinationID: m.DestinationID,
-		Event:         models.Event{ID: m.EventID, TenantID: m.TenantID},
+		Event:         event,
 		Telemetry:     m.Telemetry,
+		Manual:        m.Manual,
 	}
 }
 
@@ -65,3 +78,70 @@ func RetryMessageFromDeliveryEvent(deliveryEvent models.DeliveryEvent) RetryMess
 		Telemetry:       deliveryEvent.Telemetry,
 	}
 }
+
+// DeferredMessageFromDeliveryEvent creates a message to deliver the same
+// attempt again later, for example when the destination is rate limited.
+func DeferredMessageFromDeliveryEvent(deliveryEvent models.DeliveryEvent) RetryMessage {
+	event := deliveryEvent.Event
+	return RetryMessage{
+		DeliveryEventID: deliveryEvent.ID,
+		EventID:         deliveryEvent.Event.ID,
+		TenantID:        deliveryEvent.Event.TenantID,
+		DestinationID:   deliveryEvent.DestinationID,
+		Attempt:         deliveryEvent.Attempt,
+		Telemetry:       deliveryEvent.Telemetry,
+		Event:           &event,
+		Manual:          deliveryEvent.Manual,
+	}
+}
+
+// retryDelay returns the backoff delay, extended to the destination's retry
+// hint when there's one, up to maxRetryAfter.
+func retryDelay(backoffDuration time.Duration, err error, maxRetryAfter time.Duration) time.Duration {
+	var publishErr *destregistry.ErrDestinationPublishAttempt
+	if !errors.As(err, &publishErr) || publishErr.RetryAfter <= backoffDuration {
+		return backoffDuration
+	}
+	return max(backoffDuration, min(publishErr.RetryAfter, maxRetryAfter))
+}
+
+// retryBackoffFromPolicy returns the backoff described by the destination's
+// retry policy, falling back to the global backoff when the policy doesn't
+// set a strategy.
+func retryBackoffFromPolicy(policy *models.RetryPolicy, fallback backoff.Backoff) backoff.Backoff {
+	if policy.IsZero() {
+		return fallback
+	}
+
+	var b backoff.Backoff
+	switch policy.ResolvedStrategy() {
+	case models.RetryStrategyExponential:
+		if policy.MaxInterval > 0 {
+			b = &backoff.CappedExponentialBackoff{
+				Interval: time.Duration(policy.Interval),
+				Base:     2,
+				Max:      time.Duration(policy.MaxInterval),
+			}
+		} else {
+			b = &backoff.ExponentialBackoff{
+				Interval: time.Duration(policy.Interval),
+				Base:     2,
+			}
+		}
+	case models.RetryStrategyConstant:
+		b = &backoff.ConstantBackoff{Interval: time.Duration(policy.Interval)}
+	case models.RetryStrategySchedule:
+		schedule := make([]time.Duration, len(policy.Schedule))
+		for i, interval := range policy.Schedule {
+			schedule[i] = time.Duration(interval)
+		}
+		b = &backoff.ScheduledBackoff{Schedule: schedule}
+	default:
+		b = fallback
+	}
+
+	if policy.Jitter > 0 {
+		b = &backoff.JitterBackoff{Backoff: b, Factor: policy.Jitter}
+	}
+	return b
+}
@@ -2,8 +2,10 @@ package destregistry
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -193,6 +195,14 @@ func (p *BaseProvider) Preprocess(newDestination *models.Destination, originalDe
 type HTTPClientConfig struct {
 	Timeout   *time.Duration
 	UserAgent *string
+	// TLSConfig configures client certificates and trusted certificates,
+	// e.g. built with TLSClientConfig.Build.
+	TLSConfig *tls.Config
+	// EgressPolicy restricts the addresses the client connects to.
+	EgressPolicy *EgressPolicy
+	// Proxy routes requests through a forward proxy, e.g. built with
+	// ProxyConfig.Build. The proxy from the environment is used otherwise.
+	Proxy func(*http.Request) (*url.URL, error)
 }
 
 func (p *BaseProvider) MakeHTTPClient(config HTTPClientConfig) *http.Client {
@@ -202,10 +212,29 @@ func (p *BaseProvider) MakeHTTPClient(config HTTPClientConfig) *http.Client {
 		client.Timeout = *config.Timeout
 	}
 
+	transport := http.DefaultTransport
+	if config.TLSConfig != nil || config.EgressPolicy != nil || config.Proxy != nil {
+		customTransport := http.DefaultTransport.(*http.Transport).Clone()
+		if config.TLSConfig != nil {
+			customTransport.TLSClientConfig = config.TLSConfig
+		}
+		if config.Proxy != nil {
+			customTransport.Proxy = config.Proxy
+		}
+		if config.EgressPolicy != nil {
+			customTransport.DialContext = config.EgressPolicy.Dialer().DialContext
+			if customTransport.Proxy != nil {
+				customTransport.Proxy = config.EgressPolicy.proxy(customTransport.Proxy)
+			}
+		}
+		transport = customTransport
+		client.Transport = transport
+	}
+
 	if config.UserAgent != nil {
 		client.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
 			req.Header.Set("User-Agent", *config.UserAgent)
-			return http.DefaultTransport.RoundTrip(req)
+			return transport.RoundTrip(req)
 		})
 	}
 
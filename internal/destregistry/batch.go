@@ -0,0 +1,110 @@
+package destregistry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// BatchPublisher is implemented by publishers that can deliver several events
+// in a single request.
+type BatchPublisher interface {
+	Publisher
+	PublishBatch(ctx context.Context, events []*models.Event) (*Delivery, error)
+}
+
+// BatchProvider is implemented by providers whose publishers implement BatchPublisher.
+type BatchProvider interface {
+	Provider
+	SupportsBatch() bool
+}
+
+// batcher collects events published concurrently to the same destination
+// into batches. Every event of a batch gets the batch's delivery result.
+type batcher struct {
+	mu      sync.Mutex
+	pending map[string]*pendingBatch
+}
+
+type pendingBatch struct {
+	publisher BatchPublisher
+	events    []*models.Event
+	timer     *time.Timer
+	sent      bool
+	done      chan struct{}
+	delivery  *Delivery
+	err       error
+}
+
+func newBatcher() *batcher {
+	return &batcher{
+		pending: make(map[string]*pendingBatch),
+	}
+}
+
+// publish adds the event to the pending batch for the key and waits for the
+// batch to be sent. The batch is sent with its own timeout so that it's not
+// tied to the context of any single event.
+func (b *batcher) publish(ctx context.Context, key string, publisher BatchPublisher, config *models.Batch, event *models.Event, timeout time.Duration) (*Delivery, error) {
+	b.mu.Lock()
+	batch, ok := b.pending[key]
+	if !ok {
+		batch = &pendingBatch{
+			publisher: publisher,
+			done:      make(chan struct{}),
+		}
+		b.pending[key] = batch
+		sendCtx := context.WithoutCancel(ctx)
+		batch.timer = time.AfterFunc(config.ResolvedWindow(), func() {
+			if b.take(key, batch) {
+				batch.send(sendCtx, timeout)
+			}
+		})
+	}
+	batch.events = append(batch.events, event)
+	full := len(batch.events) >= config.ResolvedMaxSize()
+	if full {
+		b.takeLocked(key, batch)
+	}
+	b.mu.Unlock()
+
+	if full {
+		batch.timer.Stop()
+		batch.send(context.WithoutCancel(ctx), timeout)
+	}
+
+	select {
+	case <-batch.done:
+		return batch.delivery, batch.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// take removes the batch from the pending batches. It returns false if the
+// batch was already taken to be sent.
+func (b *batcher) take(key string, batch *pendingBatch) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if batch.sent {
+		return false
+	}
+	b.takeLocked(key, batch)
+	return true
+}
+
+func (b *batcher) takeLocked(key string, batch *pendingBatch) {
+	batch.sent = true
+	if b.pending[key] == batch {
+		delete(b.pending, key)
+	}
+}
+
+func (p *pendingBatch) send(ctx context.Context, timeout time.Duration) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	p.delivery, p.err = p.publisher.PublishBatch(timeoutCtx, p.events)
+	close(p.done)
+}
@@ -1,6 +1,8 @@
 package destregistry
 
 import (
+	"time"
+
 	"github.com/hookdeck/outpost/internal/models"
 )
 
@@ -8,6 +10,8 @@ import (
 type DestinationDisplay struct {
 	*models.Destination
 	DestinationTarget
+	// CertificateExpiresAt is when the destination's client certificate expires.
+	CertificateExpiresAt *time.Time `json:"certificate_expires_at,omitempty"`
 }
 
 type DestinationTarget struct {
@@ -0,0 +1,151 @@
+package destregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrEgressBlocked is returned when a delivery connects to an address the
+// egress policy doesn't allow.
+var ErrEgressBlocked = errors.New("address blocked by egress policy")
+
+// ErrorCodeEgressBlocked is the error code of deliveries blocked by the egress
+// policy in ErrDestinationPublishAttempt.Data.
+const ErrorCodeEgressBlocked = "egress_blocked"
+
+// blockedPrefixes are blocked by default in addition to the loopback, private,
+// link-local and unspecified addresses.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT, used by some cloud metadata services
+}
+
+// EgressPolicy restricts the addresses outbound deliveries can connect to.
+// Addresses are checked when connecting, after DNS resolution, so hostnames
+// resolving to internal addresses and redirects to them are blocked too.
+//
+// Denied CIDRs take precedence over allowed CIDRs, which take precedence over
+// the default blocking of loopback, private and link-local addresses.
+type EgressPolicy struct {
+	allowPrivateNetworks bool
+	allow                []netip.Prefix
+	deny                 []netip.Prefix
+}
+
+// NewEgressPolicy creates an egress policy. allowPrivateNetworks disables the
+// default blocking of loopback, private and link-local addresses.
+func NewEgressPolicy(allowPrivateNetworks bool, allowCIDRs, denyCIDRs []string) (*EgressPolicy, error) {
+	allow, err := parsePrefixes(allowCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := parsePrefixes(denyCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	return &EgressPolicy{
+		allowPrivateNetworks: allowPrivateNetworks,
+		allow:                allow,
+		deny:                 deny,
+	}, nil
+}
+
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Allowed reports whether the policy allows connecting to the address.
+func (p *EgressPolicy) Allowed(addr netip.Addr) bool {
+	addr = addr.Unmap().WithZone("")
+	if containsAddr(p.deny, addr) {
+		return false
+	}
+	if containsAddr(p.allow, addr) {
+		return true
+	}
+	if p.allowPrivateNetworks {
+		return true
+	}
+	return !(addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsUnspecified() ||
+		containsAddr(blockedPrefixes, addr))
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dialer returns a dialer that refuses connections the policy doesn't allow.
+func (p *EgressPolicy) Dialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   p.control,
+	}
+}
+
+// proxy checks the targets of proxied requests, as the dialer only sees the
+// address of the proxy. Targets that can't be resolved locally are left to
+// the proxy's own policy.
+func (p *EgressPolicy) proxy(next func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := next(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		if err := p.checkHost(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+		return proxyURL, nil
+	}
+}
+
+func (p *EgressPolicy) checkHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !p.Allowed(addr) {
+			return fmt.Errorf("%w: %s", ErrEgressBlocked, host)
+		}
+	}
+	return nil
+}
+
+// control is called with the resolved address of each connection attempt.
+func (p *EgressPolicy) control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrEgressBlocked, address)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !p.Allowed(addr) {
+		return fmt.Errorf("%w: %s", ErrEgressBlocked, host)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package destregistry_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressPolicy_Allowed(t *testing.T) {
+	t.Parallel()
+
+	defaultPolicy, err := destregistry.NewEgressPolicy(false, nil, nil)
+	require.NoError(t, err)
+	customPolicy, err := destregistry.NewEgressPolicy(false, []string{"10.1.0.0/16"}, []string{"10.1.2.0/24", "203.0.113.0/24"})
+	require.NoError(t, err)
+	privatePolicy, err := destregistry.NewEgressPolicy(true, nil, []string{"169.254.169.254/32"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		policy  *destregistry.EgressPolicy
+		addr    string
+		allowed bool
+	}{
+		{"public IPv4", defaultPolicy, "93.184.216.34", true},
+		{"public IPv6", defaultPolicy, "2606:2800:220:1:248:1893:25c8:1946", true},
+		{"loopback", defaultPolicy, "127.0.0.1", false},
+		{"IPv6 loopback", defaultPolicy, "::1", false},
+		{"private", defaultPolicy, "10.0.0.1", false},
+		{"private 172.16/12", defaultPolicy, "172.31.255.255", false},
+		{"private 192.168/16", defaultPolicy, "192.168.1.1", false},
+		{"IPv6 unique local", defaultPolicy, "fd00:ec2::254", false},
+		{"metadata service", defaultPolicy, "169.254.169.254", false},
+		{"IPv6 link-local", defaultPolicy, "fe80::1%eth0", false},
+		{"IPv4-mapped loopback", defaultPolicy, "::ffff:127.0.0.1", false},
+		{"unspecified", defaultPolicy, "0.0.0.0", false},
+		{"carrier-grade NAT", defaultPolicy, "100.100.100.200", false},
+		{"allowed CIDR", customPolicy, "10.1.0.1", true},
+		{"denied CIDR within allowed CIDR", customPolicy, "10.1.2.1", false},
+		{"denied public CIDR", customPolicy, "203.0.113.10", false},
+		{"private outside allowed CIDR", customPolicy, "10.2.0.1", false},
+		{"private networks allowed", privatePolicy, "10.0.0.1", true},
+		{"denied CIDR with private networks allowed", privatePolicy, "169.254.169.254", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.allowed, tt.policy.Allowed(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestNewEgressPolicy_InvalidCIDR(t *testing.T) {
+	t.Parallel()
+
+	_, err := destregistry.NewEgressPolicy(false, []string{"10.0.0.0/33"}, nil)
+	assert.Error(t, err)
+	_, err = destregistry.NewEgressPolicy(false, nil, []string{"not-a-cidr"})
+	assert.Error(t, err)
+}
@@ -3,6 +3,7 @@ package destregistry
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 type ErrDestinationValidation struct {
@@ -26,6 +27,14 @@ type ErrDestinationPublishAttempt struct {
 	Err      error
 	Provider string
 	Data     map[string]interface{}
+	// RetryAfter is a hint from the destination of how long to wait before
+	// retrying, e.g. from a Retry-After header or a throttling error.
+	RetryAfter time.Duration
+	// NonRetryable marks failures that retrying won't fix, e.g. a 400 response.
+	NonRetryable bool
+	// DisableDestination marks failures meaning the destination is gone for
+	// good, e.g. a 410 response. They aren't retried either.
+	DisableDestination bool
 }
 
 var _ error = &ErrDestinationPublishAttempt{}
@@ -38,4 +47,14 @@ func NewErrDestinationPublishAttempt(err error, provider string, data map[string
 	return &ErrDestinationPublishAttempt{Err: err, Provider: provider, Data: data}
 }
 
+// NewErrDestinationPublishAttemptWithRetryAfter returns a publish attempt error
+// hinting that the delivery shouldn't be retried before retryAfter.
+func NewErrDestinationPublishAttemptWithRetryAfter(err error, provider string, data map[string]interface{}, retryAfter time.Duration) error {
+	return &ErrDestinationPublishAttempt{Err: err, Provider: provider, Data: data, RetryAfter: retryAfter}
+}
+
 var ErrPublisherClosed = errors.New("publisher is closed")
+
+// ErrTransformationFailed is wrapped by publish attempt errors when the
+// destination transformation can't be applied to the event.
+var ErrTransformationFailed = errors.New("transformation failed")
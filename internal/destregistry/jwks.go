@@ -0,0 +1,95 @@
+package destregistry
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// JWK is a public key in the JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+// KeySetProvider is implemented by providers that sign deliveries with
+// asymmetric keys, so the public keys can be shared with tenants.
+type KeySetProvider interface {
+	// PublicKeys returns the public keys currently used to sign deliveries to
+	// the destination. It returns an empty list if the destination isn't
+	// signed with asymmetric keys.
+	PublicKeys(destination *models.Destination) ([]JWK, error)
+}
+
+// NewJWK returns the signing JWK of an Ed25519 or ECDSA P-256 public key. The
+// key ID is the key's thumbprint (RFC 7638).
+func NewJWK(key crypto.PublicKey) (JWK, error) {
+	var jwk JWK
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		jwk = JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+			Alg: "EdDSA",
+		}
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return JWK{}, fmt.Errorf("unsupported curve %s", key.Curve.Params().Name)
+		}
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+		// Uncompressed point: 0x04 || X || Y
+		point := ecdhKey.Bytes()
+		jwk = JWK{
+			Kty: "EC",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(point[1:33]),
+			Y:   base64.RawURLEncoding.EncodeToString(point[33:]),
+			Alg: "ES256",
+		}
+	default:
+		return JWK{}, fmt.Errorf("unsupported key type %T", key)
+	}
+	jwk.Use = "sig"
+
+	kid, err := jwk.thumbprint()
+	if err != nil {
+		return JWK{}, err
+	}
+	jwk.Kid = kid
+	return jwk, nil
+}
+
+// thumbprint hashes the required members of the key in lexicographic order.
+func (k JWK) thumbprint() (string, error) {
+	members := map[string]string{
+		"crv": k.Crv,
+		"kty": k.Kty,
+		"x":   k.X,
+	}
+	if k.Kty == "EC" {
+		members["y"] = k.Y
+	}
+	// encoding/json sorts map keys, giving the canonical form of RFC 7638.
+	b, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -6,8 +6,13 @@ import (
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destawss3"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destawssqs"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destazureservicebus"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destgcppubsub"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/desthookdeck"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destkafka"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destmqtt"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destnats"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destrabbitmq"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destredisstream"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
 )
 
@@ -21,16 +26,35 @@ type DestWebhookConfig struct {
 	SignatureHeaderTemplate       string
 	SignatureEncoding             string
 	SignatureAlgorithm            string
+	Mode                          string
+	SuccessStatusCodes            string
+	NonRetryableStatusCodes       string
+	DisableStatusCodes            string
+	ResponseHeaders               []string
+	ResponseBodyMaxBytes          int
+	VerificationMethod            string
 }
 
 type DestAWSKinesisConfig struct {
 	MetadataInPayload bool
 }
 
+type DestEgressConfig struct {
+	AllowPrivateNetworks bool
+	AllowCIDRs           []string
+	DenyCIDRs            []string
+}
+
 type RegisterDefaultDestinationOptions struct {
 	UserAgent  string
 	Webhook    *DestWebhookConfig
 	AWSKinesis *DestAWSKinesisConfig
+	// Egress restricts the addresses HTTP destinations can connect to. There's
+	// no restriction if it's nil.
+	Egress *DestEgressConfig
+	// Proxy is the forward proxy of HTTP destinations without their own proxy.
+	// The proxy from the environment is used if it's nil.
+	Proxy *destregistry.ProxyConfig
 }
 
 // RegisterDefault registers the default destination providers with the registry.
@@ -39,8 +63,24 @@ type RegisterDefaultDestinationOptions struct {
 func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestinationOptions) error {
 	loader := registry.MetadataLoader()
 
+	var egressPolicy *destregistry.EgressPolicy
+	if opts.Egress != nil {
+		var err error
+		egressPolicy, err = destregistry.NewEgressPolicy(opts.Egress.AllowPrivateNetworks, opts.Egress.AllowCIDRs, opts.Egress.DenyCIDRs)
+		if err != nil {
+			return err
+		}
+	}
+	if !opts.Proxy.IsZero() {
+		if _, err := opts.Proxy.Build(); err != nil {
+			return err
+		}
+	}
+
 	webhookOpts := []destwebhook.Option{
 		destwebhook.WithUserAgent(opts.UserAgent),
+		destwebhook.WithEgressPolicy(egressPolicy),
+		destwebhook.WithProxy(opts.Proxy),
 	}
 	if opts.Webhook != nil {
 		webhookOpts = append(webhookOpts,
@@ -53,6 +93,13 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 			destwebhook.WithSignatureHeaderTemplate(opts.Webhook.SignatureHeaderTemplate),
 			destwebhook.WithSignatureEncoding(opts.Webhook.SignatureEncoding),
 			destwebhook.WithSignatureAlgorithm(opts.Webhook.SignatureAlgorithm),
+			destwebhook.WithMode(opts.Webhook.Mode),
+			destwebhook.WithSuccessStatusCodes(opts.Webhook.SuccessStatusCodes),
+			destwebhook.WithNonRetryableStatusCodes(opts.Webhook.NonRetryableStatusCodes),
+			destwebhook.WithDisableStatusCodes(opts.Webhook.DisableStatusCodes),
+			destwebhook.WithResponseHeaders(opts.Webhook.ResponseHeaders),
+			destwebhook.WithResponseBodyMaxBytes(opts.Webhook.ResponseBodyMaxBytes),
+			destwebhook.WithVerification(opts.Webhook.VerificationMethod),
 		)
 	}
 	webhook, err := destwebhook.New(loader, webhookOpts...)
@@ -62,7 +109,9 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 	registry.RegisterProvider("webhook", webhook)
 
 	hookdeck, err := desthookdeck.New(loader,
-		desthookdeck.WithUserAgent(opts.UserAgent))
+		desthookdeck.WithUserAgent(opts.UserAgent),
+		desthookdeck.WithEgressPolicy(egressPolicy),
+		desthookdeck.WithProxy(opts.Proxy))
 	if err != nil {
 		return err
 	}
@@ -104,5 +153,35 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 	}
 	registry.RegisterProvider("rabbitmq", rabbitmq)
 
+	kafka, err := destkafka.New(loader, destkafka.WithEgressPolicy(egressPolicy))
+	if err != nil {
+		return err
+	}
+	registry.RegisterProvider("kafka", kafka)
+
+	gcpPubSub, err := destgcppubsub.New(loader)
+	if err != nil {
+		return err
+	}
+	registry.RegisterProvider("gcp_pubsub", gcpPubSub)
+
+	nats, err := destnats.New(loader, destnats.WithEgressPolicy(egressPolicy))
+	if err != nil {
+		return err
+	}
+	registry.RegisterProvider("nats", nats)
+
+	redisStream, err := destredisstream.New(loader, destredisstream.WithEgressPolicy(egressPolicy))
+	if err != nil {
+		return err
+	}
+	registry.RegisterProvider("redis_stream", redisStream)
+
+	mqtt, err := destmqtt.New(loader, destmqtt.WithEgressPolicy(egressPolicy))
+	if err != nil {
+		return err
+	}
+	registry.RegisterProvider("mqtt", mqtt)
+
 	return nil
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/metadata"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/awsutil"
 	"github.com/jmespath/go-jmespath"
 )
 
@@ -299,7 +300,7 @@ func (p *AWSKinesisPublisher) Publish(ctx context.Context, event *models.Event)
 				Response: map[string]interface{}{
 					"error": err.Error(),
 				},
-			}, destregistry.NewErrDestinationPublishAttempt(
+			}, destregistry.NewErrDestinationPublishAttemptWithRetryAfter(
 				err,
 				"aws_kinesis",
 				map[string]interface{}{
@@ -307,6 +308,7 @@ func (p *AWSKinesisPublisher) Publish(ctx context.Context, event *models.Event)
 					"stream_name":   p.streamName,
 					"partition_key": *input.PartitionKey,
 				},
+				awsutil.ThrottlingRetryAfter(err),
 			)
 	}
 
@@ -17,6 +17,7 @@ import (
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/metadata"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/awsutil"
 )
 
 type AWSSQSDestination struct {
@@ -176,9 +177,9 @@ func (p *AWSSQSPublisher) Publish(ctx context.Context, event *models.Event) (*de
 				Response: map[string]interface{}{
 					"error": err.Error(),
 				},
-			}, destregistry.NewErrDestinationPublishAttempt(err, "aws_sqs", map[string]interface{}{
+			}, destregistry.NewErrDestinationPublishAttemptWithRetryAfter(err, "aws_sqs", map[string]interface{}{
 				"error": err.Error(),
-			})
+			}, awsutil.ThrottlingRetryAfter(err))
 	}
 
 	return &destregistry.Delivery{
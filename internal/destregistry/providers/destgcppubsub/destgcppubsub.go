@@ -0,0 +1,325 @@
+package destgcppubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/jmespath/go-jmespath"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// Configuration types
+type GCPPubSubDestinationConfig struct {
+	ProjectID           string
+	Topic               string
+	OrderingKeyTemplate string
+}
+
+type GCPPubSubDestinationCredentials struct {
+	ServiceAccountJSON string
+}
+
+// serviceAccountKey holds the fields of a service account key file that are
+// checked during validation and displayed after obfuscation.
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+}
+
+// Provider implementation
+type GCPPubSubDestination struct {
+	*destregistry.BaseProvider
+	// emulatorEndpoint is only set by tests to publish to a Pub/Sub emulator
+	emulatorEndpoint string
+}
+
+var _ destregistry.Provider = (*GCPPubSubDestination)(nil)
+
+// Option configures a GCPPubSubDestination
+type Option func(*GCPPubSubDestination)
+
+// Constructor
+func New(loader metadata.MetadataLoader, opts ...Option) (*GCPPubSubDestination, error) {
+	base, err := destregistry.NewBaseProvider(loader, "gcp_pubsub")
+	if err != nil {
+		return nil, err
+	}
+	d := &GCPPubSubDestination{BaseProvider: base}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+// Validate performs destination-specific validation
+func (d *GCPPubSubDestination) Validate(ctx context.Context, destination *models.Destination) error {
+	_, _, err := d.resolveConfig(ctx, destination)
+	return err
+}
+
+// CreatePublisher creates a new publisher instance
+func (d *GCPPubSubDestination) CreatePublisher(ctx context.Context, destination *models.Destination) (destregistry.Publisher, error) {
+	config, credentials, err := d.resolveConfig(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []option.ClientOption
+	if d.emulatorEndpoint != "" {
+		// The emulator doesn't support authentication nor TLS
+		opts = append(opts,
+			option.WithEndpoint(d.emulatorEndpoint),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		)
+	} else {
+		opts = append(opts, option.WithCredentialsJSON([]byte(credentials.ServiceAccountJSON)))
+	}
+
+	// The publisher outlives the context of the request creating it
+	client, err := pubsub.NewClient(context.Background(), config.ProjectID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub client: %w", err)
+	}
+
+	topic := client.Topic(config.Topic)
+	topic.EnableMessageOrdering = config.OrderingKeyTemplate != ""
+
+	return &GCPPubSubPublisher{
+		BasePublisher:       &destregistry.BasePublisher{},
+		client:              client,
+		topic:               topic,
+		orderingKeyTemplate: config.OrderingKeyTemplate,
+	}, nil
+}
+
+// resolveConfig parses the destination config and credentials
+func (d *GCPPubSubDestination) resolveConfig(ctx context.Context, destination *models.Destination) (*GCPPubSubDestinationConfig, *GCPPubSubDestinationCredentials, error) {
+	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
+		return nil, nil, err
+	}
+
+	serviceAccountJSON := destination.Credentials["service_account_json"]
+	var key serviceAccountKey
+	if err := json.Unmarshal([]byte(serviceAccountJSON), &key); err != nil || key.Type != "service_account" || key.PrivateKey == "" || key.ClientEmail == "" {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials.service_account_json",
+				Type:  "invalid",
+			},
+		})
+	}
+
+	return &GCPPubSubDestinationConfig{
+		ProjectID:           destination.Config["project_id"],
+		Topic:               destination.Config["topic"],
+		OrderingKeyTemplate: destination.Config["ordering_key_template"],
+	}, &GCPPubSubDestinationCredentials{
+		ServiceAccountJSON: serviceAccountJSON,
+	}, nil
+}
+
+// ComputeTarget returns a human-readable target for display
+func (d *GCPPubSubDestination) ComputeTarget(destination *models.Destination) destregistry.DestinationTarget {
+	projectID := destination.Config["project_id"]
+	topic := destination.Config["topic"]
+	return destregistry.DestinationTarget{
+		Target: fmt.Sprintf("%s in %s", topic, projectID),
+		TargetURL: fmt.Sprintf("https://console.cloud.google.com/cloudpubsub/topic/detail/%s?project=%s",
+			url.PathEscape(topic), url.QueryEscape(projectID)),
+	}
+}
+
+// ObfuscateDestination overrides the base implementation to keep the
+// non-secret fields of the service account key, such as its client email,
+// visible while masking the private key.
+func (d *GCPPubSubDestination) ObfuscateDestination(destination *models.Destination) *models.Destination {
+	result := d.BaseProvider.ObfuscateDestination(destination)
+
+	serviceAccountJSON, ok := destination.Credentials["service_account_json"]
+	if !ok {
+		return result
+	}
+	var key serviceAccountKey
+	if err := json.Unmarshal([]byte(serviceAccountJSON), &key); err != nil {
+		return result
+	}
+	key.PrivateKeyID = destregistry.ObfuscateValue(key.PrivateKeyID)
+	key.PrivateKey = destregistry.ObfuscateValue(key.PrivateKey)
+	obfuscated, err := json.Marshal(key)
+	if err != nil {
+		return result
+	}
+	result.Credentials["service_account_json"] = string(obfuscated)
+	return result
+}
+
+// Preprocess validates the destination
+func (d *GCPPubSubDestination) Preprocess(newDestination *models.Destination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if newDestination.Config == nil {
+		return nil
+	}
+	if _, _, err := d.resolveConfig(context.Background(), newDestination); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Publisher implementation
+type GCPPubSubPublisher struct {
+	*destregistry.BasePublisher
+	client              *pubsub.Client
+	topic               *pubsub.Topic
+	orderingKeyTemplate string
+}
+
+// Close flushes pending messages and closes the client
+func (p *GCPPubSubPublisher) Close() error {
+	p.BasePublisher.StartClose()
+	p.topic.Stop()
+	return p.client.Close()
+}
+
+// evaluateOrderingKey extracts the ordering key from the event using the JMESPath template
+func (p *GCPPubSubPublisher) evaluateOrderingKey(payload map[string]interface{}) (string, error) {
+	if p.orderingKeyTemplate == "" {
+		return "", nil
+	}
+
+	result, err := jmespath.Search(p.orderingKeyTemplate, payload)
+	if err != nil {
+		return "", fmt.Errorf("error evaluating ordering key template: %w", err)
+	}
+
+	switch v := result.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return fmt.Sprintf("%g", v), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// Format prepares the event as a Pub/Sub message. The event data is the
+// message data and the metadata are the message attributes.
+func (p *GCPPubSubPublisher) Format(ctx context.Context, event *models.Event) (*pubsub.Message, error) {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	attributes := p.BasePublisher.MakeMetadata(event, time.Now())
+	metadataMap := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		metadataMap[k] = v
+	}
+	dataMap := make(map[string]interface{}, len(event.Data))
+	for k, v := range event.Data {
+		dataMap[k] = v
+	}
+	orderingKey, err := p.evaluateOrderingKey(map[string]interface{}{
+		"metadata": metadataMap,
+		"data":     dataMap,
+	})
+	if err != nil {
+		// Publish without ordering if the template can't be evaluated
+		orderingKey = ""
+	}
+
+	return &pubsub.Message{
+		Data:        data,
+		Attributes:  attributes,
+		OrderingKey: orderingKey,
+	}, nil
+}
+
+// Publish sends an event to the Pub/Sub topic
+func (p *GCPPubSubPublisher) Publish(ctx context.Context, event *models.Event) (*destregistry.Delivery, error) {
+	if err := p.BasePublisher.StartPublish(); err != nil {
+		return nil, err
+	}
+	defer p.BasePublisher.FinishPublish()
+
+	msg, err := p.Format(ctx, event)
+	if err != nil {
+		return nil, destregistry.NewErrDestinationPublishAttempt(err, "gcp_pubsub", map[string]interface{}{
+			"error":   "format_failed",
+			"message": err.Error(),
+		})
+	}
+
+	messageID, err := p.topic.Publish(ctx, msg).Get(ctx)
+	if err != nil {
+		// Publishing is paused for an ordering key after a failure
+		if msg.OrderingKey != "" {
+			p.topic.ResumePublish(msg.OrderingKey)
+		}
+		return &destregistry.Delivery{
+			Status: "failed",
+			Code:   "ERR",
+			Response: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, destregistry.NewErrDestinationPublishAttempt(err, "gcp_pubsub", map[string]interface{}{
+			"error":   formatPubSubError(err),
+			"topic":   p.topic.String(),
+			"message": err.Error(),
+		})
+	}
+
+	response := map[string]interface{}{
+		"message_id": messageID,
+	}
+	if msg.OrderingKey != "" {
+		response["ordering_key"] = msg.OrderingKey
+	}
+	return &destregistry.Delivery{
+		Status:   "success",
+		Code:     "OK",
+		Response: response,
+	}, nil
+}
+
+// Helper function to format Pub/Sub errors
+func formatPubSubError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	switch status.Code(err) {
+	case codes.NotFound:
+		return "topic_not_found"
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return "access_denied"
+	case codes.InvalidArgument:
+		return "validation_error"
+	}
+	return "publish_failed"
+}
+
+// NewGCPPubSubPublisher creates a new publisher for testing purposes
+func NewGCPPubSubPublisher(client *pubsub.Client, topic *pubsub.Topic, orderingKeyTemplate string) *GCPPubSubPublisher {
+	return &GCPPubSubPublisher{
+		BasePublisher:       &destregistry.BasePublisher{},
+		client:              client,
+		topic:               topic,
+		orderingKeyTemplate: orderingKeyTemplate,
+	}
+}
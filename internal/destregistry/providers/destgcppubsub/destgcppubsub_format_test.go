@@ -0,0 +1,85 @@
+package destgcppubsub_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destgcppubsub"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCPPubSubPublisher_Format(t *testing.T) {
+	t.Parallel()
+
+	event := models.Event{
+		ID:    "event-123",
+		Topic: "user.created",
+		Data: map[string]interface{}{
+			"customer_id": "cus_456",
+			"count":       float64(3),
+		},
+		Metadata: map[string]string{
+			"source": "signup",
+		},
+	}
+
+	testCases := []struct {
+		name                string
+		orderingKeyTemplate string
+		expectedKey         string
+	}{
+		{
+			name:                "no template publishes without ordering key",
+			orderingKeyTemplate: "",
+			expectedKey:         "",
+		},
+		{
+			name:                "data field",
+			orderingKeyTemplate: "data.customer_id",
+			expectedKey:         "cus_456",
+		},
+		{
+			name:                "numeric data field",
+			orderingKeyTemplate: "data.count",
+			expectedKey:         "3",
+		},
+		{
+			name:                "metadata field",
+			orderingKeyTemplate: "metadata.source",
+			expectedKey:         "signup",
+		},
+		{
+			name:                "missing field publishes without ordering key",
+			orderingKeyTemplate: "data.missing",
+			expectedKey:         "",
+		},
+		{
+			name:                "invalid template publishes without ordering key",
+			orderingKeyTemplate: "data.[invalid",
+			expectedKey:         "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			publisher := destgcppubsub.NewGCPPubSubPublisher(nil, nil, tc.orderingKeyTemplate)
+
+			msg, err := publisher.Format(context.Background(), &event)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedKey, msg.OrderingKey)
+
+			var data map[string]interface{}
+			require.NoError(t, json.Unmarshal(msg.Data, &data))
+			assert.Equal(t, map[string]interface{}(event.Data), data)
+
+			assert.Equal(t, "event-123", msg.Attributes["event-id"])
+			assert.Equal(t, "user.created", msg.Attributes["topic"])
+			assert.Equal(t, "signup", msg.Attributes["source"])
+			assert.NotEmpty(t, msg.Attributes["timestamp"])
+		})
+	}
+}
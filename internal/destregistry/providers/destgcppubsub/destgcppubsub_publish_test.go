@@ -0,0 +1,154 @@
+package destgcppubsub_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destgcppubsub"
+	testsuite "github.com/hookdeck/outpost/internal/destregistry/testing"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/mqs"
+	"github.com/hookdeck/outpost/internal/util/testinfra"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// PubSubConsumer implements testsuite.MessageConsumer
+type PubSubConsumer struct {
+	client   *pubsub.Client
+	cancel   context.CancelFunc
+	done     chan struct{}
+	messages chan testsuite.Message
+}
+
+func NewPubSubConsumer(endpoint string, cfg *mqs.GCPPubSubConfig) (*PubSubConsumer, error) {
+	client, err := pubsub.NewClient(context.Background(), cfg.ProjectID,
+		option.WithEndpoint(endpoint),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub client: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consumer := &PubSubConsumer{
+		client:   client,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		messages: make(chan testsuite.Message, 100),
+	}
+
+	go func() {
+		defer close(consumer.done)
+		// Receive returns once the context is canceled
+		_ = client.Subscription(cfg.SubscriptionID).Receive(ctx, func(_ context.Context, msg *pubsub.Message) {
+			msg.Ack()
+			consumer.messages <- testsuite.Message{
+				Data:     msg.Data,
+				Metadata: msg.Attributes,
+				Raw:      msg,
+			}
+		})
+	}()
+
+	return consumer, nil
+}
+
+func (c *PubSubConsumer) Consume() <-chan testsuite.Message {
+	return c.messages
+}
+
+func (c *PubSubConsumer) Close() error {
+	c.cancel()
+	<-c.done
+	close(c.messages)
+	return c.client.Close()
+}
+
+// PubSubAsserter implements provider-specific message assertions
+type PubSubAsserter struct{}
+
+func (a *PubSubAsserter) AssertMessage(t testsuite.TestingT, msg testsuite.Message, event models.Event) {
+	pubsubMsg, ok := msg.Raw.(*pubsub.Message)
+	assert.True(t, ok, "raw message should be *pubsub.Message")
+	assert.Empty(t, pubsubMsg.OrderingKey, "ordering key should be empty without a template")
+
+	// Verify system metadata
+	metadata := msg.Metadata
+	assert.NotEmpty(t, metadata["timestamp"], "timestamp should be present")
+	assert.Equal(t, event.ID, metadata["event-id"], "event-id should match")
+	assert.Equal(t, event.Topic, metadata["topic"], "topic should match")
+
+	// Verify custom metadata
+	for k, v := range event.Metadata {
+		assert.Equal(t, v, metadata[k], "metadata key %s should match expected value", k)
+	}
+}
+
+type GCPPubSubPublishSuite struct {
+	testsuite.PublisherSuite
+	consumer *PubSubConsumer
+}
+
+func (s *GCPPubSubPublishSuite) SetupSuite() {
+	t := s.T()
+	t.Cleanup(testinfra.Start(t))
+	endpoint := testinfra.EnsureGCP()
+
+	cfg := &mqs.GCPPubSubConfig{
+		ProjectID:      "test-project",
+		TopicID:        "test-topic-" + uuid.New().String(),
+		SubscriptionID: "test-sub-" + uuid.New().String(),
+	}
+	ctx := context.Background()
+	require.NoError(t, testinfra.DeclareTestGCPInfrastructure(ctx, cfg, endpoint))
+	t.Cleanup(func() {
+		testinfra.TeardownTestGCPInfrastructure(ctx, cfg, endpoint)
+	})
+
+	provider, err := destgcppubsub.New(testutil.Registry.MetadataLoader(), destgcppubsub.WithEmulatorEndpoint(endpoint))
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("gcp_pubsub"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"project_id": cfg.ProjectID,
+			"topic":      cfg.TopicID,
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{
+			"service_account_json": testServiceAccountJSON,
+		}),
+	)
+
+	consumer, err := NewPubSubConsumer(endpoint, cfg)
+	require.NoError(t, err)
+	s.consumer = consumer
+
+	s.InitSuite(testsuite.Config{
+		Provider: provider,
+		Dest:     &dest,
+		Consumer: consumer,
+		Asserter: &PubSubAsserter{},
+	})
+}
+
+func (s *GCPPubSubPublishSuite) TearDownSuite() {
+	if s.consumer != nil {
+		s.consumer.Close()
+	}
+}
+
+func TestGCPPubSubPublishIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	suite.Run(t, new(GCPPubSubPublishSuite))
+}
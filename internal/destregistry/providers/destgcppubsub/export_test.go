@@ -0,0 +1,9 @@
+package destgcppubsub
+
+// WithEmulatorEndpoint publishes to a Pub/Sub emulator instead of Google
+// Cloud, without authentication nor TLS.
+func WithEmulatorEndpoint(endpoint string) Option {
+	return func(d *GCPPubSubDestination) {
+		d.emulatorEndpoint = endpoint
+	}
+}
@@ -2,6 +2,7 @@ package desthookdeck
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -34,8 +35,10 @@ func WithHTTPClient(client *http.Client) ProviderOption {
 // Provider implementation
 type HookdeckProvider struct {
 	*destregistry.BaseProvider
-	userAgent  string
-	httpClient *http.Client
+	userAgent    string
+	httpClient   *http.Client
+	egressPolicy *destregistry.EgressPolicy
+	proxy        *destregistry.ProxyConfig
 }
 
 // Ensure our provider implements the Provider interface
@@ -48,6 +51,20 @@ func WithUserAgent(userAgent string) ProviderOption {
 	}
 }
 
+// WithEgressPolicy restricts the addresses hookdeck requests can connect to
+func WithEgressPolicy(policy *destregistry.EgressPolicy) ProviderOption {
+	return func(p *HookdeckProvider) {
+		p.egressPolicy = policy
+	}
+}
+
+// WithProxy sets the proxy of destinations without their own proxy
+func WithProxy(proxy *destregistry.ProxyConfig) ProviderOption {
+	return func(p *HookdeckProvider) {
+		p.proxy = proxy
+	}
+}
+
 // Constructor
 func New(loader metadata.MetadataLoader, opts ...ProviderOption) (*HookdeckProvider, error) {
 	base, err := destregistry.NewBaseProvider(loader, "hookdeck")
@@ -87,9 +104,39 @@ func (p *HookdeckProvider) Validate(ctx context.Context, destination *models.Des
 		}
 	}
 
+	if _, err := destregistry.ResolveProxyConfig(p.proxy, destination); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// makeHTTPClient returns the provider's HTTP client if set, or a client using
+// the destination's proxy.
+func (p *HookdeckProvider) makeHTTPClient(destination *models.Destination, timeout *time.Duration) (*http.Client, error) {
+	if p.httpClient != nil {
+		return p.httpClient, nil
+	}
+
+	httpClientConfig := destregistry.HTTPClientConfig{
+		Timeout:      timeout,
+		UserAgent:    &p.userAgent,
+		EgressPolicy: p.egressPolicy,
+	}
+	proxyConfig, err := destregistry.ResolveProxyConfig(p.proxy, destination)
+	if err != nil {
+		return nil, err
+	}
+	if !proxyConfig.IsZero() {
+		proxy, err := proxyConfig.Build()
+		if err != nil {
+			return nil, err
+		}
+		httpClientConfig.Proxy = proxy
+	}
+	return p.BaseProvider.MakeHTTPClient(httpClientConfig), nil
+}
+
 // PublisherOption defines a function that configures a HookdeckPublisher
 type PublisherOption func(*HookdeckPublisher)
 
@@ -137,15 +184,11 @@ func (p *HookdeckProvider) CreatePublisher(ctx context.Context, destination *mod
 	// Create publisher options
 	var opts []PublisherOption
 
-	// Use the provider's HTTP client if set
-	if p.httpClient != nil {
-		opts = append(opts, PublisherWithClient(p.httpClient))
-	} else {
-		httpClient := p.BaseProvider.MakeHTTPClient(destregistry.HTTPClientConfig{
-			UserAgent: &p.userAgent,
-		})
-		opts = append(opts, PublisherWithClient(httpClient))
+	httpClient, err := p.makeHTTPClient(destination, nil)
+	if err != nil {
+		return nil, err
 	}
+	opts = append(opts, PublisherWithClient(httpClient))
 
 	// Use NewPublisher to create the publisher with options
 	publisher, err := NewPublisher(tokenString, opts...)
@@ -229,8 +272,14 @@ func (p *HookdeckProvider) Preprocess(newDestination *models.Destination, origin
 		// Create a background context for the verification request
 		ctx := context.Background()
 
-		// Verify token to get source information
-		sourceResponse, err := VerifyHookdeckToken(p.httpClient, ctx, parsedToken)
+		// Verify token to get source information, through the destination's
+		// proxy if any
+		timeout := 10 * time.Second
+		client, err := p.makeHTTPClient(newDestination, &timeout)
+		if err != nil {
+			return err
+		}
+		sourceResponse, err := VerifyHookdeckToken(client, ctx, parsedToken)
 		if err != nil {
 			// Return error from verification
 			return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
@@ -310,6 +359,10 @@ func (p *HookdeckPublisher) Publish(ctx context.Context, event *models.Event) (*
 	// Send the request
 	resp, err := p.client.Do(req)
 	if err != nil {
+		code := "request_failed"
+		if errors.Is(err, destregistry.ErrEgressBlocked) {
+			code = destregistry.ErrorCodeEgressBlocked
+		}
 		return &destregistry.Delivery{
 				Status: "failed",
 				Code:   "ERROR",
@@ -317,7 +370,7 @@ func (p *HookdeckPublisher) Publish(ctx context.Context, event *models.Event) (*
 					"error": "Request failed",
 				},
 			}, destregistry.NewErrDestinationPublishAttempt(err, "hookdeck", map[string]interface{}{
-				"error":   "request_failed",
+				"error":   code,
 				"message": err.Error(),
 			})
 	}
@@ -0,0 +1,391 @@
+package destkafka
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/jmespath/go-jmespath"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const (
+	SASLMechanismPlain       = "plain"
+	SASLMechanismSCRAMSHA256 = "scram-sha-256"
+	SASLMechanismSCRAMSHA512 = "scram-sha-512"
+)
+
+var brokerPattern = regexp.MustCompile(`^[\w\-.]+:\d{1,5}$`)
+
+// Configuration types
+type KafkaDestinationConfig struct {
+	Brokers              []string
+	Topic                string
+	PartitionKeyTemplate string
+	SASLMechanism        string
+	UseTLS               bool
+}
+
+type KafkaDestinationCredentials struct {
+	Username string // optional, enables SASL authentication
+	Password string
+}
+
+// Provider implementation
+type KafkaDestination struct {
+	*destregistry.BaseProvider
+	egressPolicy *destregistry.EgressPolicy
+}
+
+var _ destregistry.Provider = (*KafkaDestination)(nil)
+
+type Option func(*KafkaDestination)
+
+// WithEgressPolicy restricts the addresses of the brokers publishers can connect to
+func WithEgressPolicy(policy *destregistry.EgressPolicy) Option {
+	return func(d *KafkaDestination) {
+		d.egressPolicy = policy
+	}
+}
+
+// Constructor
+func New(loader metadata.MetadataLoader, opts ...Option) (*KafkaDestination, error) {
+	base, err := destregistry.NewBaseProvider(loader, "kafka")
+	if err != nil {
+		return nil, err
+	}
+	d := &KafkaDestination{BaseProvider: base}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+// Validate performs destination-specific validation
+func (d *KafkaDestination) Validate(ctx context.Context, destination *models.Destination) error {
+	_, _, err := d.resolveConfig(ctx, destination)
+	return err
+}
+
+// CreatePublisher creates a new publisher instance
+func (d *KafkaDestination) CreatePublisher(ctx context.Context, destination *models.Destination) (destregistry.Publisher, error) {
+	config, credentials, err := d.resolveConfig(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &kafka.Transport{}
+	if d.egressPolicy != nil {
+		// Also applies to the broker addresses returned by the cluster metadata
+		transport.Dial = d.egressPolicy.Dialer().DialContext
+	}
+	if config.UseTLS {
+		transport.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if credentials.Username != "" {
+		mechanism, err := saslMechanism(config.SASLMechanism, credentials)
+		if err != nil {
+			return nil, err
+		}
+		transport.SASL = mechanism
+	}
+
+	return &KafkaPublisher{
+		BasePublisher: &destregistry.BasePublisher{},
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(config.Brokers...),
+			Topic:        config.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+			Transport:    transport,
+		},
+		topic:                config.Topic,
+		partitionKeyTemplate: config.PartitionKeyTemplate,
+	}, nil
+}
+
+// resolveConfig parses the destination config and credentials
+func (d *KafkaDestination) resolveConfig(ctx context.Context, destination *models.Destination) (*KafkaDestinationConfig, *KafkaDestinationCredentials, error) {
+	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
+		return nil, nil, err
+	}
+
+	brokers := parseBrokers(destination.Config["brokers"])
+	for _, broker := range brokers {
+		if !brokerPattern.MatchString(broker) {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+				{
+					Field: "config.brokers",
+					Type:  "pattern",
+				},
+			})
+		}
+	}
+	if len(brokers) == 0 {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "config.brokers",
+				Type:  "required",
+			},
+		})
+	}
+
+	if tlsStr, ok := destination.Config["tls"]; ok {
+		if tlsStr != "on" && tlsStr != "true" && tlsStr != "false" {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+				{
+					Field: "config.tls",
+					Type:  "invalid",
+				},
+			})
+		}
+	}
+
+	// A password without a username can't be used for SASL
+	if destination.Credentials["username"] == "" && destination.Credentials["password"] != "" {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials.username",
+				Type:  "required",
+			},
+		})
+	}
+
+	saslMechanism := destination.Config["sasl_mechanism"]
+	if saslMechanism == "" {
+		saslMechanism = SASLMechanismPlain
+	}
+
+	return &KafkaDestinationConfig{
+		Brokers:              brokers,
+		Topic:                destination.Config["topic"],
+		PartitionKeyTemplate: destination.Config["partition_key_template"],
+		SASLMechanism:        saslMechanism,
+		UseTLS:               destination.Config["tls"] == "true" || destination.Config["tls"] == "on",
+	}, &KafkaDestinationCredentials{
+		Username: destination.Credentials["username"],
+		Password: destination.Credentials["password"],
+	}, nil
+}
+
+// ComputeTarget returns a human-readable target for display
+func (d *KafkaDestination) ComputeTarget(destination *models.Destination) destregistry.DestinationTarget {
+	brokers := parseBrokers(destination.Config["brokers"])
+	target := destination.Config["topic"]
+	if len(brokers) > 0 {
+		target = fmt.Sprintf("%s on %s", target, brokers[0])
+	}
+	return destregistry.DestinationTarget{
+		Target:    target,
+		TargetURL: "",
+	}
+}
+
+// Preprocess sets defaults and standardizes values
+func (d *KafkaDestination) Preprocess(newDestination *models.Destination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if newDestination.Config == nil {
+		return nil
+	}
+	if brokers := parseBrokers(newDestination.Config["brokers"]); len(brokers) > 0 {
+		newDestination.Config["brokers"] = strings.Join(brokers, ",")
+	}
+	if newDestination.Config["tls"] == "on" {
+		newDestination.Config["tls"] = "true"
+	} else if newDestination.Config["tls"] == "" {
+		newDestination.Config["tls"] = "false" // default to false if omitted
+	}
+	if _, _, err := d.resolveConfig(context.Background(), newDestination); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Publisher implementation
+type KafkaPublisher struct {
+	*destregistry.BasePublisher
+	writer               *kafka.Writer
+	topic                string
+	partitionKeyTemplate string
+}
+
+// Close handles resource cleanup
+func (p *KafkaPublisher) Close() error {
+	p.BasePublisher.StartClose()
+	return p.writer.Close()
+}
+
+// evaluatePartitionKey extracts the partition key from the event using the JMESPath template
+func (p *KafkaPublisher) evaluatePartitionKey(payload map[string]interface{}, eventID string) (string, error) {
+	if p.partitionKeyTemplate == "" {
+		return eventID, nil
+	}
+
+	result, err := jmespath.Search(p.partitionKeyTemplate, payload)
+	if err != nil {
+		return "", fmt.Errorf("error evaluating partition key template: %w", err)
+	}
+
+	switch v := result.(type) {
+	case nil:
+		return eventID, nil
+	case string:
+		if v == "" {
+			return eventID, nil
+		}
+		return v, nil
+	case float64:
+		return fmt.Sprintf("%g", v), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// Format prepares the event as a Kafka message. The event data is the value,
+// the metadata are the headers and the partition key template decides the key.
+func (p *KafkaPublisher) Format(ctx context.Context, event *models.Event) (*kafka.Message, error) {
+	value, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	metadata := p.BasePublisher.MakeMetadata(event, time.Now())
+	headers := make([]kafka.Header, 0, len(metadata))
+	metadataMap := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		headers = append(headers, kafka.Header{Key: k, Value: []byte(v)})
+		metadataMap[k] = v
+	}
+
+	dataMap := make(map[string]interface{}, len(event.Data))
+	for k, v := range event.Data {
+		dataMap[k] = v
+	}
+	partitionKey, err := p.evaluatePartitionKey(map[string]interface{}{
+		"metadata": metadataMap,
+		"data":     dataMap,
+	}, event.ID)
+	if err != nil {
+		// Fall back to the event ID if the template can't be evaluated
+		partitionKey = event.ID
+	}
+
+	return &kafka.Message{
+		Key:     []byte(partitionKey),
+		Value:   value,
+		Headers: headers,
+	}, nil
+}
+
+// Publish sends an event to the Kafka topic
+func (p *KafkaPublisher) Publish(ctx context.Context, event *models.Event) (*destregistry.Delivery, error) {
+	if err := p.BasePublisher.StartPublish(); err != nil {
+		return nil, err
+	}
+	defer p.BasePublisher.FinishPublish()
+
+	msg, err := p.Format(ctx, event)
+	if err != nil {
+		return nil, destregistry.NewErrDestinationPublishAttempt(err, "kafka", map[string]interface{}{
+			"error":   "format_failed",
+			"message": err.Error(),
+		})
+	}
+
+	if err := p.writer.WriteMessages(ctx, *msg); err != nil {
+		return &destregistry.Delivery{
+			Status: "failed",
+			Code:   "ERR",
+			Response: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, destregistry.NewErrDestinationPublishAttempt(err, "kafka", map[string]interface{}{
+			"error":         formatKafkaError(err),
+			"topic":         p.topic,
+			"partition_key": string(msg.Key),
+		})
+	}
+
+	return &destregistry.Delivery{
+		Status: "success",
+		Code:   "OK",
+		Response: map[string]interface{}{
+			"topic":         p.topic,
+			"partition_key": string(msg.Key),
+		},
+	}, nil
+}
+
+// Helper function to format Kafka errors
+func formatKafkaError(err error) string {
+	if errors.Is(err, destregistry.ErrEgressBlocked) {
+		return destregistry.ErrorCodeEgressBlocked
+	}
+	// A single message is written at a time, so only one error can be set
+	var writeErrs kafka.WriteErrors
+	if errors.As(err, &writeErrs) {
+		for _, writeErr := range writeErrs {
+			if writeErr != nil {
+				err = writeErr
+				break
+			}
+		}
+	}
+	var kafkaErr kafka.Error
+	if errors.As(err, &kafkaErr) {
+		switch kafkaErr {
+		case kafka.UnknownTopicOrPartition:
+			return "topic_not_found"
+		case kafka.TopicAuthorizationFailed, kafka.SASLAuthenticationFailed:
+			return "access_denied"
+		case kafka.MessageSizeTooLarge:
+			return "message_too_large"
+		}
+	}
+	if strings.Contains(err.Error(), "SASL") {
+		return "access_denied"
+	}
+	return "publish_failed"
+}
+
+func saslMechanism(mechanism string, credentials *KafkaDestinationCredentials) (sasl.Mechanism, error) {
+	switch mechanism {
+	case SASLMechanismSCRAMSHA256:
+		return scram.Mechanism(scram.SHA256, credentials.Username, credentials.Password)
+	case SASLMechanismSCRAMSHA512:
+		return scram.Mechanism(scram.SHA512, credentials.Username, credentials.Password)
+	default:
+		return plain.Mechanism{Username: credentials.Username, Password: credentials.Password}, nil
+	}
+}
+
+func parseBrokers(brokers string) []string {
+	var result []string
+	for _, broker := range strings.Split(brokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			result = append(result, broker)
+		}
+	}
+	return result
+}
+
+// NewKafkaPublisher creates a new publisher for testing purposes
+func NewKafkaPublisher(writer *kafka.Writer, partitionKeyTemplate string) *KafkaPublisher {
+	return &KafkaPublisher{
+		BasePublisher:        &destregistry.BasePublisher{},
+		writer:               writer,
+		topic:                writer.Topic,
+		partitionKeyTemplate: partitionKeyTemplate,
+	}
+}
@@ -0,0 +1,95 @@
+package destkafka_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destkafka"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaPublisher_Format(t *testing.T) {
+	t.Parallel()
+
+	event := models.Event{
+		ID:    "event-123",
+		Topic: "user.created",
+		Data: map[string]interface{}{
+			"user_id": "user-456",
+			"count":   float64(3),
+		},
+		Metadata: map[string]string{
+			"source": "signup",
+		},
+	}
+
+	testCases := []struct {
+		name                 string
+		partitionKeyTemplate string
+		expectedKey          string
+	}{
+		{
+			name:                 "default template uses event ID",
+			partitionKeyTemplate: "",
+			expectedKey:          "event-123",
+		},
+		{
+			name:                 "data field",
+			partitionKeyTemplate: "data.user_id",
+			expectedKey:          "user-456",
+		},
+		{
+			name:                 "numeric data field",
+			partitionKeyTemplate: "data.count",
+			expectedKey:          "3",
+		},
+		{
+			name:                 "metadata field",
+			partitionKeyTemplate: "metadata.source",
+			expectedKey:          "signup",
+		},
+		{
+			name:                 "join metadata fields",
+			partitionKeyTemplate: "join('-', [metadata.topic, metadata.\"event-id\"])",
+			expectedKey:          "user.created-event-123",
+		},
+		{
+			name:                 "missing field falls back to event ID",
+			partitionKeyTemplate: "data.missing",
+			expectedKey:          "event-123",
+		},
+		{
+			name:                 "invalid template falls back to event ID",
+			partitionKeyTemplate: "data.[invalid",
+			expectedKey:          "event-123",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			publisher := destkafka.NewKafkaPublisher(&kafka.Writer{Topic: "events"}, tc.partitionKeyTemplate)
+
+			msg, err := publisher.Format(context.Background(), &event)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedKey, string(msg.Key))
+
+			var value map[string]interface{}
+			require.NoError(t, json.Unmarshal(msg.Value, &value))
+			assert.Equal(t, map[string]interface{}(event.Data), value)
+
+			headers := map[string]string{}
+			for _, header := range msg.Headers {
+				headers[header.Key] = string(header.Value)
+			}
+			assert.Equal(t, "event-123", headers["event-id"])
+			assert.Equal(t, "user.created", headers["topic"])
+			assert.Equal(t, "signup", headers["source"])
+			assert.NotEmpty(t, headers["timestamp"])
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package destkafka_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destkafka"
+	testsuite "github.com/hookdeck/outpost/internal/destregistry/testing"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testinfra"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// KafkaConsumer implements testsuite.MessageConsumer
+type KafkaConsumer struct {
+	reader   *kafka.Reader
+	cancel   context.CancelFunc
+	done     chan struct{}
+	messages chan testsuite.Message
+}
+
+func NewKafkaConsumer(broker, topic string) (*KafkaConsumer, error) {
+	// Create the topic with a single partition so a plain reader sees every message
+	conn, err := kafka.Dial("tcp", broker)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.CreateTopics(kafka.TopicConfig{
+		Topic:             topic,
+		NumPartitions:     1,
+		ReplicationFactor: 1,
+	}); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consumer := &KafkaConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     []string{broker},
+			Topic:       topic,
+			StartOffset: kafka.FirstOffset,
+		}),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		messages: make(chan testsuite.Message, 100),
+	}
+
+	go func() {
+		defer close(consumer.done)
+		for {
+			msg, err := consumer.reader.ReadMessage(ctx)
+			if err != nil {
+				// The reader only fails once it's closed or the context is canceled
+				return
+			}
+			metadata := make(map[string]string)
+			for _, header := range msg.Headers {
+				metadata[header.Key] = string(header.Value)
+			}
+			consumer.messages <- testsuite.Message{
+				Data:     msg.Value,
+				Metadata: metadata,
+				Raw:      msg,
+			}
+		}
+	}()
+
+	return consumer, nil
+}
+
+func (c *KafkaConsumer) Consume() <-chan testsuite.Message {
+	return c.messages
+}
+
+func (c *KafkaConsumer) Close() error {
+	c.cancel()
+	<-c.done
+	close(c.messages)
+	return c.reader.Close()
+}
+
+// KafkaAsserter implements provider-specific message assertions
+type KafkaAsserter struct {
+	topic string
+}
+
+func (a *KafkaAsserter) AssertMessage(t testsuite.TestingT, msg testsuite.Message, event models.Event) {
+	record, ok := msg.Raw.(kafka.Message)
+	assert.True(t, ok, "raw message should be kafka.Message")
+
+	assert.Equal(t, a.topic, record.Topic)
+	assert.Equal(t, event.ID, string(record.Key), "key should default to the event ID")
+
+	// Verify system metadata
+	metadata := msg.Metadata
+	assert.NotEmpty(t, metadata["timestamp"], "timestamp should be present")
+	assert.Equal(t, event.ID, metadata["event-id"], "event-id should match")
+	assert.Equal(t, event.Topic, metadata["topic"], "topic should match")
+
+	// Verify custom metadata
+	for k, v := range event.Metadata {
+		assert.Equal(t, v, metadata[k], "metadata key %s should match expected value", k)
+	}
+}
+
+type KafkaPublishSuite struct {
+	testsuite.PublisherSuite
+	consumer *KafkaConsumer
+}
+
+func (s *KafkaPublishSuite) SetupSuite() {
+	t := s.T()
+	t.Cleanup(testinfra.Start(t))
+	broker := testinfra.EnsureKafka()
+	topic := "test-" + uuid.New().String()
+
+	provider, err := destkafka.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("kafka"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"brokers": broker,
+			"topic":   topic,
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+
+	consumer, err := NewKafkaConsumer(broker, topic)
+	require.NoError(t, err)
+	s.consumer = consumer
+
+	s.InitSuite(testsuite.Config{
+		Provider: provider,
+		Dest:     &dest,
+		Consumer: consumer,
+		Asserter: &KafkaAsserter{topic: topic},
+	})
+}
+
+func (s *KafkaPublishSuite) TearDownSuite() {
+	if s.consumer != nil {
+		s.consumer.Close()
+	}
+}
+
+func TestKafkaPublishIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	suite.Run(t, new(KafkaPublishSuite))
+}
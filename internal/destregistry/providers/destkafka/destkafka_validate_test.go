@@ -0,0 +1,167 @@
+package destkafka_test
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destkafka"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaDestination_Validate(t *testing.T) {
+	t.Parallel()
+
+	validDestination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("kafka"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"brokers": "broker-1.example.com:9092,broker-2.example.com:9092",
+			"topic":   "events",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{
+			"username": "user",
+			"password": "secret",
+		}),
+	)
+
+	kafkaDestination, err := destkafka.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	t.Run("should validate valid destination", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, kafkaDestination.Validate(context.Background(), &validDestination))
+	})
+
+	t.Run("should validate destination without credentials", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Credentials = map[string]string{}
+		assert.NoError(t, kafkaDestination.Validate(context.Background(), &dest))
+	})
+
+	t.Run("should validate invalid type", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Type = "invalid"
+		err := kafkaDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "type", validationErr.Errors[0].Field)
+		assert.Equal(t, "invalid_type", validationErr.Errors[0].Type)
+	})
+
+	t.Run("should validate missing config", func(t *testing.T) {
+		t.Parallel()
+		for _, field := range []string{"brokers", "topic"} {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			delete(dest.Config, field)
+			err := kafkaDestination.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "config."+field, validationErr.Errors[0].Field)
+			assert.Equal(t, "required", validationErr.Errors[0].Type)
+		}
+	})
+
+	t.Run("should validate malformed brokers", func(t *testing.T) {
+		t.Parallel()
+		for _, brokers := range []string{"broker-1.example.com", "broker-1.example.com:9092,", "kafka://broker:9092"} {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			dest.Config["brokers"] = brokers
+			err := kafkaDestination.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr, "brokers %q", brokers)
+			assert.Equal(t, "config.brokers", validationErr.Errors[0].Field)
+			assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+		}
+	})
+
+	t.Run("should validate sasl_mechanism", func(t *testing.T) {
+		t.Parallel()
+		for _, mechanism := range []string{"plain", "scram-sha-256", "scram-sha-512"} {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			dest.Config["sasl_mechanism"] = mechanism
+			assert.NoError(t, kafkaDestination.Validate(context.Background(), &dest))
+		}
+
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Config["sasl_mechanism"] = "gssapi"
+		err := kafkaDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.sasl_mechanism", validationErr.Errors[0].Field)
+		assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+	})
+
+	t.Run("should validate password without username", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Credentials = map[string]string{"password": "secret"}
+		err := kafkaDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "credentials.username", validationErr.Errors[0].Field)
+		assert.Equal(t, "required", validationErr.Errors[0].Type)
+	})
+
+	t.Run("should validate tls config values", func(t *testing.T) {
+		t.Parallel()
+		for value, valid := range map[string]bool{"true": true, "false": true, "on": true, "yes": false} {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			dest.Config["tls"] = value
+			err := kafkaDestination.Validate(context.Background(), &dest)
+			if valid {
+				assert.NoError(t, err, "tls %q", value)
+				continue
+			}
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr, "tls %q", value)
+			assert.Equal(t, "config.tls", validationErr.Errors[0].Field)
+		}
+	})
+}
+
+func TestKafkaDestination_Preprocess(t *testing.T) {
+	t.Parallel()
+
+	kafkaDestination, err := destkafka.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("kafka"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"brokers": " broker-1.example.com:9092 , broker-2.example.com:9092",
+			"topic":   "events",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+	require.NoError(t, kafkaDestination.Preprocess(&dest, nil, &destregistry.PreprocessDestinationOpts{}))
+	assert.Equal(t, "broker-1.example.com:9092,broker-2.example.com:9092", dest.Config["brokers"])
+	assert.Equal(t, "false", dest.Config["tls"])
+}
+
+func TestKafkaDestination_ComputeTarget(t *testing.T) {
+	t.Parallel()
+
+	kafkaDestination, err := destkafka.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("kafka"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"brokers": "broker-1.example.com:9092,broker-2.example.com:9092",
+			"topic":   "events",
+		}),
+	)
+	assert.Equal(t, "events on broker-1.example.com:9092", kafkaDestination.ComputeTarget(&dest).Target)
+}
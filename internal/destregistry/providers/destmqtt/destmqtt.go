@@ -0,0 +1,559 @@
+package destmqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/jmespath/go-jmespath"
+)
+
+// Default template that publishes to the event topic
+const defaultTopicTemplate = "metadata.topic"
+
+const (
+	dialTimeout = 10 * time.Second
+	keepAlive   = 30 // seconds
+)
+
+// MQTT 5 reason codes used to classify failures
+const (
+	reasonBadUsernameOrPassword = 0x86
+	reasonNotAuthorized         = 0x87
+	reasonTopicNameInvalid      = 0x90
+	reasonPacketTooLarge        = 0x95
+	reasonQuotaExceeded         = 0x97
+)
+
+type MQTTDestination struct {
+	*destregistry.BaseProvider
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+type MQTTDestinationConfig struct {
+	ServerURL     string
+	TopicTemplate string // JMESPath expression for generating MQTT topics
+	QoS           byte
+	Retain        bool
+	UseTLS        bool
+}
+
+type MQTTDestinationCredentials struct {
+	Username   string
+	Password   string
+	ClientCert string // PEM encoded client certificate
+	ClientKey  string // PEM encoded client private key
+}
+
+var _ destregistry.Provider = (*MQTTDestination)(nil)
+
+type Option func(*MQTTDestination)
+
+// WithEgressPolicy restricts the addresses publishers can connect to
+func WithEgressPolicy(policy *destregistry.EgressPolicy) Option {
+	return func(d *MQTTDestination) {
+		if policy != nil {
+			d.dialContext = policy.Dialer().DialContext
+		}
+	}
+}
+
+func New(loader metadata.MetadataLoader, opts ...Option) (*MQTTDestination, error) {
+	base, err := destregistry.NewBaseProvider(loader, "mqtt")
+	if err != nil {
+		return nil, err
+	}
+	d := &MQTTDestination{BaseProvider: base}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+func (d *MQTTDestination) Validate(ctx context.Context, destination *models.Destination) error {
+	_, _, err := d.resolveMetadata(ctx, destination)
+	return err
+}
+
+func (d *MQTTDestination) CreatePublisher(ctx context.Context, destination *models.Destination) (destregistry.Publisher, error) {
+	config, credentials, err := d.resolveMetadata(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	var tlsConfig *tls.Config
+	if config.UseTLS {
+		host, _, err := net.SplitHostPort(config.ServerURL)
+		if err != nil {
+			host = config.ServerURL
+		}
+		tlsConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: host,
+		}
+		if credentials.ClientCert != "" {
+			cert, err := tls.X509KeyPair([]byte(credentials.ClientCert), []byte(credentials.ClientKey))
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	// This should not fail as the template is validated in resolveMetadata
+	topicTemplate, err := jmespath.Compile(config.TopicTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MQTTPublisher{
+		BasePublisher: &destregistry.BasePublisher{},
+		serverURL:     config.ServerURL,
+		tlsConfig:     tlsConfig,
+		dialContext:   d.dialContext,
+		username:      credentials.Username,
+		password:      credentials.Password,
+		clientID:      newClientID(),
+		topicTemplate: topicTemplate,
+		qos:           config.QoS,
+		retain:        config.Retain,
+	}, nil
+}
+
+func (d *MQTTDestination) resolveMetadata(ctx context.Context, destination *models.Destination) (*MQTTDestinationConfig, *MQTTDestinationCredentials, error) {
+	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
+		return nil, nil, err
+	}
+
+	for _, key := range []string{"tls", "retain"} {
+		if value, ok := destination.Config[key]; ok && value != "on" && value != "true" && value != "false" {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+				{
+					Field: "config." + key,
+					Type:  "invalid",
+				},
+			})
+		}
+	}
+
+	// Use custom template if provided, otherwise use default
+	topicTemplate := destination.Config["topic_template"]
+	if topicTemplate == "" {
+		topicTemplate = defaultTopicTemplate
+	}
+	if _, err := jmespath.Compile(topicTemplate); err != nil {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "config.topic_template",
+				Type:  "pattern",
+			},
+		})
+	}
+
+	// The metadata pattern only allows 0, 1 or 2
+	qos := byte(1)
+	if value := destination.Config["qos"]; value != "" {
+		qos = value[0] - '0'
+	}
+
+	useTLS := isEnabled(destination.Config["tls"])
+	credentials := &MQTTDestinationCredentials{
+		Username:   destination.Credentials["username"],
+		Password:   destination.Credentials["password"],
+		ClientCert: destination.Credentials["client_cert"],
+		ClientKey:  destination.Credentials["client_key"],
+	}
+	if err := validateCredentials(credentials, useTLS); err != nil {
+		return nil, nil, err
+	}
+
+	return &MQTTDestinationConfig{
+		ServerURL:     destination.Config["server_url"],
+		TopicTemplate: topicTemplate,
+		QoS:           qos,
+		Retain:        isEnabled(destination.Config["retain"]),
+		UseTLS:        useTLS,
+	}, credentials, nil
+}
+
+func validateCredentials(credentials *MQTTDestinationCredentials, useTLS bool) error {
+	if credentials.Password != "" && credentials.Username == "" {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials.username",
+				Type:  "required",
+			},
+		})
+	}
+
+	if credentials.ClientCert == "" && credentials.ClientKey == "" {
+		return nil
+	}
+	if credentials.ClientCert == "" {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials.client_cert",
+				Type:  "required",
+			},
+		})
+	}
+	if credentials.ClientKey == "" {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials.client_key",
+				Type:  "required",
+			},
+		})
+	}
+	if _, err := tls.X509KeyPair([]byte(credentials.ClientCert), []byte(credentials.ClientKey)); err != nil {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials.client_cert",
+				Type:  "invalid",
+			},
+		})
+	}
+	// Client certificates are presented during the TLS handshake
+	if !useTLS {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "config.tls",
+				Type:  "required",
+			},
+		})
+	}
+	return nil
+}
+
+// Preprocess sets the default TLS and retain values to "false" if not provided
+func (d *MQTTDestination) Preprocess(newDestination *models.Destination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if newDestination.Config == nil {
+		return nil
+	}
+	for _, key := range []string{"tls", "retain"} {
+		if newDestination.Config[key] == "on" {
+			newDestination.Config[key] = "true"
+		} else if newDestination.Config[key] == "" {
+			newDestination.Config[key] = "false" // default to false if omitted
+		}
+	}
+	if newDestination.Config["qos"] == "" {
+		newDestination.Config["qos"] = "1"
+	}
+	if _, _, err := d.resolveMetadata(context.Background(), newDestination); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *MQTTDestination) ComputeTarget(destination *models.Destination) destregistry.DestinationTarget {
+	topicTemplate := destination.Config["topic_template"]
+	if topicTemplate == "" {
+		topicTemplate = defaultTopicTemplate
+	}
+	return destregistry.DestinationTarget{
+		Target:    topicTemplate + " on " + destination.Config["server_url"],
+		TargetURL: "",
+	}
+}
+
+type MQTTPublisher struct {
+	*destregistry.BasePublisher
+	serverURL     string
+	tlsConfig     *tls.Config // nil without TLS
+	dialContext   func(ctx context.Context, network, addr string) (net.Conn, error)
+	username      string
+	password      string
+	clientID      string
+	topicTemplate *jmespath.JMESPath
+	qos           byte
+	retain        bool
+	client        *paho.Client
+	mu            sync.Mutex
+}
+
+func (p *MQTTPublisher) Close() error {
+	p.BasePublisher.StartClose()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil && !isDone(p.client) {
+		p.client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+	}
+	return nil
+}
+
+// Format prepares the event as an MQTT 5 publish with the metadata as user
+// properties
+func (p *MQTTPublisher) Format(ctx context.Context, event *models.Event) (*paho.Publish, error) {
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := p.BasePublisher.MakeMetadata(event, time.Now())
+	topic, err := p.makeTopic(event, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort the user properties so messages are deterministic
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	userProperties := make(paho.UserProperties, 0, len(keys))
+	for _, k := range keys {
+		userProperties = append(userProperties, paho.UserProperty{Key: k, Value: metadata[k]})
+	}
+
+	return &paho.Publish{
+		Topic:   topic,
+		QoS:     p.qos,
+		Retain:  p.retain,
+		Payload: dataBytes,
+		Properties: &paho.PublishProperties{
+			ContentType: "application/json",
+			User:        userProperties,
+		},
+	}, nil
+}
+
+func (p *MQTTPublisher) makeTopic(event *models.Event, metadata map[string]string) (string, error) {
+	metadataMap := make(map[string]interface{}, len(metadata))
+	for k, v := range metadata {
+		metadataMap[k] = v
+	}
+	templateData := map[string]interface{}{
+		"data":     map[string]interface{}(event.Data),
+		"metadata": metadataMap,
+	}
+
+	result, err := p.topicTemplate.Search(templateData)
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate topic template: %w", err)
+	}
+	topic, ok := result.(string)
+	if !ok || topic == "" {
+		return "", errors.New("topic template must produce a non-empty string")
+	}
+	// Wildcards are only valid in subscriptions
+	if strings.ContainsAny(topic, "+#\x00") {
+		return "", fmt.Errorf("topic %q must not contain wildcards", topic)
+	}
+	return topic, nil
+}
+
+func (p *MQTTPublisher) Publish(ctx context.Context, event *models.Event) (*destregistry.Delivery, error) {
+	if err := p.BasePublisher.StartPublish(); err != nil {
+		return nil, err
+	}
+	defer p.BasePublisher.FinishPublish()
+
+	msg, err := p.Format(ctx, event)
+	if err != nil {
+		return nil, destregistry.NewErrDestinationPublishAttempt(err, "mqtt", map[string]interface{}{
+			"error":   "format_failed",
+			"message": err.Error(),
+		})
+	}
+
+	client, err := p.ensureConnection(ctx)
+	if err != nil {
+		return nil, destregistry.NewErrDestinationPublishAttempt(err, "mqtt", map[string]interface{}{
+			"error":   formatMQTTError(err, "connection_failed"),
+			"message": err.Error(),
+		})
+	}
+
+	resp, err := client.Publish(ctx, msg)
+	// QoS 2 publishes rejected by the broker return a reason code without an error
+	if resp != nil && resp.ReasonCode >= 0x80 {
+		err = &reasonCodeError{code: resp.ReasonCode, op: "publish", err: err}
+	}
+	if err != nil {
+		return &destregistry.Delivery{
+			Status: "failed",
+			Code:   "ERR",
+			Response: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, destregistry.NewErrDestinationPublishAttempt(err, "mqtt", map[string]interface{}{
+			"error":   formatMQTTError(err, "publish_failed"),
+			"topic":   msg.Topic,
+			"message": err.Error(),
+		})
+	}
+
+	return &destregistry.Delivery{
+		Status: "success",
+		Code:   "OK",
+		Response: map[string]interface{}{
+			"topic":  msg.Topic,
+			"qos":    int(msg.QoS),
+			"retain": msg.Retain,
+		},
+	}, nil
+}
+
+// ensureConnection returns the connected client, connecting again if the
+// previous connection was lost. Publishers are cached by the registry, so a
+// connection is reused across publishes to the same destination.
+func (p *MQTTPublisher) ensureConnection(ctx context.Context) (*paho.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil && !isDone(p.client) {
+		return p.client, nil
+	}
+
+	dialContext := p.dialContext
+	if dialContext == nil {
+		dialContext = (&net.Dialer{}).DialContext
+	}
+	// Bounds the TLS handshake too
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	conn, err := dialContext(dialCtx, "tcp", p.serverURL)
+	if err == nil && p.tlsConfig != nil {
+		tlsConn := tls.Client(conn, p.tlsConfig)
+		if err = tlsConn.HandshakeContext(dialCtx); err != nil {
+			conn.Close()
+		} else {
+			// tls.Conn isn't safe for concurrent writes
+			conn = packets.NewThreadSafeConn(tlsConn)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	client := paho.NewClient(paho.ClientConfig{
+		ClientID:           p.clientID,
+		Conn:               conn,
+		OnClientError:      func(error) {},
+		OnServerDisconnect: func(*paho.Disconnect) {},
+	})
+	connect := &paho.Connect{
+		ClientID:   p.clientID,
+		KeepAlive:  keepAlive,
+		CleanStart: true,
+	}
+	if p.username != "" {
+		connect.Username = p.username
+		connect.UsernameFlag = true
+	}
+	if p.password != "" {
+		connect.Password = []byte(p.password)
+		connect.PasswordFlag = true
+	}
+	connack, err := client.Connect(ctx, connect)
+	if err != nil {
+		if connack != nil && connack.ReasonCode >= 0x80 {
+			return nil, &reasonCodeError{code: connack.ReasonCode, op: "connect", err: err}
+		}
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	p.client = client
+	return client, nil
+}
+
+func isDone(client *paho.Client) bool {
+	select {
+	case <-client.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// reasonCodeError is a failure reported by the broker with an MQTT 5 reason code
+type reasonCodeError struct {
+	code byte
+	op   string
+	err  error
+}
+
+func (e *reasonCodeError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("%s failed with reason code 0x%02x: %s", e.op, e.code, e.err)
+	}
+	return fmt.Sprintf("%s failed with reason code 0x%02x", e.op, e.code)
+}
+
+func (e *reasonCodeError) Unwrap() error {
+	return e.err
+}
+
+// Helper function to format MQTT errors
+func formatMQTTError(err error, fallback string) string {
+	if errors.Is(err, destregistry.ErrEgressBlocked) {
+		return destregistry.ErrorCodeEgressBlocked
+	}
+	var reasonErr *reasonCodeError
+	if errors.As(err, &reasonErr) {
+		switch reasonErr.code {
+		case reasonBadUsernameOrPassword, reasonNotAuthorized:
+			return "access_denied"
+		case reasonTopicNameInvalid:
+			return "invalid_topic"
+		case reasonPacketTooLarge:
+			return "message_too_large"
+		case reasonQuotaExceeded:
+			return "quota_exceeded"
+		}
+		return fallback
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+	return fallback
+}
+
+// newClientID returns a unique client ID for each publisher, as brokers
+// disconnect clients when another one connects with the same ID. MQTT
+// brokers must accept IDs of up to 23 characters.
+func newClientID() string {
+	return "outpost-" + strings.ReplaceAll(uuid.New().String(), "-", "")[:15]
+}
+
+func isEnabled(value string) bool {
+	return value == "true" || value == "on"
+}
+
+// ===== TEST HELPERS =====
+
+// NewMQTTPublisher creates a new publisher for testing purposes
+func NewMQTTPublisher(topicTemplate string, qos byte, retain bool) (*MQTTPublisher, error) {
+	if topicTemplate == "" {
+		topicTemplate = defaultTopicTemplate
+	}
+	tmpl, err := jmespath.Compile(topicTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &MQTTPublisher{
+		BasePublisher: &destregistry.BasePublisher{},
+		topicTemplate: tmpl,
+		qos:           qos,
+		retain:        retain,
+	}, nil
+}
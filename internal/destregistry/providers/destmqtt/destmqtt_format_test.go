@@ -0,0 +1,99 @@
+package destmqtt_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destmqtt"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMQTTPublisher_Format(t *testing.T) {
+	t.Parallel()
+
+	event := models.Event{
+		ID:    "event-123",
+		Topic: "user.created",
+		Data: map[string]interface{}{
+			"customer_id": "cus_456",
+		},
+		Metadata: map[string]string{
+			"source": "signup",
+		},
+	}
+
+	t.Run("should publish the data with metadata as user properties", func(t *testing.T) {
+		t.Parallel()
+		publisher, err := destmqtt.NewMQTTPublisher("", 1, true)
+		require.NoError(t, err)
+
+		msg, err := publisher.Format(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, "user.created", msg.Topic, "should default to the event topic")
+		assert.Equal(t, byte(1), msg.QoS)
+		assert.True(t, msg.Retain)
+
+		var data map[string]interface{}
+		require.NoError(t, json.Unmarshal(msg.Payload, &data))
+		assert.Equal(t, map[string]interface{}(event.Data), data)
+
+		require.NotNil(t, msg.Properties)
+		assert.Equal(t, "application/json", msg.Properties.ContentType)
+		assert.Equal(t, "event-123", msg.Properties.User.Get("event-id"))
+		assert.Equal(t, "user.created", msg.Properties.User.Get("topic"))
+		assert.Equal(t, "signup", msg.Properties.User.Get("source"))
+		assert.NotEmpty(t, msg.Properties.User.Get("timestamp"))
+	})
+
+	testCases := []struct {
+		name          string
+		topicTemplate string
+		expectedTopic string
+		expectError   bool
+	}{
+		{
+			name:          "prefixed topic",
+			topicTemplate: "join('/', ['outpost', metadata.topic])",
+			expectedTopic: "outpost/user.created",
+		},
+		{
+			name:          "metadata and data fields",
+			topicTemplate: "join('/', ['tenants', data.customer_id, metadata.source])",
+			expectedTopic: "tenants/cus_456/signup",
+		},
+		{
+			name:          "missing field",
+			topicTemplate: "data.missing",
+			expectError:   true,
+		},
+		{
+			name:          "non string result",
+			topicTemplate: "data",
+			expectError:   true,
+		},
+		{
+			name:          "wildcard",
+			topicTemplate: "'outpost/#'",
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			publisher, err := destmqtt.NewMQTTPublisher(tc.topicTemplate, 0, false)
+			require.NoError(t, err)
+
+			msg, err := publisher.Format(context.Background(), &event)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTopic, msg.Topic)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package destmqtt_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destmqtt"
+	testsuite "github.com/hookdeck/outpost/internal/destregistry/testing"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testinfra"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// MQTTConsumer implements testsuite.MessageConsumer
+type MQTTConsumer struct {
+	client   *paho.Client
+	messages chan testsuite.Message
+}
+
+func NewMQTTConsumer(serverURL, topicFilter string) (*MQTTConsumer, error) {
+	conn, err := net.Dial("tcp", serverURL)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer := &MQTTConsumer{
+		messages: make(chan testsuite.Message, 100),
+	}
+	consumer.client = paho.NewClient(paho.ClientConfig{
+		Conn: conn,
+		OnPublishReceived: []func(paho.PublishReceived) (bool, error){
+			func(pr paho.PublishReceived) (bool, error) {
+				metadata := make(map[string]string)
+				if pr.Packet.Properties != nil {
+					for _, property := range pr.Packet.Properties.User {
+						metadata[property.Key] = property.Value
+					}
+				}
+				consumer.messages <- testsuite.Message{
+					Data:     pr.Packet.Payload,
+					Metadata: metadata,
+					Raw:      pr.Packet,
+				}
+				return true, nil
+			},
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := consumer.client.Connect(ctx, &paho.Connect{
+		ClientID:   "consumer-" + uuid.New().String()[:8],
+		KeepAlive:  30,
+		CleanStart: true,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := consumer.client.Subscribe(ctx, &paho.Subscribe{
+		Subscriptions: []paho.SubscribeOptions{{Topic: topicFilter, QoS: 1}},
+	}); err != nil {
+		consumer.client.Disconnect(&paho.Disconnect{})
+		return nil, err
+	}
+	return consumer, nil
+}
+
+func (c *MQTTConsumer) Consume() <-chan testsuite.Message {
+	return c.messages
+}
+
+func (c *MQTTConsumer) Close() error {
+	return c.client.Disconnect(&paho.Disconnect{})
+}
+
+// MQTTAsserter implements provider-specific message assertions
+type MQTTAsserter struct {
+	topicPrefix string
+}
+
+func (a *MQTTAsserter) AssertMessage(t testsuite.TestingT, msg testsuite.Message, event models.Event) {
+	packet, ok := msg.Raw.(*paho.Publish)
+	assert.True(t, ok, "raw message should be *paho.Publish")
+
+	assert.Equal(t, a.topicPrefix+"/"+event.Topic, packet.Topic, "topic should be rendered from the template")
+	assert.Equal(t, byte(1), packet.QoS)
+	assert.Equal(t, "application/json", packet.Properties.ContentType)
+
+	// Verify system metadata
+	metadata := msg.Metadata
+	assert.NotEmpty(t, metadata["timestamp"], "timestamp should be present")
+	assert.Equal(t, event.ID, metadata["event-id"], "event-id should match")
+	assert.Equal(t, event.Topic, metadata["topic"], "topic should match")
+
+	// Verify custom metadata
+	for k, v := range event.Metadata {
+		assert.Equal(t, v, metadata[k], "metadata key %s should match expected value", k)
+	}
+}
+
+type MQTTPublishSuite struct {
+	testsuite.PublisherSuite
+	consumer *MQTTConsumer
+}
+
+func (s *MQTTPublishSuite) SetupSuite() {
+	t := s.T()
+	t.Cleanup(testinfra.Start(t))
+	serverURL := testinfra.EnsureMQTT()
+	topicPrefix := "test/" + uuid.New().String()
+
+	host, port, err := net.SplitHostPort(serverURL)
+	require.NoError(t, err)
+	provider, err := destmqtt.New(testutil.Registry.MetadataLoader(),
+		destmqtt.WithDialContext(testsuite.RedirectDialer((&net.Dialer{}).DialContext, host)))
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("mqtt"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url":     net.JoinHostPort("broker.example.com", port),
+			"topic_template": fmt.Sprintf("join('/', ['%s', metadata.topic])", topicPrefix),
+			"qos":            "1",
+			"tls":            "false",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+
+	consumer, err := NewMQTTConsumer(serverURL, topicPrefix+"/#")
+	require.NoError(t, err)
+	s.consumer = consumer
+
+	s.InitSuite(testsuite.Config{
+		Provider: provider,
+		Dest:     &dest,
+		Consumer: consumer,
+		Asserter: &MQTTAsserter{topicPrefix: topicPrefix},
+	})
+}
+
+func (s *MQTTPublishSuite) TearDownSuite() {
+	if s.consumer != nil {
+		s.consumer.Close()
+	}
+}
+
+func TestMQTTPublishIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	suite.Run(t, new(MQTTPublishSuite))
+}
@@ -0,0 +1,230 @@
+package destmqtt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"maps"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destmqtt"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateClientCert returns a self-signed PEM encoded certificate and key
+func generateClientCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "outpost"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+func TestMQTTDestination_Validate(t *testing.T) {
+	t.Parallel()
+
+	validDestination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("mqtt"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": "broker.example.com:8883",
+			"tls":        "true",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{
+			"username": "outpost",
+			"password": "secret",
+		}),
+	)
+
+	mqttDestination, err := destmqtt.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	clientCert, clientKey := generateClientCert(t)
+
+	t.Run("should validate valid destination", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, mqttDestination.Validate(context.Background(), &validDestination))
+	})
+
+	t.Run("should validate invalid type", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Type = "invalid"
+		err := mqttDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "type", validationErr.Errors[0].Field)
+		assert.Equal(t, "invalid_type", validationErr.Errors[0].Type)
+	})
+
+	t.Run("should validate missing server_url", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		delete(dest.Config, "server_url")
+		err := mqttDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.server_url", validationErr.Errors[0].Field)
+		assert.Equal(t, "required", validationErr.Errors[0].Type)
+	})
+
+	t.Run("should validate malformed config", func(t *testing.T) {
+		t.Parallel()
+		testCases := []struct {
+			field string
+			value string
+		}{
+			{"server_url", "mqtt://broker.example.com:8883"},
+			{"server_url", "localhost:1883"},
+			{"server_url", "127.0.0.1:1883"},
+			{"topic_template", "metadata.[invalid"},
+			{"qos", "3"},
+			{"qos", "one"},
+		}
+		for _, tc := range testCases {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			dest.Config[tc.field] = tc.value
+			err := mqttDestination.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr, "%s %q", tc.field, tc.value)
+			assert.Equal(t, "config."+tc.field, validationErr.Errors[0].Field)
+			assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+		}
+	})
+
+	t.Run("should validate checkbox values", func(t *testing.T) {
+		t.Parallel()
+		for _, field := range []string{"tls", "retain"} {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			dest.Config[field] = "yes"
+			err := mqttDestination.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr, field)
+			assert.Equal(t, "config."+field, validationErr.Errors[0].Field)
+			assert.Equal(t, "invalid", validationErr.Errors[0].Type)
+		}
+	})
+
+	t.Run("should validate optional config", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Config["topic_template"] = "join('/', ['outpost', metadata.topic])"
+		dest.Config["qos"] = "2"
+		dest.Config["retain"] = "true"
+		assert.NoError(t, mqttDestination.Validate(context.Background(), &dest))
+	})
+
+	t.Run("should allow each authentication method", func(t *testing.T) {
+		t.Parallel()
+		for _, credentials := range []map[string]string{
+			{},
+			{"username": "outpost"},
+			{"client_cert": clientCert, "client_key": clientKey},
+			{"username": "outpost", "password": "secret", "client_cert": clientCert, "client_key": clientKey},
+		} {
+			dest := validDestination
+			dest.Credentials = credentials
+			assert.NoError(t, mqttDestination.Validate(context.Background(), &dest), "credentials %v", credentials)
+		}
+	})
+
+	t.Run("should validate invalid credentials", func(t *testing.T) {
+		t.Parallel()
+		testCases := []struct {
+			credentials map[string]string
+			field       string
+			errType     string
+		}{
+			{map[string]string{"password": "secret"}, "credentials.username", "required"},
+			{map[string]string{"client_key": clientKey}, "credentials.client_cert", "required"},
+			{map[string]string{"client_cert": clientCert}, "credentials.client_key", "required"},
+			{map[string]string{"client_cert": "not-a-cert", "client_key": clientKey}, "credentials.client_cert", "invalid"},
+		}
+		for _, tc := range testCases {
+			dest := validDestination
+			dest.Credentials = tc.credentials
+			err := mqttDestination.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr, "credentials %v", tc.credentials)
+			assert.Equal(t, tc.field, validationErr.Errors[0].Field)
+			assert.Equal(t, tc.errType, validationErr.Errors[0].Type)
+		}
+	})
+
+	t.Run("should require tls for client certificates", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Config["tls"] = "false"
+		dest.Credentials = map[string]string{"client_cert": clientCert, "client_key": clientKey}
+		err := mqttDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.tls", validationErr.Errors[0].Field)
+		assert.Equal(t, "required", validationErr.Errors[0].Type)
+	})
+}
+
+func TestMQTTDestination_Preprocess(t *testing.T) {
+	t.Parallel()
+
+	mqttDestination, err := destmqtt.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("mqtt"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": "broker.example.com:8883",
+			"tls":        "on",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+	require.NoError(t, mqttDestination.Preprocess(&dest, nil, &destregistry.PreprocessDestinationOpts{}))
+	assert.Equal(t, "true", dest.Config["tls"])
+	assert.Equal(t, "false", dest.Config["retain"])
+	assert.Equal(t, "1", dest.Config["qos"])
+}
+
+func TestMQTTDestination_ComputeTarget(t *testing.T) {
+	t.Parallel()
+
+	mqttDestination, err := destmqtt.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("mqtt"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": "broker.example.com:8883",
+		}),
+	)
+	target := mqttDestination.ComputeTarget(&dest)
+	assert.Equal(t, "metadata.topic on broker.example.com:8883", target.Target)
+	assert.Empty(t, target.TargetURL)
+
+	dest.Config["topic_template"] = "join('/', ['outpost', metadata.topic])"
+	assert.Equal(t, "join('/', ['outpost', metadata.topic]) on broker.example.com:8883", mqttDestination.ComputeTarget(&dest).Target)
+}
@@ -0,0 +1,13 @@
+package destmqtt
+
+import (
+	"context"
+	"net"
+)
+
+// WithDialContext lets tests connect to a local MQTT broker.
+func WithDialContext(dialContext func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(d *MQTTDestination) {
+		d.dialContext = dialContext
+	}
+}
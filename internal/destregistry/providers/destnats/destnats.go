@@ -0,0 +1,470 @@
+package destnats
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nkeys"
+)
+
+// flushTimeout bounds the round trip confirming core NATS publishes when the
+// context has no deadline.
+const flushTimeout = 5 * time.Second
+
+type NATSDestination struct {
+	*destregistry.BaseProvider
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+type NATSDestinationConfig struct {
+	ServerURL string
+	Subject   string
+	JetStream bool
+	Stream    string // optional, expected stream of JetStream publishes
+	UseTLS    bool
+}
+
+// NATSDestinationCredentials holds one of the supported authentication
+// methods, or none.
+type NATSDestinationCredentials struct {
+	Username string
+	Password string
+	Token    string
+	NKeySeed string
+	Creds    string // contents of a .creds file with a user JWT and NKey seed
+}
+
+var _ destregistry.Provider = (*NATSDestination)(nil)
+
+type Option func(*NATSDestination)
+
+// WithEgressPolicy restricts the addresses publishers can connect to
+func WithEgressPolicy(policy *destregistry.EgressPolicy) Option {
+	return func(d *NATSDestination) {
+		if policy != nil {
+			d.dialContext = policy.Dialer().DialContext
+		}
+	}
+}
+
+func New(loader metadata.MetadataLoader, opts ...Option) (*NATSDestination, error) {
+	base, err := destregistry.NewBaseProvider(loader, "nats")
+	if err != nil {
+		return nil, err
+	}
+	d := &NATSDestination{BaseProvider: base}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+func (d *NATSDestination) Validate(ctx context.Context, destination *models.Destination) error {
+	_, _, err := d.resolveMetadata(ctx, destination)
+	return err
+}
+
+func (d *NATSDestination) CreatePublisher(ctx context.Context, destination *models.Destination) (destregistry.Publisher, error) {
+	config, credentials, err := d.resolveMetadata(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := connectOptions(config, credentials)
+	if err != nil {
+		return nil, err
+	}
+	if d.dialContext != nil {
+		opts = append(opts, nats.SetCustomDialer(customDialer(d.dialContext)))
+	}
+	return &NATSPublisher{
+		BasePublisher: &destregistry.BasePublisher{},
+		url:           natsURL(config),
+		opts:          opts,
+		subject:       config.Subject,
+		jetStream:     config.JetStream,
+		stream:        config.Stream,
+	}, nil
+}
+
+func (d *NATSDestination) resolveMetadata(ctx context.Context, destination *models.Destination) (*NATSDestinationConfig, *NATSDestinationCredentials, error) {
+	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
+		return nil, nil, err
+	}
+
+	for _, key := range []string{"tls", "jetstream"} {
+		if value, ok := destination.Config[key]; ok && value != "on" && value != "true" && value != "false" {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+				{
+					Field: "config." + key,
+					Type:  "invalid",
+				},
+			})
+		}
+	}
+
+	credentials := &NATSDestinationCredentials{
+		Username: destination.Credentials["username"],
+		Password: destination.Credentials["password"],
+		Token:    destination.Credentials["token"],
+		NKeySeed: destination.Credentials["nkey_seed"],
+		Creds:    destination.Credentials["creds"],
+	}
+	if err := validateCredentials(credentials); err != nil {
+		return nil, nil, err
+	}
+
+	return &NATSDestinationConfig{
+		ServerURL: destination.Config["server_url"],
+		Subject:   destination.Config["subject"],
+		JetStream: isEnabled(destination.Config["jetstream"]),
+		Stream:    destination.Config["stream"],
+		UseTLS:    isEnabled(destination.Config["tls"]),
+	}, credentials, nil
+}
+
+// validateCredentials checks that at most one authentication method is set
+// and that its values can be parsed.
+func validateCredentials(credentials *NATSDestinationCredentials) error {
+	methods := 0
+	for _, set := range []bool{
+		credentials.Username != "" || credentials.Password != "",
+		credentials.Token != "",
+		credentials.NKeySeed != "",
+		credentials.Creds != "",
+	} {
+		if set {
+			methods++
+		}
+	}
+	if methods > 1 {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials",
+				Type:  "invalid",
+			},
+		})
+	}
+
+	if credentials.Password != "" && credentials.Username == "" {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials.username",
+				Type:  "required",
+			},
+		})
+	}
+	if credentials.NKeySeed != "" {
+		if _, err := nkeys.FromSeed([]byte(credentials.NKeySeed)); err != nil {
+			return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+				{
+					Field: "credentials.nkey_seed",
+					Type:  "invalid",
+				},
+			})
+		}
+	}
+	if credentials.Creds != "" {
+		if _, _, err := parseCreds(credentials.Creds); err != nil {
+			return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+				{
+					Field: "credentials.creds",
+					Type:  "invalid",
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// Preprocess sets the default TLS and JetStream values to "false" if not provided
+func (d *NATSDestination) Preprocess(newDestination *models.Destination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if newDestination.Config == nil {
+		return nil
+	}
+	for _, key := range []string{"tls", "jetstream"} {
+		if newDestination.Config[key] == "on" {
+			newDestination.Config[key] = "true"
+		} else if newDestination.Config[key] == "" {
+			newDestination.Config[key] = "false" // default to false if omitted
+		}
+	}
+	if _, _, err := d.resolveMetadata(context.Background(), newDestination); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *NATSDestination) ComputeTarget(destination *models.Destination) destregistry.DestinationTarget {
+	return destregistry.DestinationTarget{
+		Target:    destination.Config["subject"] + " on " + destination.Config["server_url"],
+		TargetURL: "",
+	}
+}
+
+type NATSPublisher struct {
+	*destregistry.BasePublisher
+	url       string
+	opts      []nats.Option
+	subject   string
+	jetStream bool
+	stream    string
+	conn      *nats.Conn
+	js        jetstream.JetStream
+	mu        sync.Mutex
+}
+
+func (p *NATSPublisher) Close() error {
+	p.BasePublisher.StartClose()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn != nil {
+		// Drain flushes pending messages before closing
+		if err := p.conn.Drain(); err != nil {
+			p.conn.Close()
+		}
+	}
+	return nil
+}
+
+// Format prepares the event as a NATS message with the metadata as headers
+func (p *NATSPublisher) Format(ctx context.Context, event *models.Event) (*nats.Msg, error) {
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := nats.NewMsg(p.subject)
+	msg.Data = dataBytes
+	for k, v := range p.BasePublisher.MakeMetadata(event, time.Now()) {
+		msg.Header.Set(k, v)
+	}
+	return msg, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event *models.Event) (*destregistry.Delivery, error) {
+	if err := p.BasePublisher.StartPublish(); err != nil {
+		return nil, err
+	}
+	defer p.BasePublisher.FinishPublish()
+
+	msg, err := p.Format(ctx, event)
+	if err != nil {
+		return nil, destregistry.NewErrDestinationPublishAttempt(err, "nats", map[string]interface{}{
+			"error":   "format_failed",
+			"message": err.Error(),
+		})
+	}
+
+	conn, js, err := p.ensureConnection()
+	if err != nil {
+		return nil, destregistry.NewErrDestinationPublishAttempt(err, "nats", map[string]interface{}{
+			"error":   formatNATSError(err, "connection_failed"),
+			"message": err.Error(),
+		})
+	}
+
+	if !p.jetStream {
+		if err := publishCore(ctx, conn, msg); err != nil {
+			return failedDelivery(err), destregistry.NewErrDestinationPublishAttempt(err, "nats", map[string]interface{}{
+				"error":   formatNATSError(err, "publish_failed"),
+				"subject": p.subject,
+				"message": err.Error(),
+			})
+		}
+		return &destregistry.Delivery{
+			Status: "success",
+			Code:   "OK",
+			Response: map[string]interface{}{
+				"subject": p.subject,
+			},
+		}, nil
+	}
+
+	// The event ID deduplicates retries within the stream's duplicate window
+	publishOpts := []jetstream.PublishOpt{jetstream.WithMsgID(event.ID)}
+	if p.stream != "" {
+		publishOpts = append(publishOpts, jetstream.WithExpectStream(p.stream))
+	}
+	ack, err := js.PublishMsg(ctx, msg, publishOpts...)
+	if err != nil {
+		return failedDelivery(err), destregistry.NewErrDestinationPublishAttempt(err, "nats", map[string]interface{}{
+			"error":   formatNATSError(err, "publish_failed"),
+			"subject": p.subject,
+			"message": err.Error(),
+		})
+	}
+	return &destregistry.Delivery{
+		Status: "success",
+		Code:   "OK",
+		Response: map[string]interface{}{
+			"subject":   p.subject,
+			"stream":    ack.Stream,
+			"sequence":  ack.Sequence,
+			"duplicate": ack.Duplicate,
+		},
+	}, nil
+}
+
+// publishCore publishes the message and waits for the server to process it.
+// Core NATS doesn't acknowledge messages, so this only confirms the server
+// received it, not that a subscriber did.
+func publishCore(ctx context.Context, conn *nats.Conn, msg *nats.Msg) error {
+	if err := conn.PublishMsg(msg); err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return conn.FlushTimeout(flushTimeout)
+	}
+	return conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) ensureConnection() (*nats.Conn, jetstream.JetStream, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn != nil && !p.conn.IsClosed() {
+		return p.conn, p.js, nil
+	}
+
+	conn, err := nats.Connect(p.url, p.opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+	p.conn = conn
+	p.js = js
+	return conn, js, nil
+}
+
+func failedDelivery(err error) *destregistry.Delivery {
+	return &destregistry.Delivery{
+		Status: "failed",
+		Code:   "ERR",
+		Response: map[string]interface{}{
+			"error": err.Error(),
+		},
+	}
+}
+
+// customDialer adapts a dial function to nats.CustomDialer. The NATS client
+// applies TLS itself on top of the connection.
+type customDialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (d customDialer) Dial(network, address string) (net.Conn, error) {
+	return d(context.Background(), network, address)
+}
+
+// Helper function to format NATS errors
+func formatNATSError(err error, fallback string) string {
+	var apiErr *jetstream.APIError
+	switch {
+	case errors.Is(err, destregistry.ErrEgressBlocked):
+		return destregistry.ErrorCodeEgressBlocked
+	case errors.Is(err, jetstream.ErrNoStreamResponse):
+		return "stream_not_found"
+	case errors.Is(err, nats.ErrAuthorization), errors.Is(err, nats.ErrAuthExpired), errors.Is(err, nats.ErrPermissionViolation):
+		return "access_denied"
+	case errors.Is(err, nats.ErrMaxPayload):
+		return "message_too_large"
+	case errors.Is(err, nats.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &apiErr):
+		return "jetstream_error"
+	}
+	return fallback
+}
+
+func connectOptions(config *NATSDestinationConfig, credentials *NATSDestinationCredentials) ([]nats.Option, error) {
+	opts := []nats.Option{
+		nats.Name("outpost"),
+		// Publishers are long-lived, reconnect instead of failing deliveries
+		nats.MaxReconnects(-1),
+	}
+	if config.UseTLS {
+		opts = append(opts, nats.Secure(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+
+	switch {
+	case credentials.Username != "":
+		opts = append(opts, nats.UserInfo(credentials.Username, credentials.Password))
+	case credentials.Token != "":
+		opts = append(opts, nats.Token(credentials.Token))
+	case credentials.NKeySeed != "":
+		keyPair, err := nkeys.FromSeed([]byte(credentials.NKeySeed))
+		if err != nil {
+			return nil, fmt.Errorf("invalid NKey seed: %w", err)
+		}
+		publicKey, err := keyPair.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid NKey seed: %w", err)
+		}
+		opts = append(opts, nats.Nkey(publicKey, keyPair.Sign))
+	case credentials.Creds != "":
+		jwt, seed, err := parseCreds(credentials.Creds)
+		if err != nil {
+			return nil, fmt.Errorf("invalid creds: %w", err)
+		}
+		opts = append(opts, nats.UserJWTAndSeed(jwt, seed))
+	}
+	return opts, nil
+}
+
+// parseCreds returns the user JWT and NKey seed of the contents of a .creds file
+func parseCreds(creds string) (string, string, error) {
+	jwt, err := nkeys.ParseDecoratedJWT([]byte(creds))
+	if err != nil {
+		return "", "", err
+	}
+	// ParseDecoratedJWT returns the contents as is if there is no JWT block
+	if jwt == "" || jwt == creds {
+		return "", "", errors.New("missing user JWT")
+	}
+	keyPair, err := nkeys.ParseDecoratedNKey([]byte(creds))
+	if err != nil {
+		return "", "", err
+	}
+	seed, err := keyPair.Seed()
+	if err != nil {
+		return "", "", err
+	}
+	return jwt, string(seed), nil
+}
+
+func natsURL(config *NATSDestinationConfig) string {
+	scheme := "nats"
+	if config.UseTLS {
+		scheme = "tls"
+	}
+	return fmt.Sprintf("%s://%s", scheme, config.ServerURL)
+}
+
+func isEnabled(value string) bool {
+	return value == "true" || value == "on"
+}
+
+// ===== TEST HELPERS =====
+
+// NewNATSPublisher creates a new publisher for testing purposes
+func NewNATSPublisher(subject string) *NATSPublisher {
+	return &NATSPublisher{
+		BasePublisher: &destregistry.BasePublisher{},
+		subject:       subject,
+	}
+}
@@ -0,0 +1,34 @@
+package destnats_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destnats"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNATSPublisher_Format(t *testing.T) {
+	t.Parallel()
+
+	publisher := destnats.NewNATSPublisher("events")
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithID("evt_123"),
+		testutil.EventFactory.WithTopic("user.created"),
+		testutil.EventFactory.WithData(map[string]interface{}{"user_id": "usr_123"}),
+		testutil.EventFactory.WithMetadata(map[string]string{"Source": "signup"}),
+	)
+
+	msg, err := publisher.Format(context.Background(), &event)
+	require.NoError(t, err)
+	assert.Equal(t, "events", msg.Subject)
+	assert.JSONEq(t, `{"user_id":"usr_123"}`, string(msg.Data))
+	assert.Equal(t, "evt_123", msg.Header.Get("event-id"))
+	assert.Equal(t, "user.created", msg.Header.Get("topic"))
+	assert.NotEmpty(t, msg.Header.Get("timestamp"))
+	// NATS headers are case-sensitive and keep the metadata keys as is
+	assert.Equal(t, "signup", msg.Header.Get("Source"))
+	assert.Empty(t, msg.Header.Get("Nats-Msg-Id"), "the message ID is only set for JetStream publishes")
+}
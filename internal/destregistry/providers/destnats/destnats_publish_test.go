@@ -0,0 +1,315 @@
+package destnats_test
+
+import (
+	"context"
+	"maps"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destnats"
+	testsuite "github.com/hookdeck/outpost/internal/destregistry/testing"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testinfra"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// NATSConsumer implements testsuite.MessageConsumer
+type NATSConsumer struct {
+	conn     *nats.Conn
+	sub      *nats.Subscription
+	consume  jetstream.ConsumeContext
+	messages chan testsuite.Message
+}
+
+// NewNATSConsumer subscribes to the subject with core NATS
+func NewNATSConsumer(serverURL, subject string) (*NATSConsumer, error) {
+	conn, err := nats.Connect("nats://" + serverURL)
+	if err != nil {
+		return nil, err
+	}
+	consumer := &NATSConsumer{
+		conn:     conn,
+		messages: make(chan testsuite.Message, 100),
+	}
+	consumer.sub, err = conn.Subscribe(subject, func(msg *nats.Msg) {
+		consumer.messages <- toTestMessage(msg.Data, msg.Header, msg)
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// Make sure the subscription is registered before anything is published
+	if err := conn.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return consumer, nil
+}
+
+// NewJetStreamConsumer creates a stream capturing the subject and consumes it
+// with an ordered consumer
+func NewJetStreamConsumer(serverURL, stream, subject string) (*NATSConsumer, error) {
+	conn, err := nats.Connect("nats://" + serverURL)
+	if err != nil {
+		return nil, err
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s, err := js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     stream,
+		Subjects: []string{subject},
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c, err := s.OrderedConsumer(ctx, jetstream.OrderedConsumerConfig{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	consumer := &NATSConsumer{
+		conn:     conn,
+		messages: make(chan testsuite.Message, 100),
+	}
+	consumer.consume, err = c.Consume(func(msg jetstream.Msg) {
+		consumer.messages <- toTestMessage(msg.Data(), msg.Headers(), msg)
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return consumer, nil
+}
+
+func toTestMessage(data []byte, header nats.Header, raw interface{}) testsuite.Message {
+	metadata := make(map[string]string)
+	for k := range header {
+		metadata[k] = header.Get(k)
+	}
+	return testsuite.Message{
+		Data:     data,
+		Metadata: metadata,
+		Raw:      raw,
+	}
+}
+
+func (c *NATSConsumer) Consume() <-chan testsuite.Message {
+	return c.messages
+}
+
+func (c *NATSConsumer) Close() error {
+	if c.sub != nil {
+		c.sub.Unsubscribe()
+	}
+	if c.consume != nil {
+		c.consume.Stop()
+	}
+	c.conn.Close()
+	return nil
+}
+
+// NATSAsserter implements provider-specific message assertions
+type NATSAsserter struct {
+	subject   string
+	jetStream bool
+}
+
+func (a *NATSAsserter) AssertMessage(t testsuite.TestingT, msg testsuite.Message, event models.Event) {
+	if a.jetStream {
+		record, ok := msg.Raw.(jetstream.Msg)
+		assert.True(t, ok, "raw message should be jetstream.Msg")
+		assert.Equal(t, a.subject, record.Subject())
+		assert.Equal(t, event.ID, record.Headers().Get(jetstream.MsgIDHeader), "message ID should be the event ID")
+	} else {
+		record, ok := msg.Raw.(*nats.Msg)
+		assert.True(t, ok, "raw message should be *nats.Msg")
+		assert.Equal(t, a.subject, record.Subject)
+	}
+
+	// Verify system metadata
+	metadata := msg.Metadata
+	assert.NotEmpty(t, metadata["timestamp"], "timestamp should be present")
+	assert.Equal(t, event.ID, metadata["event-id"], "event-id should match")
+	assert.Equal(t, event.Topic, metadata["topic"], "topic should match")
+
+	// Verify custom metadata
+	for k, v := range event.Metadata {
+		assert.Equal(t, v, metadata[k], "metadata key %s should match expected value", k)
+	}
+}
+
+type NATSPublishSuite struct {
+	testsuite.PublisherSuite
+	consumer *NATSConsumer
+}
+
+func (s *NATSPublishSuite) SetupSuite() {
+	t := s.T()
+	t.Cleanup(testinfra.Start(t))
+	serverURL := testinfra.EnsureNATS()
+	subject := "test." + uuid.New().String()
+
+	host, port, err := net.SplitHostPort(serverURL)
+	require.NoError(t, err)
+	provider, err := destnats.New(testutil.Registry.MetadataLoader(),
+		destnats.WithDialContext(testsuite.RedirectDialer((&net.Dialer{}).DialContext, host)))
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("nats"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": net.JoinHostPort("nats.example.com", port),
+			"subject":    subject,
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+
+	consumer, err := NewNATSConsumer(serverURL, subject)
+	require.NoError(t, err)
+	s.consumer = consumer
+
+	s.InitSuite(testsuite.Config{
+		Provider: provider,
+		Dest:     &dest,
+		Consumer: consumer,
+		Asserter: &NATSAsserter{subject: subject},
+	})
+}
+
+func (s *NATSPublishSuite) TearDownSuite() {
+	if s.consumer != nil {
+		s.consumer.Close()
+	}
+}
+
+func TestNATSPublishIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	suite.Run(t, new(NATSPublishSuite))
+}
+
+type NATSJetStreamPublishSuite struct {
+	testsuite.PublisherSuite
+	provider *destnats.NATSDestination
+	dest     models.Destination
+	consumer *NATSConsumer
+}
+
+func (s *NATSJetStreamPublishSuite) SetupSuite() {
+	t := s.T()
+	t.Cleanup(testinfra.Start(t))
+	serverURL := testinfra.EnsureNATS()
+	id := uuid.New().String()
+	stream := "test-" + id
+	subject := "test." + id
+
+	host, port, err := net.SplitHostPort(serverURL)
+	require.NoError(t, err)
+	provider, err := destnats.New(testutil.Registry.MetadataLoader(),
+		destnats.WithDialContext(testsuite.RedirectDialer((&net.Dialer{}).DialContext, host)))
+	require.NoError(t, err)
+	s.provider = provider
+
+	s.dest = testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("nats"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": net.JoinHostPort("nats.example.com", port),
+			"subject":    subject,
+			"jetstream":  "true",
+			"stream":     stream,
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+
+	consumer, err := NewJetStreamConsumer(serverURL, stream, subject)
+	require.NoError(t, err)
+	s.consumer = consumer
+
+	s.InitSuite(testsuite.Config{
+		Provider: provider,
+		Dest:     &s.dest,
+		Consumer: consumer,
+		Asserter: &NATSAsserter{subject: subject, jetStream: true},
+	})
+}
+
+func (s *NATSJetStreamPublishSuite) TearDownSuite() {
+	if s.consumer != nil {
+		s.consumer.Close()
+	}
+}
+
+func (s *NATSJetStreamPublishSuite) TestDeduplicatesRetries() {
+	publisher, err := s.provider.CreatePublisher(context.Background(), &s.dest)
+	s.Require().NoError(err)
+	defer publisher.Close()
+
+	event := testutil.EventFactory.Any()
+	first, err := publisher.Publish(context.Background(), &event)
+	s.Require().NoError(err)
+	s.Equal("success", first.Status)
+	s.Equal(false, first.Response["duplicate"])
+
+	retry, err := publisher.Publish(context.Background(), &event)
+	s.Require().NoError(err)
+	s.Equal("success", retry.Status)
+	s.Equal(true, retry.Response["duplicate"])
+	s.Equal(first.Response["sequence"], retry.Response["sequence"])
+
+	// Only the first publish is stored in the stream
+	select {
+	case msg := <-s.consumer.Consume():
+		s.Equal(event.ID, msg.Metadata["event-id"])
+	case <-time.After(5 * time.Second):
+		s.Fail("timeout waiting for message")
+	}
+	select {
+	case msg := <-s.consumer.Consume():
+		s.Failf("unexpected duplicate message", "event-id %s", msg.Metadata["event-id"])
+	case <-time.After(time.Second):
+	}
+}
+
+func (s *NATSJetStreamPublishSuite) TestMismatchedStream() {
+	dest := s.dest
+	dest.Config = maps.Clone(s.dest.Config)
+	dest.Config["stream"] = "other-stream"
+
+	publisher, err := s.provider.CreatePublisher(context.Background(), &dest)
+	s.Require().NoError(err)
+	defer publisher.Close()
+
+	event := testutil.EventFactory.Any()
+	delivery, err := publisher.Publish(context.Background(), &event)
+	var publishErr *destregistry.ErrDestinationPublishAttempt
+	s.Require().ErrorAs(err, &publishErr)
+	s.Equal("nats", publishErr.Provider)
+	s.Equal("jetstream_error", publishErr.Data["error"])
+	s.Require().NotNil(delivery)
+	s.Equal("failed", delivery.Status)
+	s.Equal("ERR", delivery.Code)
+}
+
+func TestNATSJetStreamPublishIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	suite.Run(t, new(NATSJetStreamPublishSuite))
+}
@@ -0,0 +1,13 @@
+package destnats
+
+import (
+	"context"
+	"net"
+)
+
+// WithDialContext lets tests connect to a local NATS server.
+func WithDialContext(dialContext func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(d *NATSDestination) {
+		d.dialContext = dialContext
+	}
+}
@@ -0,0 +1,324 @@
+package destredisstream
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisStreamDestination struct {
+	*destregistry.BaseProvider
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+type RedisStreamDestinationConfig struct {
+	ServerURL string
+	Database  int
+	Stream    string
+	MaxLen    int64 // optional, approximate stream length to trim to
+	UseTLS    bool
+}
+
+type RedisStreamDestinationCredentials struct {
+	Username string // optional, defaults to the "default" ACL user
+	Password string
+}
+
+var _ destregistry.Provider = (*RedisStreamDestination)(nil)
+
+type Option func(*RedisStreamDestination)
+
+// WithEgressPolicy restricts the addresses publishers can connect to
+func WithEgressPolicy(policy *destregistry.EgressPolicy) Option {
+	return func(d *RedisStreamDestination) {
+		if policy != nil {
+			d.dialContext = policy.Dialer().DialContext
+		}
+	}
+}
+
+func New(loader metadata.MetadataLoader, opts ...Option) (*RedisStreamDestination, error) {
+	base, err := destregistry.NewBaseProvider(loader, "redis_stream")
+	if err != nil {
+		return nil, err
+	}
+	d := &RedisStreamDestination{BaseProvider: base}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+func (d *RedisStreamDestination) Validate(ctx context.Context, destination *models.Destination) error {
+	_, _, err := d.resolveMetadata(ctx, destination)
+	return err
+}
+
+func (d *RedisStreamDestination) CreatePublisher(ctx context.Context, destination *models.Destination) (destregistry.Publisher, error) {
+	config, credentials, err := d.resolveMetadata(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &redis.Options{
+		Addr:     config.ServerURL,
+		Username: credentials.Username,
+		Password: credentials.Password,
+		DB:       config.Database,
+	}
+	if config.UseTLS {
+		host, _, err := net.SplitHostPort(config.ServerURL)
+		if err != nil {
+			host = config.ServerURL
+		}
+		opts.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: host,
+		}
+	}
+	if d.dialContext != nil {
+		opts.Dialer = newDialer(d.dialContext, opts.TLSConfig)
+	}
+
+	return &RedisStreamPublisher{
+		BasePublisher: &destregistry.BasePublisher{},
+		client:        redis.NewClient(opts),
+		stream:        config.Stream,
+		maxLen:        config.MaxLen,
+	}, nil
+}
+
+func (d *RedisStreamDestination) resolveMetadata(ctx context.Context, destination *models.Destination) (*RedisStreamDestinationConfig, *RedisStreamDestinationCredentials, error) {
+	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
+		return nil, nil, err
+	}
+
+	if tlsStr, ok := destination.Config["tls"]; ok && tlsStr != "on" && tlsStr != "true" && tlsStr != "false" {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "config.tls",
+				Type:  "invalid",
+			},
+		})
+	}
+
+	database := 0
+	if value := destination.Config["database"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+				{
+					Field: "config.database",
+					Type:  "invalid",
+				},
+			})
+		}
+		database = parsed
+	}
+	var maxLen int64
+	if value := destination.Config["max_len"]; value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+				{
+					Field: "config.max_len",
+					Type:  "invalid",
+				},
+			})
+		}
+		maxLen = parsed
+	}
+
+	if destination.Credentials["password"] == "" && destination.Credentials["username"] != "" {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "credentials.password",
+				Type:  "required",
+			},
+		})
+	}
+
+	return &RedisStreamDestinationConfig{
+		ServerURL: destination.Config["server_url"],
+		Database:  database,
+		Stream:    destination.Config["stream"],
+		MaxLen:    maxLen,
+		UseTLS:    destination.Config["tls"] == "true" || destination.Config["tls"] == "on",
+	}, &RedisStreamDestinationCredentials{
+		Username: destination.Credentials["username"],
+		Password: destination.Credentials["password"],
+	}, nil
+}
+
+// Preprocess sets the default TLS value to "false" if not provided
+func (d *RedisStreamDestination) Preprocess(newDestination *models.Destination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if newDestination.Config == nil {
+		return nil
+	}
+	if newDestination.Config["tls"] == "on" {
+		newDestination.Config["tls"] = "true"
+	} else if newDestination.Config["tls"] == "" {
+		newDestination.Config["tls"] = "false" // default to false if omitted
+	}
+	if _, _, err := d.resolveMetadata(context.Background(), newDestination); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *RedisStreamDestination) ComputeTarget(destination *models.Destination) destregistry.DestinationTarget {
+	return destregistry.DestinationTarget{
+		Target:    destination.Config["stream"] + " on " + destination.Config["server_url"],
+		TargetURL: "",
+	}
+}
+
+type RedisStreamPublisher struct {
+	*destregistry.BasePublisher
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+func (p *RedisStreamPublisher) Close() error {
+	p.BasePublisher.StartClose()
+	return p.client.Close()
+}
+
+// Format prepares the XADD arguments of the event. Entries have a "data"
+// field with the JSON event data, a "metadata" field with the JSON event
+// metadata and a "topic" field with the event topic.
+func (p *RedisStreamPublisher) Format(ctx context.Context, event *models.Event) (*redis.XAddArgs, error) {
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, err
+	}
+	metadataBytes, err := json.Marshal(p.BasePublisher.MakeMetadata(event, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	args := &redis.XAddArgs{
+		Stream: p.stream,
+		ID:     "*",
+		// A slice keeps the fields in a stable order
+		Values: []interface{}{
+			"data", string(dataBytes),
+			"metadata", string(metadataBytes),
+			"topic", event.Topic,
+		},
+	}
+	if p.maxLen > 0 {
+		// Approximate trimming (MAXLEN ~) lets Redis trim whole nodes, which is much cheaper
+		args.MaxLen = p.maxLen
+		args.Approx = true
+	}
+	return args, nil
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event *models.Event) (*destregistry.Delivery, error) {
+	if err := p.BasePublisher.StartPublish(); err != nil {
+		return nil, err
+	}
+	defer p.BasePublisher.FinishPublish()
+
+	args, err := p.Format(ctx, event)
+	if err != nil {
+		return nil, destregistry.NewErrDestinationPublishAttempt(err, "redis_stream", map[string]interface{}{
+			"error":   "format_failed",
+			"message": err.Error(),
+		})
+	}
+
+	id, err := p.client.XAdd(ctx, args).Result()
+	if err != nil {
+		return &destregistry.Delivery{
+			Status: "failed",
+			Code:   "ERR",
+			Response: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, destregistry.NewErrDestinationPublishAttempt(err, "redis_stream", map[string]interface{}{
+			"error":   formatRedisError(err),
+			"stream":  p.stream,
+			"message": err.Error(),
+		})
+	}
+
+	return &destregistry.Delivery{
+		Status: "success",
+		Code:   "OK",
+		Response: map[string]interface{}{
+			"stream": p.stream,
+			"id":     id,
+		},
+	}, nil
+}
+
+// newDialer replaces the default dialer of go-redis, which is also
+// responsible for the TLS handshake.
+func newDialer(dialContext func(ctx context.Context, network, addr string) (net.Conn, error), tlsConfig *tls.Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialContext(ctx, network, addr)
+		if err != nil || tlsConfig == nil {
+			return conn, err
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+}
+
+// Helper function to format Redis errors
+func formatRedisError(err error) string {
+	if errors.Is(err, destregistry.ErrEgressBlocked) {
+		return destregistry.ErrorCodeEgressBlocked
+	}
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		msg := redisErr.Error()
+		switch {
+		case strings.HasPrefix(msg, "WRONGPASS"), strings.HasPrefix(msg, "NOAUTH"), strings.HasPrefix(msg, "NOPERM"):
+			return "access_denied"
+		case strings.HasPrefix(msg, "WRONGTYPE"):
+			return "wrong_type"
+		case strings.HasPrefix(msg, "OOM"):
+			return "out_of_memory"
+		}
+		return "publish_failed"
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &netErr):
+		return "connection_failed"
+	}
+	return "publish_failed"
+}
+
+// ===== TEST HELPERS =====
+
+// NewRedisStreamPublisher creates a new publisher for testing purposes
+func NewRedisStreamPublisher(client *redis.Client, stream string, maxLen int64) *RedisStreamPublisher {
+	return &RedisStreamPublisher{
+		BasePublisher: &destregistry.BasePublisher{},
+		client:        client,
+		stream:        stream,
+		maxLen:        maxLen,
+	}
+}
@@ -0,0 +1,66 @@
+package destredisstream_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destredisstream"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStreamPublisher_Format(t *testing.T) {
+	t.Parallel()
+
+	event := models.Event{
+		ID:    "event-123",
+		Topic: "user.created",
+		Data: map[string]interface{}{
+			"customer_id": "cus_456",
+		},
+		Metadata: map[string]string{
+			"source": "signup",
+		},
+	}
+
+	t.Run("should add data, metadata and topic fields", func(t *testing.T) {
+		t.Parallel()
+		publisher := destredisstream.NewRedisStreamPublisher(nil, "events", 0)
+
+		args, err := publisher.Format(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, "events", args.Stream)
+		assert.Equal(t, "*", args.ID)
+		assert.Zero(t, args.MaxLen, "should not trim by default")
+		assert.False(t, args.Approx)
+
+		values, ok := args.Values.([]interface{})
+		require.True(t, ok)
+		require.Len(t, values, 6)
+		assert.Equal(t, []interface{}{"data", "metadata", "topic"}, []interface{}{values[0], values[2], values[4]})
+		assert.Equal(t, "user.created", values[5])
+
+		var data map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(values[1].(string)), &data))
+		assert.Equal(t, map[string]interface{}(event.Data), data)
+
+		var metadata map[string]string
+		require.NoError(t, json.Unmarshal([]byte(values[3].(string)), &metadata))
+		assert.Equal(t, "event-123", metadata["event-id"])
+		assert.Equal(t, "user.created", metadata["topic"])
+		assert.Equal(t, "signup", metadata["source"])
+		assert.NotEmpty(t, metadata["timestamp"])
+	})
+
+	t.Run("should trim approximately to max length", func(t *testing.T) {
+		t.Parallel()
+		publisher := destredisstream.NewRedisStreamPublisher(nil, "events", 1000)
+
+		args, err := publisher.Format(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), args.MaxLen)
+		assert.True(t, args.Approx)
+	})
+}
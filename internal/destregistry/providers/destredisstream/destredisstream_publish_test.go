@@ -0,0 +1,311 @@
+package destredisstream_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destredisstream"
+	testsuite "github.com/hookdeck/outpost/internal/destregistry/testing"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// testServerURL points at the miniredis server under a hostname accepted by
+// the server URL pattern. Publishers redirect it to 127.0.0.1.
+func testServerURL(mr *miniredis.Miniredis) string {
+	return net.JoinHostPort("redis.example.com", mr.Port())
+}
+
+// RedisStreamConsumer implements testsuite.MessageConsumer
+type RedisStreamConsumer struct {
+	client   *redis.Client
+	cancel   context.CancelFunc
+	done     chan struct{}
+	messages chan testsuite.Message
+}
+
+func NewRedisStreamConsumer(addr, stream string) *RedisStreamConsumer {
+	ctx, cancel := context.WithCancel(context.Background())
+	consumer := &RedisStreamConsumer{
+		client:   redis.NewClient(&redis.Options{Addr: addr}),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		messages: make(chan testsuite.Message, 100),
+	}
+
+	go func() {
+		defer close(consumer.done)
+		lastID := "0"
+		for {
+			streams, err := consumer.client.XRead(ctx, &redis.XReadArgs{
+				Streams: []string{stream, lastID},
+				Block:   100 * time.Millisecond,
+			}).Result()
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				// redis.Nil when no entries were added while blocking
+				continue
+			}
+			for _, entry := range streams[0].Messages {
+				lastID = entry.ID
+				consumer.messages <- toTestMessage(entry)
+			}
+		}
+	}()
+
+	return consumer
+}
+
+func toTestMessage(entry redis.XMessage) testsuite.Message {
+	data, _ := entry.Values["data"].(string)
+	rawMetadata, _ := entry.Values["metadata"].(string)
+	metadata := make(map[string]string)
+	_ = json.Unmarshal([]byte(rawMetadata), &metadata)
+	return testsuite.Message{
+		Data:     []byte(data),
+		Metadata: metadata,
+		Raw:      entry,
+	}
+}
+
+func (c *RedisStreamConsumer) Consume() <-chan testsuite.Message {
+	return c.messages
+}
+
+func (c *RedisStreamConsumer) Close() error {
+	c.cancel()
+	<-c.done
+	close(c.messages)
+	return c.client.Close()
+}
+
+// RedisStreamAsserter implements provider-specific message assertions
+type RedisStreamAsserter struct{}
+
+func (a *RedisStreamAsserter) AssertMessage(t testsuite.TestingT, msg testsuite.Message, event models.Event) {
+	entry, ok := msg.Raw.(redis.XMessage)
+	assert.True(t, ok, "raw message should be redis.XMessage")
+	assert.NotEmpty(t, entry.ID)
+	assert.Equal(t, event.Topic, entry.Values["topic"], "topic field should match")
+
+	// Verify system metadata
+	metadata := msg.Metadata
+	assert.NotEmpty(t, metadata["timestamp"], "timestamp should be present")
+	assert.Equal(t, event.ID, metadata["event-id"], "event-id should match")
+	assert.Equal(t, event.Topic, metadata["topic"], "topic should match")
+
+	// Verify custom metadata
+	for k, v := range event.Metadata {
+		assert.Equal(t, v, metadata[k], "metadata key %s should match expected value", k)
+	}
+}
+
+type RedisStreamPublishSuite struct {
+	testsuite.PublisherSuite
+	consumer *RedisStreamConsumer
+}
+
+func (s *RedisStreamPublishSuite) SetupSuite() {
+	t := s.T()
+	mr := miniredis.RunT(t)
+
+	provider, err := destredisstream.New(testutil.Registry.MetadataLoader(), destredisstream.WithDialContext(testsuite.RedirectDialer((&net.Dialer{}).DialContext, "127.0.0.1")))
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("redis_stream"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": testServerURL(mr),
+			"stream":     "events",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+
+	s.consumer = NewRedisStreamConsumer(mr.Addr(), "events")
+
+	s.InitSuite(testsuite.Config{
+		Provider: provider,
+		Dest:     &dest,
+		Consumer: s.consumer,
+		Asserter: &RedisStreamAsserter{},
+	})
+}
+
+func (s *RedisStreamPublishSuite) TearDownSuite() {
+	if s.consumer != nil {
+		s.consumer.Close()
+	}
+}
+
+func TestRedisStreamPublish(t *testing.T) {
+	suite.Run(t, new(RedisStreamPublishSuite))
+}
+
+func TestRedisStreamPublisher_Publish(t *testing.T) {
+	t.Parallel()
+
+	provider, err := destredisstream.New(testutil.Registry.MetadataLoader(), destredisstream.WithDialContext(testsuite.RedirectDialer((&net.Dialer{}).DialContext, "127.0.0.1")))
+	require.NoError(t, err)
+
+	newPublisher := func(t *testing.T, config map[string]string, credentials map[string]string) destregistry.Publisher {
+		t.Helper()
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("redis_stream"),
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(credentials),
+		)
+		publisher, err := provider.CreatePublisher(context.Background(), &dest)
+		require.NoError(t, err)
+		t.Cleanup(func() { publisher.Close() })
+		return publisher
+	}
+
+	t.Run("should return the entry ID", func(t *testing.T) {
+		t.Parallel()
+		mr := miniredis.RunT(t)
+		publisher := newPublisher(t, map[string]string{"server_url": testServerURL(mr), "stream": "events"}, map[string]string{})
+
+		event := testutil.EventFactory.Any()
+		delivery, err := publisher.Publish(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+		assert.Equal(t, "OK", delivery.Code)
+		assert.Equal(t, "events", delivery.Response["stream"])
+
+		entries, err := mr.Stream("events")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, entries[0].ID, delivery.Response["id"])
+	})
+
+	t.Run("should authenticate as ACL user", func(t *testing.T) {
+		t.Parallel()
+		mr := miniredis.RunT(t)
+		mr.RequireUserAuth("outpost", "secret")
+
+		publisher := newPublisher(t, map[string]string{"server_url": testServerURL(mr), "stream": "events"}, map[string]string{
+			"username": "outpost",
+			"password": "secret",
+		})
+		event := testutil.EventFactory.Any()
+		delivery, err := publisher.Publish(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+	})
+
+	t.Run("should fail with wrong password", func(t *testing.T) {
+		t.Parallel()
+		mr := miniredis.RunT(t)
+		mr.RequireUserAuth("outpost", "secret")
+
+		publisher := newPublisher(t, map[string]string{"server_url": testServerURL(mr), "stream": "events"}, map[string]string{
+			"username": "outpost",
+			"password": "wrong",
+		})
+		event := testutil.EventFactory.Any()
+		delivery, err := publisher.Publish(context.Background(), &event)
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Equal(t, "redis_stream", publishErr.Provider)
+		assert.Equal(t, "access_denied", publishErr.Data["error"])
+		require.NotNil(t, delivery)
+		assert.Equal(t, "failed", delivery.Status)
+		assert.Equal(t, "ERR", delivery.Code)
+	})
+
+	t.Run("should fail when the key isn't a stream", func(t *testing.T) {
+		t.Parallel()
+		mr := miniredis.RunT(t)
+		require.NoError(t, mr.Set("events", "not-a-stream"))
+
+		publisher := newPublisher(t, map[string]string{"server_url": testServerURL(mr), "stream": "events"}, map[string]string{})
+		event := testutil.EventFactory.Any()
+		_, err := publisher.Publish(context.Background(), &event)
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Equal(t, "wrong_type", publishErr.Data["error"])
+	})
+
+	t.Run("should trim the stream to max length", func(t *testing.T) {
+		t.Parallel()
+		mr := miniredis.RunT(t)
+		publisher := newPublisher(t, map[string]string{"server_url": testServerURL(mr), "stream": "events", "max_len": "5"}, map[string]string{})
+
+		for i := 0; i < 10; i++ {
+			event := testutil.EventFactory.Any()
+			_, err := publisher.Publish(context.Background(), &event)
+			require.NoError(t, err)
+		}
+
+		entries, err := mr.Stream("events")
+		require.NoError(t, err)
+		assert.Less(t, len(entries), 10)
+	})
+
+	t.Run("should fail when the server is unreachable", func(t *testing.T) {
+		t.Parallel()
+		mr := miniredis.RunT(t)
+		serverURL := testServerURL(mr)
+		mr.Close()
+
+		publisher := newPublisher(t, map[string]string{"server_url": serverURL, "stream": "events"}, map[string]string{})
+		event := testutil.EventFactory.Any()
+		_, err := publisher.Publish(context.Background(), &event)
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Equal(t, "connection_failed", publishErr.Data["error"])
+	})
+}
+
+func TestRedisStreamPublisher_EgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	mr := miniredis.RunT(t)
+	publish := func(t *testing.T, policy *destregistry.EgressPolicy) error {
+		t.Helper()
+		provider, err := destredisstream.New(testutil.Registry.MetadataLoader(),
+			destredisstream.WithDialContext(testsuite.RedirectDialer(policy.Dialer().DialContext, "127.0.0.1")))
+		require.NoError(t, err)
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("redis_stream"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"server_url": testServerURL(mr), "stream": "events"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{}),
+		)
+		publisher, err := provider.CreatePublisher(context.Background(), &dest)
+		require.NoError(t, err)
+		t.Cleanup(func() { publisher.Close() })
+		event := testutil.EventFactory.Any()
+		_, err = publisher.Publish(context.Background(), &event)
+		return err
+	}
+
+	t.Run("should block loopback addresses by default", func(t *testing.T) {
+		t.Parallel()
+		policy, err := destregistry.NewEgressPolicy(false, nil, nil)
+		require.NoError(t, err)
+
+		err = publish(t, policy)
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Equal(t, destregistry.ErrorCodeEgressBlocked, publishErr.Data["error"])
+	})
+
+	t.Run("should connect to allowed CIDRs", func(t *testing.T) {
+		t.Parallel()
+		policy, err := destregistry.NewEgressPolicy(false, []string{"127.0.0.1/32"}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, publish(t, policy))
+	})
+}
@@ -0,0 +1,178 @@
+package destredisstream_test
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destredisstream"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStreamDestination_Validate(t *testing.T) {
+	t.Parallel()
+
+	validDestination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("redis_stream"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": "redis.example.com:6379",
+			"stream":     "events",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{
+			"username": "outpost",
+			"password": "secret",
+		}),
+	)
+
+	redisStreamDestination, err := destredisstream.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	t.Run("should validate valid destination", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, redisStreamDestination.Validate(context.Background(), &validDestination))
+	})
+
+	t.Run("should validate invalid type", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Type = "invalid"
+		err := redisStreamDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "type", validationErr.Errors[0].Field)
+		assert.Equal(t, "invalid_type", validationErr.Errors[0].Type)
+	})
+
+	t.Run("should validate missing config", func(t *testing.T) {
+		t.Parallel()
+		for _, field := range []string{"server_url", "stream"} {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			delete(dest.Config, field)
+			err := redisStreamDestination.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "config."+field, validationErr.Errors[0].Field)
+			assert.Equal(t, "required", validationErr.Errors[0].Type)
+		}
+	})
+
+	t.Run("should validate malformed config", func(t *testing.T) {
+		t.Parallel()
+		testCases := []struct {
+			field string
+			value string
+		}{
+			{"server_url", "redis://redis.example.com:6379"},
+			{"server_url", "localhost:6379"},
+			{"server_url", "127.0.0.1:6379"},
+			{"server_url", "[::1]:6379"},
+			{"stream", "my events"},
+			{"database", "-1"},
+			{"database", "one"},
+			{"max_len", "0"},
+			{"max_len", "-100"},
+			{"max_len", "1k"},
+		}
+		for _, tc := range testCases {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			dest.Config[tc.field] = tc.value
+			err := redisStreamDestination.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr, "%s %q", tc.field, tc.value)
+			assert.Equal(t, "config."+tc.field, validationErr.Errors[0].Field)
+			assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+		}
+	})
+
+	t.Run("should validate optional config", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Config["database"] = "2"
+		dest.Config["max_len"] = "10000"
+		dest.Config["tls"] = "true"
+		assert.NoError(t, redisStreamDestination.Validate(context.Background(), &dest))
+	})
+
+	t.Run("should validate invalid tls", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Config["tls"] = "yes"
+		err := redisStreamDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.tls", validationErr.Errors[0].Field)
+		assert.Equal(t, "invalid", validationErr.Errors[0].Type)
+	})
+
+	t.Run("should allow password or no credentials", func(t *testing.T) {
+		t.Parallel()
+		for _, credentials := range []map[string]string{
+			{"password": "secret"},
+			{},
+		} {
+			dest := validDestination
+			dest.Credentials = credentials
+			assert.NoError(t, redisStreamDestination.Validate(context.Background(), &dest), "credentials %v", credentials)
+		}
+	})
+
+	t.Run("should validate username without password", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Credentials = map[string]string{"username": "outpost"}
+		err := redisStreamDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "credentials.password", validationErr.Errors[0].Field)
+		assert.Equal(t, "required", validationErr.Errors[0].Type)
+	})
+}
+
+func TestRedisStreamDestination_Preprocess(t *testing.T) {
+	t.Parallel()
+
+	redisStreamDestination, err := destredisstream.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	for input, expected := range map[string]string{"": "false", "on": "true", "true": "true", "false": "false"} {
+		config := map[string]string{
+			"server_url": "redis.example.com:6379",
+			"stream":     "events",
+		}
+		if input != "" {
+			config["tls"] = input
+		}
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("redis_stream"),
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(map[string]string{}),
+		)
+		require.NoError(t, redisStreamDestination.Preprocess(&dest, nil, &destregistry.PreprocessDestinationOpts{}))
+		assert.Equal(t, expected, dest.Config["tls"], "tls %q", input)
+	}
+}
+
+func TestRedisStreamDestination_ComputeTarget(t *testing.T) {
+	t.Parallel()
+
+	redisStreamDestination, err := destredisstream.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("redis_stream"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": "redis.example.com:6379",
+			"stream":     "events",
+		}),
+	)
+	target := redisStreamDestination.ComputeTarget(&dest)
+	assert.Equal(t, "events on redis.example.com:6379", target.Target)
+	assert.Empty(t, target.TargetURL)
+}
@@ -0,0 +1,13 @@
+package destredisstream
+
+import (
+	"context"
+	"net"
+)
+
+// WithDialContext lets tests connect to a miniredis server.
+func WithDialContext(dialContext func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(d *RedisStreamDestination) {
+		d.dialContext = dialContext
+	}
+}
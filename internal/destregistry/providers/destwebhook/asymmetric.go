@@ -0,0 +1,280 @@
+package destwebhook
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+const (
+	AlgorithmEd25519   = "ed25519"
+	AlgorithmECDSAP256 = "ecdsa-p256"
+)
+
+var errInvalidSigningKey = errors.New("invalid signing key")
+
+// isAsymmetricAlgorithm reports whether the algorithm signs with a private key
+// whose public key can be shared with tenants.
+func isAsymmetricAlgorithm(algorithm string) bool {
+	return algorithm == AlgorithmEd25519 || algorithm == AlgorithmECDSAP256
+}
+
+// signerCache parses the signing keys of an algorithm once, as publishers
+// sign every request with the same keys.
+type signerCache struct {
+	algorithm string
+	mu        sync.Mutex
+	signers   map[string]crypto.Signer
+}
+
+func newSignerCache(algorithm string) *signerCache {
+	return &signerCache{
+		algorithm: algorithm,
+		signers:   make(map[string]crypto.Signer),
+	}
+}
+
+// signer returns the parsed private key.
+func (c *signerCache) signer(key string) (crypto.Signer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if signer, ok := c.signers[key]; ok {
+		return signer, nil
+	}
+	signer, err := parseSigningKey(key, c.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	c.signers[key] = signer
+	return signer, nil
+}
+
+// prepareSigningKeys parses the private keys of asymmetric algorithms once,
+// rather than on every request. Shared secrets, e.g. of destinations created
+// before the algorithm was configured, keep being signed with HMAC until
+// they're rotated.
+func prepareSigningKeys(algorithm SigningAlgorithm, secrets []WebhookSecret) error {
+	cache, ok := algorithm.(interface {
+		signer(key string) (crypto.Signer, error)
+	})
+	if !ok {
+		return nil
+	}
+	for i, secret := range secrets {
+		if !isSigningKey(secret.Key) {
+			secrets[i].Algorithm = NewHmacSHA256()
+			continue
+		}
+		if _, err := cache.signer(secret.Key); err != nil {
+			return fmt.Errorf("invalid signing key: %w", err)
+		}
+	}
+	return nil
+}
+
+// Ed25519Algo signs with an Ed25519 private key, stored as a base64-encoded
+// PKCS#8 key.
+type Ed25519Algo struct {
+	*signerCache
+}
+
+func NewEd25519() *Ed25519Algo {
+	return &Ed25519Algo{signerCache: newSignerCache(AlgorithmEd25519)}
+}
+
+func (a *Ed25519Algo) Name() string {
+	return AlgorithmEd25519
+}
+
+func (a *Ed25519Algo) Sign(key string, content string, encoder SignatureEncoder) (string, error) {
+	privateKey, err := a.signer(key)
+	if err != nil {
+		return "", err
+	}
+	return encoder.Encode(ed25519.Sign(privateKey.(ed25519.PrivateKey), []byte(content))), nil
+}
+
+func (a *Ed25519Algo) Verify(key string, content string, signature string, encoder SignatureEncoder) bool {
+	privateKey, err := a.signer(key)
+	if err != nil {
+		return false
+	}
+	sig, err := decodeSignature(signature, encoder)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(privateKey.Public().(ed25519.PublicKey), []byte(content), sig)
+}
+
+// ECDSAP256Algo signs the SHA-256 digest of the content with an ECDSA P-256
+// private key, stored as a base64-encoded PKCS#8 key. Signatures are the
+// 64-byte concatenation of r and s, as in JWS.
+type ECDSAP256Algo struct {
+	*signerCache
+}
+
+func NewECDSAP256() *ECDSAP256Algo {
+	return &ECDSAP256Algo{signerCache: newSignerCache(AlgorithmECDSAP256)}
+}
+
+func (a *ECDSAP256Algo) Name() string {
+	return AlgorithmECDSAP256
+}
+
+func (a *ECDSAP256Algo) Sign(key string, content string, encoder SignatureEncoder) (string, error) {
+	privateKey, err := a.signer(key)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256([]byte(content))
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey.(*ecdsa.PrivateKey), digest[:])
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return encoder.Encode(sig), nil
+}
+
+func (a *ECDSAP256Algo) Verify(key string, content string, signature string, encoder SignatureEncoder) bool {
+	privateKey, err := a.signer(key)
+	if err != nil {
+		return false
+	}
+	sig, err := decodeSignature(signature, encoder)
+	if err != nil || len(sig) != 64 {
+		return false
+	}
+	digest := sha256.Sum256([]byte(content))
+	publicKey := &privateKey.(*ecdsa.PrivateKey).PublicKey
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	return ecdsa.Verify(publicKey, digest[:], r, s)
+}
+
+// generateSigningKey creates a private key for the asymmetric algorithm,
+// encoded as base64 PKCS#8.
+func generateSigningKey(algorithm string) (string, error) {
+	var privateKey crypto.PrivateKey
+	var err error
+	switch algorithm {
+	case AlgorithmEd25519:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	case AlgorithmECDSAP256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return "", fmt.Errorf("unsupported signing algorithm %s", algorithm)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// isSigningKey reports whether the secret is a base64 PKCS#8 private key, as
+// opposed to a shared secret.
+func isSigningKey(key string) bool {
+	_, err := parsePKCS8Key(key)
+	return err == nil
+}
+
+func parsePKCS8Key(key string) (crypto.PrivateKey, error) {
+	der, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errInvalidSigningKey
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errInvalidSigningKey
+	}
+	return privateKey, nil
+}
+
+// parseSigningKey parses a base64 PKCS#8 private key and checks it matches the
+// algorithm.
+func parseSigningKey(key string, algorithm string) (crypto.Signer, error) {
+	privateKey, err := parsePKCS8Key(key)
+	if err != nil {
+		return nil, err
+	}
+	switch privateKey := privateKey.(type) {
+	case ed25519.PrivateKey:
+		if algorithm == AlgorithmEd25519 {
+			return privateKey, nil
+		}
+	case *ecdsa.PrivateKey:
+		if algorithm == AlgorithmECDSAP256 && privateKey.Curve == elliptic.P256() {
+			return privateKey, nil
+		}
+	}
+	return nil, errInvalidSigningKey
+}
+
+func decodeSignature(signature string, encoder SignatureEncoder) ([]byte, error) {
+	switch encoder.(type) {
+	case Base64Encoder:
+		return base64.StdEncoding.DecodeString(signature)
+	default:
+		return hex.DecodeString(signature)
+	}
+}
+
+// PublicKeys returns the public keys of the destination's signing keys: the
+// current key, and the previous key until it's invalidated. Shared secrets,
+// e.g. of destinations created before the algorithm was configured, have no
+// public key and are skipped.
+func (d *WebhookDestination) PublicKeys(destination *models.Destination) ([]destregistry.JWK, error) {
+	keys := []destregistry.JWK{}
+	if !d.signsWithPublicKeys() {
+		return keys, nil
+	}
+
+	signingKeys := []string{destination.Credentials["secret"]}
+	if previous := destination.Credentials["previous_secret"]; previous != "" {
+		invalidAt, err := time.Parse(time.RFC3339, destination.Credentials["previous_secret_invalid_at"])
+		if err == nil && time.Now().Before(invalidAt) {
+			signingKeys = append(signingKeys, previous)
+		}
+	}
+
+	for _, signingKey := range signingKeys {
+		if signingKey == "" || !isSigningKey(signingKey) {
+			continue
+		}
+		privateKey, err := parseSigningKey(signingKey, d.algorithm)
+		if err != nil {
+			return nil, err
+		}
+		jwk, err := destregistry.NewJWK(privateKey.Public())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, jwk)
+	}
+	return keys, nil
+}
+
+// signsWithPublicKeys reports whether deliveries are signed with asymmetric
+// keys. Standard Webhooks mode always signs with shared secrets.
+func (d *WebhookDestination) signsWithPublicKeys() bool {
+	return d.mode != ModeStandard && isAsymmetricAlgorithm(d.algorithm)
+}
@@ -0,0 +1,237 @@
+package destwebhook_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verifyWithJWK verifies the default signature header of the request with a
+// public key, the way a tenant would with the JWKS endpoint.
+func verifyWithJWK(t *testing.T, req *http.Request, jwk destregistry.JWK) bool {
+	t.Helper()
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	header := req.Header.Get("x-outpost-signature")
+	timestamp, signatures, found := strings.Cut(header, ",v0=")
+	require.True(t, found, "signature header should be in the default format")
+	content := strings.TrimPrefix(timestamp, "t=") + "." + string(body)
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	require.NoError(t, err)
+	for _, signature := range strings.Split(signatures, ",") {
+		sig, err := hex.DecodeString(signature)
+		require.NoError(t, err)
+		switch jwk.Kty {
+		case "OKP":
+			if ed25519.Verify(ed25519.PublicKey(x), []byte(content), sig) {
+				return true
+			}
+		case "EC":
+			y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+			require.NoError(t, err)
+			publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+			digest := sha256.Sum256([]byte(content))
+			if ecdsa.Verify(publicKey, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestAsymmetricAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	content := `1234567890.{"hello":"world"}`
+	tests := []struct {
+		name string
+		algo destwebhook.SigningAlgorithm
+	}{
+		{name: "ed25519", algo: destwebhook.NewEd25519()},
+		{name: "ecdsa-p256", algo: destwebhook.NewECDSAP256()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			provider, err := destwebhook.New(testutil.Registry.MetadataLoader(), destwebhook.WithSignatureAlgorithm(tt.name))
+			require.NoError(t, err)
+			destination := newPreprocessedDestination(t, provider)
+			key := destination.Credentials["secret"]
+
+			for _, encoder := range []destwebhook.SignatureEncoder{destwebhook.HexEncoder{}, destwebhook.Base64Encoder{}} {
+				signature, err := tt.algo.Sign(key, content, encoder)
+				require.NoError(t, err)
+				require.NotEmpty(t, signature)
+				assert.True(t, tt.algo.Verify(key, content, signature, encoder))
+				assert.False(t, tt.algo.Verify(key, content+"tampered", signature, encoder))
+			}
+
+			_, err = tt.algo.Sign("test-secret", content, destwebhook.HexEncoder{})
+			assert.Error(t, err, "should not sign with an invalid key")
+
+			manager := destwebhook.NewSignatureManager(
+				[]destwebhook.WebhookSecret{{Key: "test-secret", CreatedAt: time.Now()}},
+				destwebhook.WithAlgorithm(tt.algo),
+			)
+			_, err = manager.GenerateSignatureHeader(destwebhook.SignaturePayload{Timestamp: time.Now(), Body: "{}"})
+			assert.Error(t, err, "should fail rather than leave the request unsigned")
+		})
+	}
+}
+
+func newPreprocessedDestination(t *testing.T, provider *destwebhook.WebhookDestination) models.Destination {
+	t.Helper()
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithConfig(map[string]string{"url": "http://example.com"}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+	require.NoError(t, provider.Preprocess(&destination, nil, &destregistry.PreprocessDestinationOpts{}))
+	return destination
+}
+
+func TestWebhookPublisher_AsymmetricSignatures(t *testing.T) {
+	t.Parallel()
+
+	for _, algorithm := range []string{destwebhook.AlgorithmEd25519, destwebhook.AlgorithmECDSAP256} {
+		t.Run(algorithm, func(t *testing.T) {
+			t.Parallel()
+			provider, err := destwebhook.New(testutil.Registry.MetadataLoader(), destwebhook.WithSignatureAlgorithm(algorithm))
+			require.NoError(t, err)
+
+			t.Run("should verify deliveries with the public key", func(t *testing.T) {
+				t.Parallel()
+				destination := newPreprocessedDestination(t, provider)
+				require.NoError(t, provider.Validate(context.Background(), &destination))
+
+				keys, err := provider.PublicKeys(&destination)
+				require.NoError(t, err)
+				require.Len(t, keys, 1)
+				assert.NotEmpty(t, keys[0].Kid)
+				assert.Equal(t, "sig", keys[0].Use)
+
+				publisher, err := provider.CreatePublisher(context.Background(), &destination)
+				require.NoError(t, err)
+				event := testutil.EventFactory.Any()
+				req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+				require.NoError(t, err)
+				assert.True(t, verifyWithJWK(t, req, keys[0]))
+			})
+
+			t.Run("should serve the previous key during rotation", func(t *testing.T) {
+				t.Parallel()
+				original := newPreprocessedDestination(t, provider)
+				originalKeys, err := provider.PublicKeys(&original)
+				require.NoError(t, err)
+
+				destination := original
+				destination.Credentials = map[string]string{"rotate_secret": "true"}
+				require.NoError(t, provider.Preprocess(&destination, &original, &destregistry.PreprocessDestinationOpts{}))
+
+				keys, err := provider.PublicKeys(&destination)
+				require.NoError(t, err)
+				require.Len(t, keys, 2)
+				assert.NotEqual(t, originalKeys[0].Kid, keys[0].Kid)
+				assert.Equal(t, originalKeys[0], keys[1])
+
+				publisher, err := provider.CreatePublisher(context.Background(), &destination)
+				require.NoError(t, err)
+				event := testutil.EventFactory.Any()
+				for _, key := range keys {
+					req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+					require.NoError(t, err)
+					assert.True(t, verifyWithJWK(t, req, key))
+				}
+
+				destination.Credentials["previous_secret_invalid_at"] = time.Now().Add(-time.Hour).Format(time.RFC3339)
+				keys, err = provider.PublicKeys(&destination)
+				require.NoError(t, err)
+				assert.Len(t, keys, 1)
+			})
+
+			t.Run("should obfuscate the private keys", func(t *testing.T) {
+				t.Parallel()
+				destination := newPreprocessedDestination(t, provider)
+				obfuscated := provider.ObfuscateDestination(&destination)
+				assert.NotEqual(t, destination.Credentials["secret"], obfuscated.Credentials["secret"])
+				assert.Contains(t, obfuscated.Credentials["secret"], "*")
+			})
+
+			t.Run("should keep signing shared secrets with hmac", func(t *testing.T) {
+				t.Parallel()
+				destination := testutil.DestinationFactory.Any(
+					testutil.DestinationFactory.WithType("webhook"),
+					testutil.DestinationFactory.WithConfig(map[string]string{"url": "http://example.com"}),
+					testutil.DestinationFactory.WithCredentials(map[string]string{"secret": "test-secret"}),
+				)
+				require.NoError(t, provider.Validate(context.Background(), &destination))
+
+				keys, err := provider.PublicKeys(&destination)
+				require.NoError(t, err)
+				assert.Empty(t, keys)
+
+				publisher, err := provider.CreatePublisher(context.Background(), &destination)
+				require.NoError(t, err)
+				event := testutil.EventFactory.Any()
+				req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+				require.NoError(t, err)
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+
+				parts := strings.SplitN(req.Header.Get("x-outpost-signature"), ",v0=", 2)
+				require.Len(t, parts, 2)
+				expected, err := destwebhook.NewHmacSHA256().Sign("test-secret", strings.TrimPrefix(parts[0], "t=")+"."+string(body), destwebhook.HexEncoder{})
+				require.NoError(t, err)
+				assert.Equal(t, expected, parts[1])
+			})
+
+			t.Run("should reject private keys of another algorithm", func(t *testing.T) {
+				t.Parallel()
+				other := destwebhook.AlgorithmECDSAP256
+				if algorithm == destwebhook.AlgorithmECDSAP256 {
+					other = destwebhook.AlgorithmEd25519
+				}
+				otherProvider, err := destwebhook.New(testutil.Registry.MetadataLoader(), destwebhook.WithSignatureAlgorithm(other))
+				require.NoError(t, err)
+				destination := newPreprocessedDestination(t, otherProvider)
+
+				err = provider.Validate(context.Background(), &destination)
+				var validationErr *destregistry.ErrDestinationValidation
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, "credentials.secret", validationErr.Errors[0].Field)
+				assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+			})
+		})
+	}
+
+	t.Run("should serve no keys for hmac destinations", func(t *testing.T) {
+		t.Parallel()
+		provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+		require.NoError(t, err)
+		destination := newPreprocessedDestination(t, provider)
+		keys, err := provider.PublicKeys(&destination)
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+		assert.NotNil(t, keys)
+	})
+}
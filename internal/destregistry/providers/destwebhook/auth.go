@@ -0,0 +1,241 @@
+package destwebhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+const (
+	AuthTypeBasic  = "basic"
+	AuthTypeBearer = "bearer"
+	AuthTypeOAuth2 = "oauth2"
+
+	// tokenExpiryDelta refreshes OAuth2 tokens slightly before they expire, so
+	// they don't expire in flight.
+	tokenExpiryDelta = 30 * time.Second
+)
+
+// WebhookAuthConfig is the authentication the receiver expects.
+type WebhookAuthConfig struct {
+	Type         string
+	Username     string
+	Password     string
+	BearerToken  string
+	TokenURL     string
+	Scopes       []string
+	ClientID     string
+	ClientSecret string
+}
+
+// authenticator sets the credentials of requests.
+type authenticator interface {
+	authenticate(ctx context.Context, req *http.Request) error
+	// invalidate discards the credentials of a rejected request and reports
+	// whether new credentials can be obtained for a retry.
+	invalidate(req *http.Request) bool
+}
+
+func newAuthenticator(config *WebhookAuthConfig, httpClient *http.Client) authenticator {
+	switch config.Type {
+	case AuthTypeBasic:
+		return &basicAuth{username: config.Username, password: config.Password}
+	case AuthTypeBearer:
+		return &bearerAuth{token: config.BearerToken}
+	case AuthTypeOAuth2:
+		return &oauth2ClientCredentials{
+			tokenURL:     config.TokenURL,
+			scopes:       config.Scopes,
+			clientID:     config.ClientID,
+			clientSecret: config.ClientSecret,
+			httpClient:   httpClient,
+		}
+	default:
+		return nil
+	}
+}
+
+type basicAuth struct {
+	username string
+	password string
+}
+
+func (a *basicAuth) authenticate(ctx context.Context, req *http.Request) error {
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+func (a *basicAuth) invalidate(req *http.Request) bool {
+	return false
+}
+
+type bearerAuth struct {
+	token string
+}
+
+func (a *bearerAuth) authenticate(ctx context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+func (a *bearerAuth) invalidate(req *http.Request) bool {
+	return false
+}
+
+// oauth2ClientCredentials fetches access tokens with the OAuth2 client
+// credentials grant (RFC 6749 section 4.4) and caches them until they expire.
+type oauth2ClientCredentials struct {
+	tokenURL     string
+	scopes       []string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (a *oauth2ClientCredentials) authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *oauth2ClientCredentials) invalidate(req *http.Request) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// Another request may have refreshed the token already
+	if req.Header.Get("Authorization") == "Bearer "+a.accessToken {
+		a.accessToken = ""
+	}
+	return true
+}
+
+// token returns the cached access token, fetching a new one if it's missing
+// or about to expire. Concurrent requests wait for a single fetch.
+func (a *oauth2ClientCredentials) token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.accessToken != "" && (a.expiresAt.IsZero() || time.Now().Before(a.expiresAt.Add(-tokenExpiryDelta))) {
+		return a.accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(a.scopes) > 0 {
+		form.Set("scope", strings.Join(a.scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(a.clientID), url.QueryEscape(a.clientSecret))
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch oauth2 token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch oauth2 token: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch oauth2 token: token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var tokenResp oauth2TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("failed to fetch oauth2 token: invalid token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("failed to fetch oauth2 token: token response has no access_token")
+	}
+	if tokenResp.TokenType != "" && !strings.EqualFold(tokenResp.TokenType, "bearer") {
+		return "", fmt.Errorf("failed to fetch oauth2 token: unsupported token type %s", tokenResp.TokenType)
+	}
+
+	a.accessToken = tokenResp.AccessToken
+	a.expiresAt = time.Time{}
+	if tokenResp.ExpiresIn > 0 {
+		a.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	}
+	return a.accessToken, nil
+}
+
+// resolveAuthConfig parses and validates the authentication of the destination.
+func resolveAuthConfig(destination *models.Destination) (*WebhookAuthConfig, error) {
+	config := &WebhookAuthConfig{
+		Type:         destination.Config["auth_type"],
+		Username:     destination.Credentials["username"],
+		Password:     destination.Credentials["password"],
+		BearerToken:  destination.Credentials["bearer_token"],
+		TokenURL:     destination.Config["token_url"],
+		Scopes:       strings.Fields(destination.Config["scopes"]),
+		ClientID:     destination.Credentials["client_id"],
+		ClientSecret: destination.Credentials["client_secret"],
+	}
+
+	var required []string
+	switch config.Type {
+	case "":
+		return config, nil
+	case AuthTypeBasic:
+		required = []string{"credentials.username", "credentials.password"}
+	case AuthTypeBearer:
+		required = []string{"credentials.bearer_token"}
+	case AuthTypeOAuth2:
+		required = []string{"config.token_url", "credentials.client_id", "credentials.client_secret"}
+	default:
+		return nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: "config.auth_type",
+			Type:  "pattern",
+		}})
+	}
+
+	for _, field := range required {
+		source, key, _ := strings.Cut(field, ".")
+		value := destination.Credentials[key]
+		if source == "config" {
+			value = destination.Config[key]
+		}
+		if value == "" {
+			return nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+				Field: field,
+				Type:  "required",
+			}})
+		}
+	}
+
+	if config.Type == AuthTypeOAuth2 {
+		if u, err := url.Parse(config.TokenURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+				Field: "config.token_url",
+				Type:  "pattern",
+			}})
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,316 @@
+package destwebhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// oauthTokenStub is a token endpoint issuing numbered tokens with the client
+// credentials grant.
+type oauthTokenStub struct {
+	server    *httptest.Server
+	requests  atomic.Int32
+	expiresIn int
+	fail      bool
+}
+
+func newOAuthTokenStub(t *testing.T) *oauthTokenStub {
+	stub := &oauthTokenStub{expiresIn: 3600}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := stub.requests.Add(1)
+		clientID, clientSecret, ok := r.BasicAuth()
+		if stub.fail || !ok || clientID != "client-id" || clientSecret != "client-secret" ||
+			r.FormValue("grant_type") != "client_credentials" || r.FormValue("scope") != "events:write" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   stub.expiresIn,
+		})
+	}))
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+// newAuthReceiver returns a receiver that accepts requests with one of the
+// valid Authorization headers and records the headers it received.
+func newAuthReceiver(t *testing.T, valid func(authorization string) bool) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.Header.Get("Authorization"))
+		mu.Unlock()
+		if !valid(r.Header.Get("Authorization")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
+
+func newAuthPublisher(t *testing.T, config, credentials map[string]string) destregistry.Publisher {
+	t.Helper()
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+	credentials["secret"] = "test-secret"
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithConfig(config),
+		testutil.DestinationFactory.WithCredentials(credentials),
+	)
+	require.NoError(t, provider.Validate(context.Background(), &dest))
+	publisher, err := provider.CreatePublisher(context.Background(), &dest)
+	require.NoError(t, err)
+	return publisher
+}
+
+func TestWebhookPublisher_Auth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should send basic auth", func(t *testing.T) {
+		t.Parallel()
+		receiver, received := newAuthReceiver(t, func(authorization string) bool {
+			return authorization == "Basic dXNlcjpwYXNz" // user:pass
+		})
+		publisher := newAuthPublisher(t,
+			map[string]string{"url": receiver.URL, "auth_type": "basic"},
+			map[string]string{"username": "user", "password": "pass"},
+		)
+
+		event := testutil.EventFactory.Any()
+		_, err := publisher.Publish(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Len(t, received(), 1)
+	})
+
+	t.Run("should send bearer token", func(t *testing.T) {
+		t.Parallel()
+		receiver, received := newAuthReceiver(t, func(authorization string) bool {
+			return authorization == "Bearer static-token"
+		})
+		publisher := newAuthPublisher(t,
+			map[string]string{"url": receiver.URL, "auth_type": "bearer"},
+			map[string]string{"bearer_token": "static-token"},
+		)
+
+		event := testutil.EventFactory.Any()
+		_, err := publisher.Publish(context.Background(), &event)
+		require.NoError(t, err)
+
+		// Static credentials can't be refreshed, so a 401 isn't retried
+		publisher = newAuthPublisher(t,
+			map[string]string{"url": receiver.URL, "auth_type": "bearer"},
+			map[string]string{"bearer_token": "revoked-token"},
+		)
+		_, err = publisher.Publish(context.Background(), &event)
+		require.Error(t, err)
+		assert.Len(t, received(), 2)
+	})
+
+	t.Run("should cache oauth2 tokens", func(t *testing.T) {
+		t.Parallel()
+		tokens := newOAuthTokenStub(t)
+		receiver, received := newAuthReceiver(t, func(authorization string) bool {
+			return authorization == "Bearer token-1"
+		})
+		publisher := newAuthPublisher(t,
+			map[string]string{"url": receiver.URL, "auth_type": "oauth2", "token_url": tokens.server.URL, "scopes": "events:write"},
+			map[string]string{"client_id": "client-id", "client_secret": "client-secret"},
+		)
+
+		for i := 0; i < 3; i++ {
+			event := testutil.EventFactory.Any()
+			_, err := publisher.Publish(context.Background(), &event)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), tokens.requests.Load())
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-1", "Bearer token-1"}, received())
+	})
+
+	t.Run("should refresh expiring oauth2 tokens", func(t *testing.T) {
+		t.Parallel()
+		tokens := newOAuthTokenStub(t)
+		tokens.expiresIn = 10 // within the expiry delta
+		receiver, _ := newAuthReceiver(t, func(authorization string) bool { return true })
+		publisher := newAuthPublisher(t,
+			map[string]string{"url": receiver.URL, "auth_type": "oauth2", "token_url": tokens.server.URL, "scopes": "events:write"},
+			map[string]string{"client_id": "client-id", "client_secret": "client-secret"},
+		)
+
+		for i := 0; i < 2; i++ {
+			event := testutil.EventFactory.Any()
+			_, err := publisher.Publish(context.Background(), &event)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), tokens.requests.Load())
+	})
+
+	t.Run("should refresh the oauth2 token and retry once on 401", func(t *testing.T) {
+		t.Parallel()
+		tokens := newOAuthTokenStub(t)
+		// The first token is revoked before its expiry
+		receiver, received := newAuthReceiver(t, func(authorization string) bool {
+			return authorization == "Bearer token-2"
+		})
+		publisher := newAuthPublisher(t,
+			map[string]string{"url": receiver.URL, "auth_type": "oauth2", "token_url": tokens.server.URL, "scopes": "events:write"},
+			map[string]string{"client_id": "client-id", "client_secret": "client-secret"},
+		)
+
+		event := testutil.EventFactory.Any()
+		delivery, err := publisher.Publish(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, received())
+	})
+
+	t.Run("should not retry more than once on 401", func(t *testing.T) {
+		t.Parallel()
+		tokens := newOAuthTokenStub(t)
+		receiver, received := newAuthReceiver(t, func(authorization string) bool { return false })
+		publisher := newAuthPublisher(t,
+			map[string]string{"url": receiver.URL, "auth_type": "oauth2", "token_url": tokens.server.URL, "scopes": "events:write"},
+			map[string]string{"client_id": "client-id", "client_secret": "client-secret"},
+		)
+
+		event := testutil.EventFactory.Any()
+		delivery, err := publisher.Publish(context.Background(), &event)
+		require.Error(t, err)
+		assert.Equal(t, "401", delivery.Code)
+		assert.Len(t, received(), 2)
+	})
+
+	t.Run("should fail the delivery when the token can't be fetched", func(t *testing.T) {
+		t.Parallel()
+		tokens := newOAuthTokenStub(t)
+		tokens.fail = true
+		receiver, received := newAuthReceiver(t, func(authorization string) bool { return true })
+		publisher := newAuthPublisher(t,
+			map[string]string{"url": receiver.URL, "auth_type": "oauth2", "token_url": tokens.server.URL, "scopes": "events:write"},
+			map[string]string{"client_id": "client-id", "client_secret": "client-secret"},
+		)
+
+		event := testutil.EventFactory.Any()
+		_, err := publisher.Publish(context.Background(), &event)
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Equal(t, "auth_failed", publishErr.Data["error"])
+		assert.Empty(t, received())
+	})
+}
+
+func TestWebhookDestination_ValidateAuth(t *testing.T) {
+	t.Parallel()
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		config      map[string]string
+		credentials map[string]string
+		field       string
+		errType     string
+	}{
+		{
+			name:    "unknown auth type",
+			config:  map[string]string{"auth_type": "digest"},
+			field:   "config.auth_type",
+			errType: "pattern",
+		},
+		{
+			name:        "basic without password",
+			config:      map[string]string{"auth_type": "basic"},
+			credentials: map[string]string{"username": "user"},
+			field:       "credentials.password",
+			errType:     "required",
+		},
+		{
+			name:    "bearer without token",
+			config:  map[string]string{"auth_type": "bearer"},
+			field:   "credentials.bearer_token",
+			errType: "required",
+		},
+		{
+			name:        "oauth2 without token url",
+			config:      map[string]string{"auth_type": "oauth2"},
+			credentials: map[string]string{"client_id": "id", "client_secret": "secret"},
+			field:       "config.token_url",
+			errType:     "required",
+		},
+		{
+			name:        "oauth2 with invalid token url",
+			config:      map[string]string{"auth_type": "oauth2", "token_url": "not-a-url"},
+			credentials: map[string]string{"client_id": "id", "client_secret": "secret"},
+			field:       "config.token_url",
+			errType:     "pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			config := map[string]string{"url": "https://example.com"}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+			credentials := map[string]string{"secret": "test-secret"}
+			for key, value := range tt.credentials {
+				credentials[key] = value
+			}
+			dest := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("webhook"),
+				testutil.DestinationFactory.WithConfig(config),
+				testutil.DestinationFactory.WithCredentials(credentials),
+			)
+
+			err := provider.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Errors[0].Field)
+			assert.Equal(t, tt.errType, validationErr.Errors[0].Type)
+		})
+	}
+
+	t.Run("should obfuscate auth secrets", func(t *testing.T) {
+		t.Parallel()
+		dest := models.Destination{
+			Type:   "webhook",
+			Config: map[string]string{"url": "https://example.com", "auth_type": "oauth2"},
+			Credentials: map[string]string{
+				"client_id":     "client-id",
+				"client_secret": "client-secret-value",
+				"password":      "password-value",
+				"bearer_token":  "bearer-token-value",
+			},
+		}
+		obfuscated := provider.ObfuscateDestination(&dest)
+		assert.Equal(t, "client-id", obfuscated.Credentials["client_id"])
+		assert.Equal(t, "clie***************", obfuscated.Credentials["client_secret"])
+		assert.Equal(t, "pass**********", obfuscated.Credentials["password"])
+		assert.Equal(t, "bear**************", obfuscated.Credentials["bearer_token"])
+	})
+}
@@ -6,9 +6,11 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +22,11 @@ import (
 const (
 	DefaultEncoding  = "hex"
 	DefaultAlgorithm = "hmac-sha256"
+
+	// ModeDefault sends the Outpost headers signed according to the signature options.
+	ModeDefault = "default"
+	// ModeStandard sends the headers and signatures of the Standard Webhooks specification.
+	ModeStandard = "standard"
 )
 
 type WebhookDestination struct {
@@ -34,25 +41,61 @@ type WebhookDestination struct {
 	disableTopicHeader       bool
 	encoding                 string
 	algorithm                string
+	mode                     string
+	egressPolicy             *destregistry.EgressPolicy
+	proxy                    *destregistry.ProxyConfig
+	successStatusCodes       string
+	nonRetryableStatusCodes  string
+	disableStatusCodes       string
+	responseHeaders          []string
+	responseBodyMaxBytes     int
+	verificationMethod       string
 }
 
 type WebhookDestinationConfig struct {
-	URL string
+	URL           string
+	Method        string
+	CustomHeaders map[string]string
+	Auth          *WebhookAuthConfig
+	TLS           *destregistry.TLSClientConfig
+	Proxy         *destregistry.ProxyConfig
+	Response      *WebhookResponseConfig
 }
 
 type WebhookSecret struct {
 	Key       string     `json:"key"`
 	CreatedAt time.Time  `json:"created_at"`
 	InvalidAt *time.Time `json:"invalid_at,omitempty"`
+	// Algorithm overrides the signing algorithm of the signature manager for
+	// this secret, e.g. for shared secrets of asymmetrically signed destinations.
+	Algorithm SigningAlgorithm `json:"-"`
 }
 
 type WebhookDestinationCredentials struct {
-	Secret                  string    `json:"secret"`
-	PreviousSecret          string    `json:"previous_secret,omitempty"`
-	PreviousSecretInvalidAt time.Time `json:"previous_secret_invalid_at,omitempty"`
+	Secret                  string            `json:"secret"`
+	PreviousSecret          string            `json:"previous_secret,omitempty"`
+	PreviousSecretInvalidAt time.Time         `json:"previous_secret_invalid_at,omitempty"`
+	CustomHeaders           map[string]string `json:"custom_headers,omitempty"`
+}
+
+// tenantCredentialKeys are the credentials tenants can set, unlike the
+// signing secrets.
+var tenantCredentialKeys = []string{"custom_headers", "username", "password", "bearer_token", "client_id", "client_secret", "tls_client_cert", "tls_client_key", "proxy_username", "proxy_password"}
+
+// sensitiveCredentialKeys are obfuscated when the destination is displayed.
+var sensitiveCredentialKeys = map[string]bool{
+	"password":       true,
+	"bearer_token":   true,
+	"client_secret":  true,
+	"tls_client_key": true,
+	"proxy_password": true,
 }
 
 var _ destregistry.Provider = (*WebhookDestination)(nil)
+var _ destregistry.BatchProvider = (*WebhookDestination)(nil)
+var _ destregistry.KeySetProvider = (*WebhookDestination)(nil)
+var _ destregistry.CertificateExpiryProvider = (*WebhookDestination)(nil)
+var _ destregistry.BatchPublisher = (*WebhookPublisher)(nil)
 
 // Option is a functional option for configuring WebhookDestination
 type Option func(*WebhookDestination)
@@ -120,6 +163,52 @@ func WithSignatureAlgorithm(algorithm string) Option {
 	}
 }
 
+// WithMode sets the webhook mode, either ModeDefault or ModeStandard.
+// ModeStandard overrides the signature options and the event ID, timestamp
+// and signature headers.
+func WithMode(mode string) Option {
+	return func(w *WebhookDestination) {
+		if mode != "" {
+			w.mode = mode
+		}
+	}
+}
+
+// WithEgressPolicy restricts the addresses webhook requests can connect to
+func WithEgressPolicy(policy *destregistry.EgressPolicy) Option {
+	return func(w *WebhookDestination) {
+		w.egressPolicy = policy
+	}
+}
+
+// WithProxy sets the proxy of destinations without their own proxy
+func WithProxy(proxy *destregistry.ProxyConfig) Option {
+	return func(w *WebhookDestination) {
+		w.proxy = proxy
+	}
+}
+
+// WithSuccessStatusCodes sets the default status codes of successful deliveries
+func WithSuccessStatusCodes(codes string) Option {
+	return func(w *WebhookDestination) {
+		w.successStatusCodes = codes
+	}
+}
+
+// WithNonRetryableStatusCodes sets the default status codes of failures that aren't retried
+func WithNonRetryableStatusCodes(codes string) Option {
+	return func(w *WebhookDestination) {
+		w.nonRetryableStatusCodes = codes
+	}
+}
+
+// WithDisableStatusCodes sets the default status codes of failures that disable the destination
+func WithDisableStatusCodes(codes string) Option {
+	return func(w *WebhookDestination) {
+		w.disableStatusCodes = codes
+	}
+}
+
 func New(loader metadata.MetadataLoader, opts ...Option) (*WebhookDestination, error) {
 	base, err := destregistry.NewBaseProvider(loader, "webhook")
 	if err != nil {
@@ -130,6 +219,10 @@ func New(loader metadata.MetadataLoader, opts ...Option) (*WebhookDestination, e
 		headerPrefix: "x-outpost-",
 		encoding:     DefaultEncoding,
 		algorithm:    DefaultAlgorithm,
+		mode:         ModeDefault,
+
+		responseHeaders:      DefaultResponseHeaders,
+		responseBodyMaxBytes: DefaultResponseBodyMaxBytes,
 	}
 	for _, opt := range opts {
 		opt(destination)
@@ -160,7 +253,19 @@ func (d *WebhookDestination) ObfuscateDestination(destination *models.Destinatio
 	// 1. They're needed for secret rotation logic
 	// 2. They're less security-critical than other provider credentials (e.g. AWS keys)
 	// TODO: Implement proper secret obfuscation later if needed
+	//
+	// Private signing keys are obfuscated, as tenants verify deliveries with
+	// the public keys instead.
 	for key, value := range destination.Credentials {
+		if d.signsWithPublicKeys() && (key == "secret" || key == "previous_secret") {
+			value = destregistry.ObfuscateValue(value)
+		}
+		if key == "custom_headers" {
+			value = obfuscateCustomHeaders(value)
+		}
+		if sensitiveCredentialKeys[key] {
+			value = destregistry.ObfuscateValue(value)
+		}
 		result.Credentials[key] = value
 	}
 
@@ -174,6 +279,11 @@ func (d *WebhookDestination) Validate(ctx context.Context, destination *models.D
 	return nil
 }
 
+// SupportsBatch returns true as webhook publishers can deliver batches of events.
+func (d *WebhookDestination) SupportsBatch() bool {
+	return true
+}
+
 func (d *WebhookDestination) GetSignatureEncoding() string {
 	return d.encoding
 }
@@ -205,47 +315,222 @@ func (d *WebhookDestination) CreatePublisher(ctx context.Context, destination *m
 		})
 	}
 
-	sm := NewSignatureManager(
-		secrets,
-		WithSignatureFormatter(NewSignatureFormatter(d.signatureContentTemplate)),
-		WithHeaderFormatter(NewHeaderFormatter(d.signatureHeaderTemplate)),
-		WithEncoder(GetEncoder(d.encoding)),
-		WithAlgorithm(GetAlgorithm(d.algorithm)),
-	)
+	sm, err := d.makeSignatureManager(secrets)
+	if err != nil {
+		return nil, err
+	}
 
-	httpClient := d.BaseProvider.MakeHTTPClient(destregistry.HTTPClientConfig{
-		UserAgent: &d.userAgent,
-	})
+	headers, err := newCustomHeaders(config.CustomHeaders, creds.CustomHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClientConfig := destregistry.HTTPClientConfig{
+		UserAgent:    &d.userAgent,
+		EgressPolicy: d.egressPolicy,
+	}
+	if !config.TLS.IsZero() {
+		tlsConfig, err := config.TLS.Build()
+		if err != nil {
+			return nil, err
+		}
+		httpClientConfig.TLSConfig = tlsConfig
+	}
+	if !config.Proxy.IsZero() {
+		proxy, err := config.Proxy.Build()
+		if err != nil {
+			return nil, err
+		}
+		httpClientConfig.Proxy = proxy
+	}
+	httpClient := d.BaseProvider.MakeHTTPClient(httpClientConfig)
 
 	return &WebhookPublisher{
 		BasePublisher:          &destregistry.BasePublisher{},
 		httpClient:             httpClient,
 		url:                    config.URL,
+		method:                 config.Method,
 		headerPrefix:           d.headerPrefix,
+		customHeaders:          headers,
+		auth:                   newAuthenticator(config.Auth, httpClient),
 		secrets:                secrets,
 		sm:                     sm,
 		disableEventIDHeader:   d.disableEventIDHeader,
 		disableSignatureHeader: d.disableSignatureHeader,
 		disableTimestampHeader: d.disableTimestampHeader,
 		disableTopicHeader:     d.disableTopicHeader,
+		mode:                   d.mode,
+		response:               config.Response,
+		responseHeaders:        d.responseHeaders,
+		responseBodyMaxBytes:   d.responseBodyMaxBytes,
 	}, nil
 }
 
+func (d *WebhookDestination) makeSignatureManager(secrets []WebhookSecret) (*SignatureManager, error) {
+	if d.mode != ModeStandard {
+		algorithm := GetAlgorithm(d.algorithm)
+		if err := prepareSigningKeys(algorithm, secrets); err != nil {
+			return nil, err
+		}
+		return NewSignatureManager(
+			secrets,
+			WithSignatureFormatter(NewSignatureFormatter(d.signatureContentTemplate)),
+			WithHeaderFormatter(NewHeaderFormatter(d.signatureHeaderTemplate)),
+			WithEncoder(GetEncoder(d.encoding)),
+			WithAlgorithm(algorithm),
+		), nil
+	}
+
+	// Standard Webhooks signs with the decoded secrets
+	keys := make([]WebhookSecret, len(secrets))
+	for i, secret := range secrets {
+		key, err := decodeStandardSecret(secret.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret: %w", err)
+		}
+		keys[i] = secret
+		keys[i].Key = key
+	}
+	return NewSignatureManager(
+		keys,
+		WithSignatureFormatter(NewSignatureFormatter(standardSignatureContentTemplate)),
+		WithHeaderFormatter(NewHeaderFormatter(standardSignatureHeaderTemplate)),
+		WithEncoder(Base64Encoder{}),
+		WithAlgorithm(NewHmacSHA256()),
+	), nil
+}
+
+// CertificateExpiry returns when the destination's client certificate expires.
+func (d *WebhookDestination) CertificateExpiry(destination *models.Destination) (*time.Time, error) {
+	tlsConfig := &destregistry.TLSClientConfig{ClientCert: destination.Credentials["tls_client_cert"]}
+	return tlsConfig.ClientCertExpiry()
+}
+
+// resolveTLSConfig parses and validates the client certificate and server
+// trust settings of the destination.
+func resolveTLSConfig(destination *models.Destination) (*destregistry.TLSClientConfig, error) {
+	tlsConfig := &destregistry.TLSClientConfig{
+		ClientCert:        destination.Credentials["tls_client_cert"],
+		ClientKey:         destination.Credentials["tls_client_key"],
+		CACerts:           destination.Config["tls_ca_cert"],
+		ServerFingerprint: destination.Config["tls_server_fingerprint"],
+	}
+	if tlsConfig.IsZero() {
+		return tlsConfig, nil
+	}
+
+	if tlsConfig.ClientCert != "" && tlsConfig.ClientKey == "" {
+		return nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: "credentials.tls_client_key",
+			Type:  "required",
+		}})
+	}
+	if tlsConfig.ClientKey != "" && tlsConfig.ClientCert == "" {
+		return nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: "credentials.tls_client_cert",
+			Type:  "required",
+		}})
+	}
+
+	if _, err := tlsConfig.Build(); err != nil {
+		var field string
+		switch {
+		case errors.Is(err, destregistry.ErrInvalidClientCertificate):
+			field = "credentials.tls_client_cert"
+		case errors.Is(err, destregistry.ErrInvalidClientKey):
+			field = "credentials.tls_client_key"
+		case errors.Is(err, destregistry.ErrInvalidCACertificate):
+			field = "config.tls_ca_cert"
+		case errors.Is(err, destregistry.ErrInvalidServerFingerprint):
+			field = "config.tls_server_fingerprint"
+		default:
+			return nil, err
+		}
+		return nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: field,
+			Type:  "pattern",
+		}})
+	}
+	return tlsConfig, nil
+}
+
+// generateSecret creates a new signing secret in the format of the webhook mode.
+func (d *WebhookDestination) generateSecret() (string, error) {
+	if d.mode == ModeStandard {
+		return generateStandardSecret()
+	}
+	if isAsymmetricAlgorithm(d.algorithm) {
+		return generateSigningKey(d.algorithm)
+	}
+	return generateSignatureSecret()
+}
+
 func (d *WebhookDestination) resolveConfig(ctx context.Context, destination *models.Destination) (*WebhookDestinationConfig, *WebhookDestinationCredentials, error) {
 	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
 		return nil, nil, err
 	}
 
 	config := &WebhookDestinationConfig{
-		URL: destination.Config["url"],
+		URL:    destination.Config["url"],
+		Method: destination.Config["method"],
+	}
+	if config.Method == "" {
+		config.Method = http.MethodPost
 	}
 
+	// Config headers are templates of the event
+	customHeaders, err := parseCustomHeaders(destination.Config["custom_headers"])
+	if err == nil {
+		_, err = newCustomHeaders(customHeaders, nil)
+	}
+	if err != nil {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: "config.custom_headers",
+			Type:  "pattern",
+		}})
+	}
+	config.CustomHeaders = customHeaders
+
+	auth, err := resolveAuthConfig(destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.Auth = auth
+
+	tlsConfig, err := resolveTLSConfig(destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.TLS = tlsConfig
+
+	proxy, err := destregistry.ResolveProxyConfig(d.proxy, destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.Proxy = proxy
+
+	response, err := d.resolveResponseConfig(destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.Response = response
+
 	// Parse credentials directly from map
 	creds := &WebhookDestinationCredentials{
 		Secret:         destination.Credentials["secret"],
 		PreviousSecret: destination.Credentials["previous_secret"],
 	}
 
+	// Credential headers hold secrets such as API keys and are sent as is
+	secretHeaders, err := parseCustomHeaders(destination.Credentials["custom_headers"])
+	if err != nil {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: "credentials.custom_headers",
+			Type:  "pattern",
+		}})
+	}
+	creds.CustomHeaders = secretHeaders
+
 	// Skip validation if no relevant credentials are passed
 	if destination.Credentials["secret"] == "" &&
 		destination.Credentials["previous_secret"] == "" &&
@@ -273,6 +558,39 @@ func (d *WebhookDestination) resolveConfig(ctx context.Context, destination *mod
 		creds.PreviousSecretInvalidAt = invalidAt
 	}
 
+	// Standard Webhooks secrets must be base64-encoded
+	if d.mode == ModeStandard {
+		if _, err := decodeStandardSecret(creds.Secret); err != nil {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+				Field: "credentials.secret",
+				Type:  "pattern",
+			}})
+		}
+		if _, err := decodeStandardSecret(creds.PreviousSecret); creds.PreviousSecret != "" && err != nil {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+				Field: "credentials.previous_secret",
+				Type:  "pattern",
+			}})
+		}
+	}
+
+	// Asymmetric signing keys must be private keys of the algorithm. Other
+	// secrets are shared secrets, still signed with HMAC.
+	if d.signsWithPublicKeys() {
+		if _, err := parseSigningKey(creds.Secret, d.algorithm); isSigningKey(creds.Secret) && err != nil {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+				Field: "credentials.secret",
+				Type:  "pattern",
+			}})
+		}
+		if _, err := parseSigningKey(creds.PreviousSecret, d.algorithm); isSigningKey(creds.PreviousSecret) && err != nil {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+				Field: "credentials.previous_secret",
+				Type:  "pattern",
+			}})
+		}
+	}
+
 	// If previous secret is provided, validate invalidation time
 	if creds.PreviousSecret != "" && creds.PreviousSecretInvalidAt.IsZero() {
 		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
@@ -318,7 +636,7 @@ func (d *WebhookDestination) rotateSecret(newDest, origDest *models.Destination)
 	creds["previous_secret"] = origDest.Credentials["secret"]
 
 	// Generate a new secret
-	secret, err := generateSignatureSecret()
+	secret, err := d.generateSecret()
 	if err != nil {
 		return nil, err
 	}
@@ -419,7 +737,7 @@ func (d *WebhookDestination) ensureInitializedCredentials(creds map[string]strin
 	}
 
 	// Otherwise generate a new secret
-	secret, err := generateSignatureSecret()
+	secret, err := d.generateSecret()
 	if err != nil {
 		return nil, err
 	}
@@ -437,7 +755,7 @@ func (d *WebhookDestination) validateAndSanitizeCredentials(creds map[string]str
 
 	// Clean up any extra fields
 	cleanCreds := make(map[string]string)
-	for _, key := range []string{"secret", "previous_secret", "previous_secret_invalid_at"} {
+	for _, key := range append([]string{"secret", "previous_secret", "previous_secret_invalid_at"}, tenantCredentialKeys...) {
 		if value := creds[key]; value != "" {
 			cleanCreds[key] = value
 		}
@@ -469,6 +787,13 @@ func (d *WebhookDestination) Preprocess(newDestination *models.Destination, orig
 		return err
 	}
 
+	// Credentials other than the signing secrets can be set by tenants
+	for _, key := range tenantCredentialKeys {
+		if value := newDestination.Credentials[key]; value != "" {
+			cleanCredentials[key] = value
+		}
+	}
+
 	// Final validation and sanitization
 	cleanCredentials, err = d.validateAndSanitizeCredentials(cleanCredentials)
 	if err != nil {
@@ -476,6 +801,7 @@ func (d *WebhookDestination) Preprocess(newDestination *models.Destination, orig
 	}
 
 	newDestination.Credentials = cleanCredentials
+	d.preprocessVerification(newDestination, originalDestination)
 	return nil
 }
 
@@ -483,13 +809,20 @@ type WebhookPublisher struct {
 	*destregistry.BasePublisher
 	httpClient             *http.Client
 	url                    string
+	method                 string
 	headerPrefix           string
+	customHeaders          *customHeaders
+	auth                   authenticator
 	secrets                []WebhookSecret
 	sm                     *SignatureManager
 	disableEventIDHeader   bool
 	disableSignatureHeader bool
 	disableTimestampHeader bool
 	disableTopicHeader     bool
+	mode                   string
+	response               *WebhookResponseConfig
+	responseHeaders        []string
+	responseBodyMaxBytes   int
 }
 
 func (p *WebhookPublisher) Close() error {
@@ -505,43 +838,119 @@ func (p *WebhookPublisher) Publish(ctx context.Context, event *models.Event) (*d
 
 	httpReq, err := p.Format(ctx, event)
 	if err != nil {
+		return formatFailure(err)
+	}
+	return p.send(httpReq)
+}
+
+// PublishBatch delivers the events in a single request signed once.
+func (p *WebhookPublisher) PublishBatch(ctx context.Context, events []*models.Event) (*destregistry.Delivery, error) {
+	if err := p.BasePublisher.StartPublish(); err != nil {
 		return nil, err
 	}
+	defer p.BasePublisher.FinishPublish()
 
-	resp, err := p.httpClient.Do(httpReq)
+	httpReq, err := p.FormatBatch(ctx, events)
+	if err != nil {
+		return formatFailure(err)
+	}
+	return p.send(httpReq)
+}
+
+// formatFailure fails the attempt when the request can't be signed, so that
+// it's logged and retried like any failed delivery.
+func formatFailure(err error) (*destregistry.Delivery, error) {
+	if !errors.Is(err, errSigningFailed) {
+		return nil, err
+	}
+	return &destregistry.Delivery{
+		Status: "failed",
+		Code:   "ERR",
+	}, destregistry.NewErrDestinationPublishAttempt(err, "webhook", map[string]interface{}{
+		"error":   "signing_failed",
+		"message": err.Error(),
+	})
+}
+
+func (p *WebhookPublisher) send(httpReq *http.Request) (*destregistry.Delivery, error) {
+	if p.auth != nil {
+		if err := p.auth.authenticate(httpReq.Context(), httpReq); err != nil {
+			return nil, destregistry.NewErrDestinationPublishAttempt(err, "webhook", map[string]interface{}{
+				"error":   "auth_failed",
+				"message": err.Error(),
+			})
+		}
+	}
+
+	timer := newRequestTimer()
+	httpReq = httpReq.WithContext(timer.trace(httpReq.Context()))
+	resp, err := p.do(httpReq)
 	if err != nil {
+		code := "request_failed"
+		if errors.Is(err, destregistry.ErrEgressBlocked) {
+			code = destregistry.ErrorCodeEgressBlocked
+		}
 		return nil, destregistry.NewErrDestinationPublishAttempt(err, "webhook", map[string]interface{}{
-			"error":   "request_failed",
+			"error":   code,
 			"message": err.Error(),
 		})
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		bodyBytes, _ := io.ReadAll(resp.Body)
+	body, truncated := readResponseBody(resp, p.responseBodyMaxBytes)
+	if !p.response.isSuccess(resp.StatusCode) {
 		delivery := &destregistry.Delivery{
 			Status: "failed",
 			Code:   fmt.Sprintf("%d", resp.StatusCode),
 		}
-		parseResponse(delivery, resp)
-		return delivery, destregistry.NewErrDestinationPublishAttempt(
-			fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(bodyBytes)),
-			"webhook",
-			map[string]interface{}{
+		p.parseResponse(delivery, resp, body, truncated, timer)
+		return delivery, &destregistry.ErrDestinationPublishAttempt{
+			Err:      fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body)),
+			Provider: "webhook",
+			Data: map[string]interface{}{
 				"status": resp.StatusCode,
-				"body":   string(bodyBytes),
-			})
+				"body":   string(body),
+			},
+			RetryAfter:         parseRetryAfter(resp, time.Now()),
+			NonRetryable:       p.response.isNonRetryable(resp.StatusCode),
+			DisableDestination: p.response.disablesDestination(resp.StatusCode),
+		}
 	}
 
 	delivery := &destregistry.Delivery{
 		Status: "success",
 		Code:   fmt.Sprintf("%d", resp.StatusCode),
 	}
-	parseResponse(delivery, resp)
+	p.parseResponse(delivery, resp, body, truncated, timer)
 
 	return delivery, nil
 }
 
+// do sends the request. If the receiver rejects credentials that can be
+// refreshed, such as an expired OAuth2 token, the request is retried once with
+// new credentials.
+func (p *WebhookPublisher) do(httpReq *http.Request) (*http.Response, error) {
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || p.auth == nil || !p.auth.invalidate(httpReq) {
+		return resp, err
+	}
+
+	retryReq := httpReq.Clone(httpReq.Context())
+	if httpReq.GetBody != nil {
+		body, err := httpReq.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retryReq.Body = body
+	}
+	if err := p.auth.authenticate(retryReq.Context(), retryReq); err != nil {
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return p.httpClient.Do(retryReq)
+}
+
 // Format is a helper function to format the event data into an HTTP request.
 func (p *WebhookPublisher) Format(ctx context.Context, event *models.Event) (*http.Request, error) {
 	now := time.Now()
@@ -550,30 +959,41 @@ func (p *WebhookPublisher) Format(ctx context.Context, event *models.Event) (*ht
 		return nil, fmt.Errorf("failed to marshal event data: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewBuffer(rawBody))
+	req, err := http.NewRequestWithContext(ctx, p.method, p.url, bytes.NewBuffer(rawBody))
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	// Custom headers are set first so they can't override the Outpost headers
+	p.customHeaders.apply(req, newHeaderTemplatePayload(event))
+
+	if p.mode == ModeStandard {
+		if err := p.setStandardHeaders(req, event.ID, now, rawBody); err != nil {
+			return nil, err
+		}
+	}
 
 	// Add default headers unless disabled
-	if !p.disableTimestampHeader {
+	if !p.disableTimestampHeader && p.mode != ModeStandard {
 		req.Header.Set(p.headerPrefix+"timestamp", fmt.Sprintf("%d", now.UnixMilli()))
 	}
-	if !p.disableEventIDHeader {
+	if !p.disableEventIDHeader && p.mode != ModeStandard {
 		req.Header.Set(p.headerPrefix+"event-id", event.ID)
 	}
 	if !p.disableTopicHeader {
 		req.Header.Set(p.headerPrefix+"topic", event.Topic)
 	}
-	if !p.disableSignatureHeader {
-		signatureHeader := p.sm.GenerateSignatureHeader(SignaturePayload{
+	if !p.disableSignatureHeader && p.mode != ModeStandard {
+		signatureHeader, err := p.sm.GenerateSignatureHeader(SignaturePayload{
 			EventID:   event.ID,
 			Topic:     event.Topic,
 			Timestamp: now,
 			Body:      string(rawBody),
 		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errSigningFailed, err)
+		}
 		if signatureHeader != "" {
 			req.Header.Set(p.headerPrefix+"signature", signatureHeader)
 		}
@@ -587,6 +1007,72 @@ func (p *WebhookPublisher) Format(ctx context.Context, event *models.Event) (*ht
 	return req, nil
 }
 
+// BatchEvent is an event in the body of a batch request.
+type BatchEvent struct {
+	ID       string          `json:"id"`
+	Topic    string          `json:"topic"`
+	Time     time.Time       `json:"time"`
+	Metadata models.Metadata `json:"metadata,omitempty"`
+	Data     models.Data     `json:"data"`
+}
+
+// FormatBatch is a helper function to format the events into a single HTTP request.
+// The body is a JSON array of BatchEvent. As the request carries several events,
+// the event ID, topic and metadata headers are omitted.
+func (p *WebhookPublisher) FormatBatch(ctx context.Context, events []*models.Event) (*http.Request, error) {
+	now := time.Now()
+	batch := make([]BatchEvent, len(events))
+	for i, event := range events {
+		batch[i] = BatchEvent{
+			ID:       event.ID,
+			Topic:    event.Topic,
+			Time:     event.Time,
+			Metadata: event.Metadata,
+			Data:     event.Data,
+		}
+	}
+	rawBody, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, p.method, p.url, bytes.NewBuffer(rawBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	p.customHeaders.applyBatch(req, events)
+	req.Header.Set(p.headerPrefix+"batch-size", fmt.Sprintf("%d", len(events)))
+	if p.mode == ModeStandard {
+		eventIDs := make([]string, len(events))
+		for i, event := range events {
+			eventIDs[i] = event.ID
+		}
+		if err := p.setStandardHeaders(req, batchMessageID(eventIDs), now, rawBody); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}
+	if !p.disableTimestampHeader {
+		req.Header.Set(p.headerPrefix+"timestamp", fmt.Sprintf("%d", now.UnixMilli()))
+	}
+	if !p.disableSignatureHeader {
+		signatureHeader, err := p.sm.GenerateSignatureHeader(SignaturePayload{
+			Timestamp: now,
+			Body:      string(rawBody),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errSigningFailed, err)
+		}
+		if signatureHeader != "" {
+			req.Header.Set(p.headerPrefix+"signature", signatureHeader)
+		}
+	}
+
+	return req, nil
+}
+
 // generateSignatureSecret creates a cryptographically secure random secret suitable for HMAC signatures.
 // The secret is 32 bytes (256 bits) encoded as a hex string.
 func generateSignatureSecret() (string, error) {
@@ -617,6 +1103,10 @@ func GetAlgorithm(algorithm string) SigningAlgorithm {
 		return NewHmacSHA1()
 	case "hmac-sha256":
 		return NewHmacSHA256()
+	case AlgorithmEd25519:
+		return NewEd25519()
+	case AlgorithmECDSAP256:
+		return NewECDSAP256()
 	default:
 		return NewHmacSHA256() // default to hmac-sha256
 	}
@@ -632,25 +1122,25 @@ func isTruthy(value string) bool {
 	}
 }
 
-func parseResponse(delivery *destregistry.Delivery, resp *http.Response) {
-	if strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		var response map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &response); err != nil {
-			delivery.Response = map[string]interface{}{
-				"status": resp.StatusCode,
-				"body":   string(bodyBytes),
-			}
-		}
-		delivery.Response = map[string]interface{}{
-			"status": resp.StatusCode,
-			"body":   response,
-		}
-	} else {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		delivery.Response = map[string]interface{}{
-			"status": resp.StatusCode,
-			"body":   string(bodyBytes),
+// parseRetryAfter returns the delay requested by the Retry-After header of a
+// 429 or 503 response, either in seconds or as an HTTP date. It returns 0 if
+// there's no valid header.
+func parseRetryAfter(resp *http.Response, now time.Time) time.Duration {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+	header := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds <= 0 {
+			return 0
 		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
 	}
+	return 0
 }
@@ -2,6 +2,7 @@ package destwebhook_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
 	testsuite "github.com/hookdeck/outpost/internal/destregistry/testing"
 	"github.com/hookdeck/outpost/internal/models"
@@ -536,3 +538,208 @@ func TestWebhookPublisher_SignatureTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestWebhookPublisher_FormatBatch(t *testing.T) {
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"url": "http://example.com",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{
+			"secret": "test-secret",
+		}),
+	)
+	publisher, err := provider.CreatePublisher(context.Background(), &dest)
+	require.NoError(t, err)
+
+	event1 := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTopic("order.created"),
+		testutil.EventFactory.WithData(map[string]interface{}{"id": "ord_1"}),
+	)
+	event2 := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTopic("order.updated"),
+		testutil.EventFactory.WithData(map[string]interface{}{"id": "ord_2"}),
+	)
+
+	req, err := publisher.(*destwebhook.WebhookPublisher).FormatBatch(context.Background(), []*models.Event{&event1, &event2})
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	var batch []destwebhook.BatchEvent
+	require.NoError(t, json.Unmarshal(body, &batch))
+	require.Len(t, batch, 2)
+	assert.Equal(t, event1.ID, batch[0].ID)
+	assert.Equal(t, "order.created", batch[0].Topic)
+	assert.Equal(t, models.Data{"id": "ord_1"}, batch[0].Data)
+	assert.Equal(t, event2.ID, batch[1].ID)
+
+	assert.Equal(t, "2", req.Header.Get("x-outpost-batch-size"))
+	assert.Empty(t, req.Header.Get("x-outpost-event-id"), "batches should not have an event id header")
+	assert.Empty(t, req.Header.Get("x-outpost-topic"), "batches should not have a topic header")
+
+	// The batch is signed once over the whole body
+	signatureHeader := req.Header.Get("x-outpost-signature")
+	parts := strings.Split(signatureHeader, "v0=")
+	require.Len(t, parts, 2)
+	sm := destwebhook.NewSignatureManager([]destwebhook.WebhookSecret{
+		{Key: "test-secret", CreatedAt: time.Now()},
+	})
+	assert.True(t, sm.VerifySignature(parts[1], "test-secret", destwebhook.SignaturePayload{
+		Timestamp: time.Now(),
+		Body:      string(body),
+	}))
+}
+
+func TestWebhookPublisher_CustomHeaders(t *testing.T) {
+	t.Parallel()
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"url":    "http://example.com",
+			"method": "PUT",
+			"custom_headers": `{
+				"x-api-version": "2024-01-01",
+				"x-customer-id": "{{.Data.customer_id}}",
+				"x-source": "{{.Metadata.source | upper}}",
+				"x-missing": "{{.Data.missing}}",
+				"x-outpost-topic": "overridden"
+			}`,
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{
+			"secret":         "test-secret",
+			"custom_headers": `{"authorization": "Bearer secret-token"}`,
+		}),
+	)
+	require.NoError(t, provider.Validate(context.Background(), &dest))
+	publisher, err := provider.CreatePublisher(context.Background(), &dest)
+	require.NoError(t, err)
+
+	t.Run("should set method and headers", func(t *testing.T) {
+		t.Parallel()
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTopic("order.created"),
+			testutil.EventFactory.WithMetadata(map[string]string{"source": "shop"}),
+			testutil.EventFactory.WithData(map[string]interface{}{"customer_id": "cus_123"}),
+		)
+		req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.MethodPut, req.Method)
+		assert.Equal(t, "2024-01-01", req.Header.Get("x-api-version"))
+		assert.Equal(t, "cus_123", req.Header.Get("x-customer-id"))
+		assert.Equal(t, "SHOP", req.Header.Get("x-source"))
+		assert.Equal(t, "Bearer secret-token", req.Header.Get("authorization"))
+		assert.Empty(t, req.Header.Values("x-missing"), "headers referring to missing fields should be omitted")
+		assert.Equal(t, "order.created", req.Header.Get("x-outpost-topic"), "custom headers should not override Outpost headers")
+	})
+
+	t.Run("should set headers on batches", func(t *testing.T) {
+		t.Parallel()
+		event1 := testutil.EventFactory.Any(
+			testutil.EventFactory.WithMetadata(map[string]string{"source": "shop"}),
+			testutil.EventFactory.WithData(map[string]interface{}{"customer_id": "cus_123"}),
+		)
+		event2 := testutil.EventFactory.Any(
+			testutil.EventFactory.WithMetadata(map[string]string{"source": "shop"}),
+			testutil.EventFactory.WithData(map[string]interface{}{"customer_id": "cus_456"}),
+		)
+		req, err := publisher.(*destwebhook.WebhookPublisher).FormatBatch(context.Background(), []*models.Event{&event1, &event2})
+		require.NoError(t, err)
+
+		assert.Equal(t, http.MethodPut, req.Method)
+		assert.Equal(t, "2024-01-01", req.Header.Get("x-api-version"))
+		assert.Equal(t, "Bearer secret-token", req.Header.Get("authorization"))
+		assert.Equal(t, "SHOP", req.Header.Get("x-source"), "headers rendering the same value for every event should be set")
+		assert.Empty(t, req.Header.Values("x-customer-id"), "headers rendering different values should be omitted")
+	})
+
+	t.Run("should obfuscate secret header values", func(t *testing.T) {
+		t.Parallel()
+		obfuscated := provider.ObfuscateDestination(&dest)
+		assert.Contains(t, obfuscated.Credentials["custom_headers"], `"authorization":"Bear***`)
+		assert.NotContains(t, obfuscated.Credentials["custom_headers"], "secret-token")
+	})
+}
+
+func TestWebhookPublisher_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{
+			name:       "429 with seconds",
+			status:     http.StatusTooManyRequests,
+			retryAfter: "120",
+			expected:   2 * time.Minute,
+		},
+		{
+			name:       "503 with http date",
+			status:     http.StatusServiceUnavailable,
+			retryAfter: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+			expected:   time.Hour,
+		},
+		{
+			name:       "429 without header",
+			status:     http.StatusTooManyRequests,
+			retryAfter: "",
+			expected:   0,
+		},
+		{
+			name:       "invalid header",
+			status:     http.StatusTooManyRequests,
+			retryAfter: "soon",
+			expected:   0,
+		},
+		{
+			name:       "other status",
+			status:     http.StatusInternalServerError,
+			retryAfter: "120",
+			expected:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+			require.NoError(t, err)
+			destination := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("webhook"),
+				testutil.DestinationFactory.WithConfig(map[string]string{
+					"url": server.URL,
+				}),
+			)
+			publisher, err := provider.CreatePublisher(context.Background(), &destination)
+			require.NoError(t, err)
+
+			event := testutil.EventFactory.Any()
+			delivery, err := publisher.Publish(context.Background(), &event)
+			require.NotNil(t, delivery)
+			assert.Equal(t, "failed", delivery.Status)
+
+			var publishErr *destregistry.ErrDestinationPublishAttempt
+			require.ErrorAs(t, err, &publishErr)
+			assert.InDelta(t, tt.expected, publishErr.RetryAfter, float64(2*time.Second))
+		})
+	}
+}
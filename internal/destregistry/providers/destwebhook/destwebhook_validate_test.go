@@ -72,6 +72,60 @@ func TestWebhookDestination_Validate(t *testing.T) {
 		assert.Equal(t, "config.url", validationErr.Errors[0].Field)
 		assert.Equal(t, "pattern", validationErr.Errors[0].Type)
 	})
+
+	t.Run("should validate method", func(t *testing.T) {
+		t.Parallel()
+		invalidDestination := validDestination
+		invalidDestination.Config = map[string]string{
+			"url":    "https://example.com",
+			"method": "GET",
+		}
+		err := webhookDestination.Validate(context.Background(), &invalidDestination)
+
+		var validationErr *destregistry.ErrDestinationValidation
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.method", validationErr.Errors[0].Field)
+		assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+	})
+
+	t.Run("should validate custom headers", func(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name    string
+			headers string
+		}{
+			{name: "not an object", headers: `["x-api-version"]`},
+			{name: "non-string value", headers: `{"x-api-version": 1}`},
+			{name: "invalid header name", headers: `{"x api version": "1"}`},
+			{name: "invalid template", headers: `{"x-customer-id": "{{.Data.customer_id"}`},
+		}
+		for _, tt := range tests {
+			invalidDestination := validDestination
+			invalidDestination.Config = map[string]string{
+				"url":            "https://example.com",
+				"custom_headers": tt.headers,
+			}
+			err := webhookDestination.Validate(context.Background(), &invalidDestination)
+
+			var validationErr *destregistry.ErrDestinationValidation
+			if assert.ErrorAs(t, err, &validationErr, tt.name) {
+				assert.Equal(t, "config.custom_headers", validationErr.Errors[0].Field, tt.name)
+				assert.Equal(t, "pattern", validationErr.Errors[0].Type, tt.name)
+			}
+		}
+
+		invalidDestination := validDestination
+		invalidDestination.Credentials = map[string]string{
+			"secret":         "test-secret",
+			"custom_headers": "not json",
+		}
+		err := webhookDestination.Validate(context.Background(), &invalidDestination)
+
+		var validationErr *destregistry.ErrDestinationValidation
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "credentials.custom_headers", validationErr.Errors[0].Field)
+		assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+	})
 }
 
 func TestWebhookDestination_ValidateSecrets(t *testing.T) {
@@ -600,4 +654,26 @@ func TestWebhookDestination_Preprocess(t *testing.T) {
 		assert.Equal(t, "old-secret", newDestination.Credentials["previous_secret"])
 		assert.NotEmpty(t, newDestination.Credentials["previous_secret_invalid_at"])
 	})
+	t.Run("tenant should be able to set secret headers", func(t *testing.T) {
+		t.Parallel()
+		originalDestination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{
+				"url": "https://example.com",
+			}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"secret": "current-secret",
+			}),
+		)
+
+		newDestination := originalDestination
+		newDestination.Credentials = maputil.MergeStringMaps(originalDestination.Credentials, map[string]string{
+			"custom_headers": `{"authorization": "Bearer token"}`,
+		})
+
+		err := webhookDestination.Preprocess(&newDestination, &originalDestination, &destregistry.PreprocessDestinationOpts{Role: "tenant"})
+		require.NoError(t, err)
+		assert.Equal(t, "current-secret", newDestination.Credentials["secret"])
+		assert.Equal(t, `{"authorization": "Bearer token"}`, newDestination.Credentials["custom_headers"])
+	})
 }
@@ -0,0 +1,80 @@
+package destwebhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPublisher_EgressPolicy(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/redirect" {
+			// Same port on another loopback address
+			u, _ := url.Parse("http://" + r.Host)
+			http.Redirect(w, r, "http://127.0.0.2:"+u.Port()+"/webhook", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	publish := func(t *testing.T, policy *destregistry.EgressPolicy, path string) (*destregistry.Delivery, error) {
+		t.Helper()
+		provider, err := destwebhook.New(testutil.Registry.MetadataLoader(), destwebhook.WithEgressPolicy(policy))
+		require.NoError(t, err)
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": server.URL + path}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"secret": "test-secret"}),
+		)
+		publisher, err := provider.CreatePublisher(context.Background(), &dest)
+		require.NoError(t, err)
+		event := testutil.EventFactory.Any()
+		return publisher.Publish(context.Background(), &event)
+	}
+
+	t.Run("should block loopback addresses by default", func(t *testing.T) {
+		policy, err := destregistry.NewEgressPolicy(false, nil, nil)
+		require.NoError(t, err)
+
+		_, err = publish(t, policy, "/webhook")
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.ErrorIs(t, publishErr.Err, destregistry.ErrEgressBlocked)
+		assert.Equal(t, destregistry.ErrorCodeEgressBlocked, publishErr.Data["error"])
+		assert.Equal(t, 0, requests)
+	})
+
+	t.Run("should connect to allowed CIDRs", func(t *testing.T) {
+		policy, err := destregistry.NewEgressPolicy(false, []string{"127.0.0.1/32"}, nil)
+		require.NoError(t, err)
+
+		delivery, err := publish(t, policy, "/webhook")
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+	})
+
+	t.Run("should block redirects to denied addresses", func(t *testing.T) {
+		policy, err := destregistry.NewEgressPolicy(false, []string{"127.0.0.0/8"}, []string{"127.0.0.2/32"})
+		require.NoError(t, err)
+
+		before := requests
+		_, err = publish(t, policy, "/redirect")
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Equal(t, destregistry.ErrorCodeEgressBlocked, publishErr.Data["error"])
+		assert.Equal(t, before+1, requests)
+	})
+}
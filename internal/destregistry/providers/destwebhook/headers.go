@@ -0,0 +1,144 @@
+package destwebhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+var (
+	errInvalidCustomHeaders = errors.New("custom headers must be a JSON object of header names to string values")
+
+	// headerNameRegex matches the token characters allowed in header names (RFC 9110).
+	headerNameRegex = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+)
+
+// HeaderTemplatePayload is the data available to custom header templates.
+type HeaderTemplatePayload struct {
+	EventID  string
+	Topic    string
+	Time     time.Time
+	Metadata map[string]string
+	Data     map[string]interface{}
+}
+
+// customHeaders are the headers added to each request. Config headers are
+// templates rendered for each event, while credential headers are secrets sent
+// as is.
+type customHeaders struct {
+	templates map[string]*template.Template
+	static    map[string]string
+}
+
+// parseCustomHeaders parses a JSON object of header names to values.
+func parseCustomHeaders(raw string) (map[string]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(raw), &headers); err != nil {
+		return nil, errInvalidCustomHeaders
+	}
+	for name := range headers {
+		if !headerNameRegex.MatchString(name) {
+			return nil, errInvalidCustomHeaders
+		}
+	}
+	return headers, nil
+}
+
+func newCustomHeaders(templates, static map[string]string) (*customHeaders, error) {
+	h := &customHeaders{
+		templates: make(map[string]*template.Template, len(templates)),
+		static:    static,
+	}
+	for name, value := range templates {
+		tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		h.templates[name] = tmpl
+	}
+	return h, nil
+}
+
+// apply sets the custom headers on the request. Templated headers that fail
+// to render, e.g. because they refer to a missing field, or render empty are
+// omitted.
+func (h *customHeaders) apply(req *http.Request, payload HeaderTemplatePayload) {
+	for name, tmpl := range h.templates {
+		if value, ok := render(tmpl, payload); ok {
+			req.Header.Set(name, value)
+		}
+	}
+	for name, value := range h.static {
+		req.Header.Set(name, value)
+	}
+}
+
+// applyBatch renders the templates for each event of a batch. A templated
+// header is only sent when it renders the same value for every event, as a
+// batch can't carry a different value per event.
+func (h *customHeaders) applyBatch(req *http.Request, events []*models.Event) {
+	for name, tmpl := range h.templates {
+		value, ok := "", len(events) > 0
+		for i, event := range events {
+			rendered, rendersOK := render(tmpl, newHeaderTemplatePayload(event))
+			if !rendersOK || (i > 0 && rendered != value) {
+				ok = false
+				break
+			}
+			value = rendered
+		}
+		if ok {
+			req.Header.Set(name, value)
+		}
+	}
+	for name, value := range h.static {
+		req.Header.Set(name, value)
+	}
+}
+
+// render executes the template, reporting false when it fails or renders empty.
+func render(tmpl *template.Template, payload HeaderTemplatePayload) (string, bool) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, payload); err != nil || buf.Len() == 0 {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+func newHeaderTemplatePayload(event *models.Event) HeaderTemplatePayload {
+	return HeaderTemplatePayload{
+		EventID:  event.ID,
+		Topic:    event.Topic,
+		Time:     event.Time,
+		Metadata: event.Metadata,
+		Data:     event.Data,
+	}
+}
+
+// obfuscateCustomHeaders masks the values of the headers while keeping their
+// names visible.
+func obfuscateCustomHeaders(raw string) string {
+	headers, err := parseCustomHeaders(raw)
+	if err != nil {
+		return destregistry.ObfuscateValue(raw)
+	}
+	for name, value := range headers {
+		headers[name] = destregistry.ObfuscateValue(value)
+	}
+	b, err := json.Marshal(headers)
+	if err != nil {
+		return destregistry.ObfuscateValue(raw)
+	}
+	return string(b)
+}
@@ -0,0 +1,271 @@
+package destwebhook_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM string
+	keyPEM  string
+}
+
+// newTestCertificate creates a certificate signed by the parent, or a
+// self-signed CA if there's no parent.
+func newTestCertificate(t *testing.T, parent *testCertificate, notAfter time.Time) *testCertificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "outpost-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+// newMTLSServer starts a server requiring client certificates signed by the CA.
+func newMTLSServer(t *testing.T, clientCA *testCertificate) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCA.cert)
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebhookPublisher_MTLS(t *testing.T) {
+	t.Parallel()
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	clientCA := newTestCertificate(t, nil, time.Now().Add(24*time.Hour))
+	clientCert := newTestCertificate(t, clientCA, time.Now().Add(12*time.Hour))
+	server := newMTLSServer(t, clientCA)
+	serverCAPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	serverFingerprint := sha256.Sum256(server.Certificate().Raw)
+
+	publish := func(t *testing.T, config, credentials map[string]string) (*destregistry.Delivery, error) {
+		t.Helper()
+		config["url"] = server.URL
+		credentials["secret"] = "test-secret"
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(credentials),
+		)
+		require.NoError(t, provider.Validate(context.Background(), &dest))
+		publisher, err := provider.CreatePublisher(context.Background(), &dest)
+		require.NoError(t, err)
+		event := testutil.EventFactory.Any()
+		return publisher.Publish(context.Background(), &event)
+	}
+
+	t.Run("should present the client certificate", func(t *testing.T) {
+		t.Parallel()
+		delivery, err := publish(t,
+			map[string]string{"tls_ca_cert": serverCAPEM},
+			map[string]string{"tls_client_cert": clientCert.certPEM, "tls_client_key": clientCert.keyPEM},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+	})
+
+	t.Run("should fail without the client certificate", func(t *testing.T) {
+		t.Parallel()
+		_, err := publish(t,
+			map[string]string{"tls_ca_cert": serverCAPEM},
+			map[string]string{},
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("should fail without the custom CA", func(t *testing.T) {
+		t.Parallel()
+		_, err := publish(t,
+			map[string]string{},
+			map[string]string{"tls_client_cert": clientCert.certPEM, "tls_client_key": clientCert.keyPEM},
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("should accept the pinned server certificate", func(t *testing.T) {
+		t.Parallel()
+		delivery, err := publish(t,
+			map[string]string{"tls_ca_cert": serverCAPEM, "tls_server_fingerprint": hex.EncodeToString(serverFingerprint[:])},
+			map[string]string{"tls_client_cert": clientCert.certPEM, "tls_client_key": clientCert.keyPEM},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+	})
+
+	t.Run("should reject a server certificate that isn't pinned", func(t *testing.T) {
+		t.Parallel()
+		otherFingerprint := sha256.Sum256(clientCA.cert.Raw)
+		_, err := publish(t,
+			map[string]string{"tls_ca_cert": serverCAPEM, "tls_server_fingerprint": hex.EncodeToString(otherFingerprint[:])},
+			map[string]string{"tls_client_cert": clientCert.certPEM, "tls_client_key": clientCert.keyPEM},
+		)
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Contains(t, publishErr.Data["message"], destregistry.ErrServerFingerprintMismatch.Error())
+	})
+}
+
+func TestWebhookDestination_ValidateTLS(t *testing.T) {
+	t.Parallel()
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	ca := newTestCertificate(t, nil, time.Now().Add(24*time.Hour))
+	cert := newTestCertificate(t, ca, time.Now().Add(12*time.Hour))
+	otherCert := newTestCertificate(t, ca, time.Now().Add(12*time.Hour))
+
+	tests := []struct {
+		name        string
+		config      map[string]string
+		credentials map[string]string
+		field       string
+		errType     string
+	}{
+		{
+			name:        "malformed client certificate",
+			credentials: map[string]string{"tls_client_cert": "-----BEGIN CERTIFICATE-----\nnot a cert\n-----END CERTIFICATE-----", "tls_client_key": cert.keyPEM},
+			field:       "credentials.tls_client_cert",
+			errType:     "pattern",
+		},
+		{
+			name:        "client key instead of certificate",
+			credentials: map[string]string{"tls_client_cert": cert.keyPEM, "tls_client_key": cert.keyPEM},
+			field:       "credentials.tls_client_cert",
+			errType:     "pattern",
+		},
+		{
+			name:        "key of another certificate",
+			credentials: map[string]string{"tls_client_cert": cert.certPEM, "tls_client_key": otherCert.keyPEM},
+			field:       "credentials.tls_client_key",
+			errType:     "pattern",
+		},
+		{
+			name:        "certificate without key",
+			credentials: map[string]string{"tls_client_cert": cert.certPEM},
+			field:       "credentials.tls_client_key",
+			errType:     "required",
+		},
+		{
+			name:    "malformed CA",
+			config:  map[string]string{"tls_ca_cert": ca.certPEM + "trailing garbage"},
+			field:   "config.tls_ca_cert",
+			errType: "pattern",
+		},
+		{
+			name:    "malformed fingerprint",
+			config:  map[string]string{"tls_server_fingerprint": "abc"},
+			field:   "config.tls_server_fingerprint",
+			errType: "pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			config := map[string]string{"url": "https://example.com"}
+			for key, value := range tt.config {
+				config[key] = value
+			}
+			credentials := map[string]string{"secret": "test-secret"}
+			for key, value := range tt.credentials {
+				credentials[key] = value
+			}
+			dest := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("webhook"),
+				testutil.DestinationFactory.WithConfig(config),
+				testutil.DestinationFactory.WithCredentials(credentials),
+			)
+
+			err := provider.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Errors[0].Field)
+			assert.Equal(t, tt.errType, validationErr.Errors[0].Type)
+		})
+	}
+
+	t.Run("should return the client certificate expiry", func(t *testing.T) {
+		t.Parallel()
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"tls_client_cert": cert.certPEM + ca.certPEM,
+				"tls_client_key":  cert.keyPEM,
+			}),
+		)
+		expiresAt, err := provider.CertificateExpiry(&dest)
+		require.NoError(t, err)
+		require.NotNil(t, expiresAt)
+		assert.True(t, cert.cert.NotAfter.Equal(*expiresAt))
+
+		obfuscated := provider.ObfuscateDestination(&dest)
+		assert.Equal(t, cert.certPEM+ca.certPEM, obfuscated.Credentials["tls_client_cert"])
+		assert.NotEqual(t, cert.keyPEM, obfuscated.Credentials["tls_client_key"])
+
+		dest.Credentials = map[string]string{}
+		expiresAt, err = provider.CertificateExpiry(&dest)
+		require.NoError(t, err)
+		assert.Nil(t, expiresAt)
+	})
+}
@@ -0,0 +1,171 @@
+package destwebhook_test
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardProxyStub is a forward proxy sending every request to the target
+// server, whatever its host.
+type forwardProxyStub struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []string // method and host of each request
+	auth     []string // Proxy-Authorization header of each request
+}
+
+func newForwardProxyStub(t *testing.T, target *httptest.Server) *forwardProxyStub {
+	targetURL, err := url.Parse(target.URL)
+	require.NoError(t, err)
+	reverseProxy := httputil.NewSingleHostReverseProxy(targetURL)
+
+	stub := &forwardProxyStub{}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, r.Method+" "+r.Host)
+		stub.auth = append(stub.auth, r.Header.Get("Proxy-Authorization"))
+		stub.mu.Unlock()
+
+		if r.Method != http.MethodConnect {
+			r.Header.Del("Proxy-Authorization")
+			reverseProxy.ServeHTTP(w, r)
+			return
+		}
+
+		targetConn, err := net.Dial("tcp", targetURL.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		clientConn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			targetConn.Close()
+			return
+		}
+		clientConn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() {
+			io.Copy(targetConn, clientConn)
+			targetConn.Close()
+		}()
+		io.Copy(clientConn, targetConn)
+		clientConn.Close()
+	}))
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func (s *forwardProxyStub) received() ([]string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...), append([]string(nil), s.auth...)
+}
+
+func TestWebhookPublisher_Proxy(t *testing.T) {
+	t.Parallel()
+
+	// Requests to loopback addresses are never proxied, so the webhook URLs
+	// use hosts the proxy stub routes to the receiver.
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	publish := func(t *testing.T, opts []destwebhook.Option, config, credentials map[string]string) (*destregistry.Delivery, error) {
+		t.Helper()
+		provider, err := destwebhook.New(testutil.Registry.MetadataLoader(), opts...)
+		require.NoError(t, err)
+		credentials["secret"] = "test-secret"
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(credentials),
+		)
+		require.NoError(t, provider.Validate(context.Background(), &dest))
+		publisher, err := provider.CreatePublisher(context.Background(), &dest)
+		require.NoError(t, err)
+		event := testutil.EventFactory.Any()
+		return publisher.Publish(context.Background(), &event)
+	}
+
+	t.Run("should send requests through the global proxy", func(t *testing.T) {
+		t.Parallel()
+		receiver := httptest.NewServer(handler)
+		t.Cleanup(receiver.Close)
+		proxy := newForwardProxyStub(t, receiver)
+		proxyURL := strings.Replace(proxy.server.URL, "http://", "http://user:pass@", 1)
+
+		delivery, err := publish(t,
+			[]destwebhook.Option{destwebhook.WithProxy(&destregistry.ProxyConfig{URL: proxyURL})},
+			map[string]string{"url": "http://hooks.example.com/webhook"},
+			map[string]string{},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+
+		requests, auth := proxy.received()
+		assert.Equal(t, []string{"POST hooks.example.com"}, requests)
+		assert.Equal(t, []string{"Basic dXNlcjpwYXNz"}, auth) // user:pass
+	})
+
+	t.Run("should tunnel HTTPS requests through the destination proxy", func(t *testing.T) {
+		t.Parallel()
+		receiver := httptest.NewTLSServer(handler)
+		t.Cleanup(receiver.Close)
+		proxy := newForwardProxyStub(t, receiver)
+		globalProxy := newForwardProxyStub(t, receiver)
+		receiverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: receiver.Certificate().Raw}))
+
+		// The test certificate is valid for example.com
+		delivery, err := publish(t,
+			[]destwebhook.Option{destwebhook.WithProxy(&destregistry.ProxyConfig{URL: globalProxy.server.URL})},
+			map[string]string{"url": "https://example.com/webhook", "proxy_url": proxy.server.URL, "tls_ca_cert": receiverCA},
+			map[string]string{"proxy_username": "tenant", "proxy_password": "secret"},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+
+		requests, auth := proxy.received()
+		assert.Equal(t, []string{"CONNECT example.com:443"}, requests)
+		assert.Equal(t, []string{"Basic dGVuYW50OnNlY3JldA=="}, auth) // tenant:secret
+		globalRequests, _ := globalProxy.received()
+		assert.Empty(t, globalRequests)
+	})
+
+	t.Run("should check proxied targets against the egress policy", func(t *testing.T) {
+		t.Parallel()
+		receiver := httptest.NewServer(handler)
+		t.Cleanup(receiver.Close)
+		proxy := newForwardProxyStub(t, receiver)
+		policy, err := destregistry.NewEgressPolicy(false, []string{"127.0.0.1/32"}, nil)
+		require.NoError(t, err)
+
+		_, err = publish(t,
+			[]destwebhook.Option{
+				destwebhook.WithProxy(&destregistry.ProxyConfig{URL: proxy.server.URL}),
+				destwebhook.WithEgressPolicy(policy),
+			},
+			map[string]string{"url": "http://10.0.0.1/webhook"},
+			map[string]string{},
+		)
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Equal(t, destregistry.ErrorCodeEgressBlocked, publishErr.Data["error"])
+		requests, _ := proxy.received()
+		assert.Empty(t, requests)
+	})
+}
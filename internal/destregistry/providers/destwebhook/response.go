@@ -0,0 +1,180 @@
+package destwebhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+)
+
+// DefaultResponseBodyMaxBytes is the size response bodies are truncated to in
+// delivery records.
+const DefaultResponseBodyMaxBytes = 64 * 1024
+
+// DefaultResponseHeaders are the response headers recorded with deliveries.
+var DefaultResponseHeaders = []string{"Content-Type", "Retry-After", "X-Request-Id"}
+
+// WithResponseHeaders sets the response headers recorded with deliveries
+func WithResponseHeaders(headers []string) Option {
+	return func(w *WebhookDestination) {
+		if headers != nil {
+			w.responseHeaders = headers
+		}
+	}
+}
+
+// WithResponseBodyMaxBytes sets the size response bodies are truncated to in
+// delivery records
+func WithResponseBodyMaxBytes(maxBytes int) Option {
+	return func(w *WebhookDestination) {
+		if maxBytes > 0 {
+			w.responseBodyMaxBytes = maxBytes
+		}
+	}
+}
+
+// requestTimer records the phases of a request with httptrace. Callbacks can
+// run concurrently, e.g. when dialing several addresses.
+type requestTimer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dns          time.Duration
+	connectStart time.Time
+	connect      time.Duration
+	tlsStart     time.Time
+	handshake    time.Duration
+	ttfb         time.Duration
+	reused       bool
+}
+
+func newRequestTimer() *requestTimer {
+	return &requestTimer{start: time.Now()}
+}
+
+// trace returns the context of the request with the timer's hooks.
+func (t *requestTimer) trace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.record(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.record(func() { t.dns = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			t.record(func() {
+				if t.connectStart.IsZero() {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.record(func() {
+				if err == nil {
+					t.connect = time.Since(t.connectStart)
+				}
+			})
+		},
+		TLSHandshakeStart: func() {
+			t.record(func() { t.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			t.record(func() {
+				if err == nil {
+					t.handshake = time.Since(t.tlsStart)
+				}
+			})
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.record(func() { t.reused = info.Reused })
+		},
+		GotFirstResponseByte: func() {
+			t.record(func() { t.ttfb = time.Since(t.start) })
+		},
+	})
+}
+
+func (t *requestTimer) record(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn()
+}
+
+// timing returns the breakdown in milliseconds. Phases skipped on a reused
+// connection are omitted.
+func (t *requestTimer) timing() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timing := map[string]interface{}{
+		"total_ms":          milliseconds(time.Since(t.start)),
+		"connection_reused": t.reused,
+	}
+	for key, duration := range map[string]time.Duration{
+		"dns_ms":     t.dns,
+		"connect_ms": t.connect,
+		"tls_ms":     t.handshake,
+		"ttfb_ms":    t.ttfb,
+	} {
+		if duration > 0 {
+			timing[key] = milliseconds(duration)
+		}
+	}
+	return timing
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// readResponseBody reads up to maxBytes of the body and reports whether it
+// was truncated.
+func readResponseBody(resp *http.Response, maxBytes int) ([]byte, bool) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultResponseBodyMaxBytes
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, int64(maxBytes)+1))
+	if len(body) > maxBytes {
+		return body[:maxBytes], true
+	}
+	return body, false
+}
+
+// parseResponse records the response of the delivery: its status, selected
+// headers, body and timing. JSON bodies are stored parsed unless they were
+// truncated or are invalid.
+func (p *WebhookPublisher) parseResponse(delivery *destregistry.Delivery, resp *http.Response, body []byte, truncated bool, timer *requestTimer) {
+	response := map[string]interface{}{
+		"status": resp.StatusCode,
+		"body":   string(body),
+	}
+	if !truncated && strings.Contains(resp.Header.Get("Content-Type"), "application/json") {
+		var parsed interface{}
+		if err := json.Unmarshal(body, &parsed); err == nil {
+			response["body"] = parsed
+		}
+	}
+	if truncated {
+		response["body_truncated"] = true
+	}
+
+	headers := map[string]interface{}{}
+	for _, name := range p.responseHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			headers[http.CanonicalHeaderKey(name)] = strings.Join(values, ", ")
+		}
+	}
+	if len(headers) > 0 {
+		response["headers"] = headers
+	}
+	if timer != nil {
+		response["timing"] = timer.timing()
+	}
+	delivery.Response = response
+}
@@ -0,0 +1,97 @@
+package destwebhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPublisher_Response(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req_123")
+		w.Header().Set("X-Internal", "secret")
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":true}`))
+		case "/invalid-json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ok":`))
+		case "/large":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"` + strings.Repeat("a", 100) + `"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader(),
+		destwebhook.WithResponseHeaders([]string{"x-request-id", "Content-Type"}),
+		destwebhook.WithResponseBodyMaxBytes(32),
+	)
+	require.NoError(t, err)
+
+	publish := func(t *testing.T, path string) (*destregistry.Delivery, error) {
+		t.Helper()
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": server.URL + path}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"secret": "test-secret"}),
+		)
+		publisher, err := provider.CreatePublisher(context.Background(), &dest)
+		require.NoError(t, err)
+		event := testutil.EventFactory.Any()
+		return publisher.Publish(context.Background(), &event)
+	}
+
+	t.Run("should record the selected headers and timing", func(t *testing.T) {
+		t.Parallel()
+		delivery, err := publish(t, "/json")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"ok": true}, delivery.Response["body"])
+		assert.Equal(t, map[string]interface{}{
+			"X-Request-Id": "req_123",
+			"Content-Type": "application/json",
+		}, delivery.Response["headers"])
+		assert.NotContains(t, delivery.Response, "body_truncated")
+
+		timing, ok := delivery.Response["timing"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Greater(t, timing["total_ms"], 0.0)
+		assert.Greater(t, timing["ttfb_ms"], 0.0)
+		assert.LessOrEqual(t, timing["ttfb_ms"], timing["total_ms"])
+		assert.Contains(t, timing, "connection_reused")
+	})
+
+	t.Run("should keep invalid JSON as a string", func(t *testing.T) {
+		t.Parallel()
+		delivery, err := publish(t, "/invalid-json")
+		require.NoError(t, err)
+		assert.Equal(t, `{"ok":`, delivery.Response["body"])
+	})
+
+	t.Run("should truncate large bodies", func(t *testing.T) {
+		t.Parallel()
+		delivery, err := publish(t, "/large")
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		require.NotNil(t, delivery)
+
+		body, ok := delivery.Response["body"].(string)
+		require.True(t, ok)
+		assert.Len(t, body, 32)
+		assert.True(t, strings.HasPrefix(body, `{"message":"aaa`))
+		assert.Equal(t, true, delivery.Response["body_truncated"])
+		assert.Equal(t, body, publishErr.Data["body"])
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
 	"sort"
@@ -18,6 +19,10 @@ import (
 	"github.com/Masterminds/sprig/v3"
 )
 
+// errSigningFailed is returned when a request can't be signed. The attempt
+// fails rather than sending the request unsigned.
+var errSigningFailed = errors.New("failed to sign request")
+
 type SignaturePayload struct {
 	EventID   string
 	Topic     string
@@ -33,7 +38,7 @@ type HeaderPayload struct {
 }
 
 type SigningAlgorithm interface {
-	Sign(key string, content string, encoder SignatureEncoder) string
+	Sign(key string, content string, encoder SignatureEncoder) (string, error)
 	Verify(key string, content string, signature string, encoder SignatureEncoder) bool
 	Name() string
 }
@@ -156,14 +161,14 @@ func (h *HmacAlgo) Name() string {
 	return h.name
 }
 
-func (h *HmacAlgo) Sign(key string, content string, encoder SignatureEncoder) string {
+func (h *HmacAlgo) Sign(key string, content string, encoder SignatureEncoder) (string, error) {
 	mac := hmac.New(h.hash, []byte(key))
 	mac.Write([]byte(content))
-	return encoder.Encode(mac.Sum(nil))
+	return encoder.Encode(mac.Sum(nil)), nil
 }
 
 func (h *HmacAlgo) Verify(key string, content string, signature string, encoder SignatureEncoder) bool {
-	expectedSignature := h.Sign(key, content, encoder)
+	expectedSignature, _ := h.Sign(key, content, encoder)
 	return hmac.Equal([]byte(signature), []byte(expectedSignature))
 }
 
@@ -217,9 +222,11 @@ func NewSignatureManager(secrets []WebhookSecret, opts ...SignatureManagerOption
 	return sm
 }
 
-func (sm *SignatureManager) GenerateSignatures(content SignaturePayload) []string {
+// GenerateSignatures signs the content with each valid secret. It fails if
+// any of them can't sign, rather than leaving the request partially signed.
+func (sm *SignatureManager) GenerateSignatures(content SignaturePayload) ([]string, error) {
 	if len(sm.secrets) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Sort secrets by creation date, newest first
@@ -236,7 +243,11 @@ func (sm *SignatureManager) GenerateSignatures(content SignaturePayload) []strin
 	// Check if latest secret is valid
 	latestSecret := sortedSecrets[0]
 	if latestSecret.InvalidAt == nil || now.Before(*latestSecret.InvalidAt) {
-		signatures = append(signatures, sm.algorithm.Sign(latestSecret.Key, formattedContent, sm.encoder))
+		signature, err := sm.sign(latestSecret, formattedContent)
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, signature)
 	}
 
 	// Add signatures for valid non-latest secrets
@@ -252,23 +263,37 @@ func (sm *SignatureManager) GenerateSignatures(content SignaturePayload) []strin
 				continue
 			}
 		}
-		signatures = append(signatures, sm.algorithm.Sign(secret.Key, formattedContent, sm.encoder))
+		signature, err := sm.sign(secret, formattedContent)
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, signature)
 	}
 
-	return signatures
+	return signatures, nil
 }
 
-func (sm *SignatureManager) GenerateSignatureHeader(content SignaturePayload) string {
-	signatures := sm.GenerateSignatures(content)
-	if len(signatures) == 0 {
-		return ""
+// sign signs the content with the secret's own algorithm, if any, or the
+// manager's algorithm.
+func (sm *SignatureManager) sign(secret WebhookSecret, content string) (string, error) {
+	algorithm := sm.algorithm
+	if secret.Algorithm != nil {
+		algorithm = secret.Algorithm
+	}
+	return algorithm.Sign(secret.Key, content, sm.encoder)
+}
+
+func (sm *SignatureManager) GenerateSignatureHeader(content SignaturePayload) (string, error) {
+	signatures, err := sm.GenerateSignatures(content)
+	if err != nil || len(signatures) == 0 {
+		return "", err
 	}
 	return sm.headerFormatter.Format(HeaderPayload{
 		EventID:    content.EventID,
 		Topic:      content.Topic,
 		Timestamp:  content.Timestamp,
 		Signatures: signatures,
-	})
+	}), nil
 }
 
 func (sm *SignatureManager) VerifySignature(signature, key string, content SignaturePayload) bool {
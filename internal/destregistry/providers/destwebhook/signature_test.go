@@ -7,6 +7,7 @@ import (
 
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHmacAlgorithms(t *testing.T) {
@@ -37,7 +38,8 @@ func TestHmacAlgorithms(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			signature := tt.algo.Sign(key, content, destwebhook.HexEncoder{})
+			signature, err := tt.algo.Sign(key, content, destwebhook.HexEncoder{})
+			require.NoError(t, err)
 			assert.Equal(t, tt.expected, signature)
 
 			// Basic verification test
@@ -190,16 +192,18 @@ func TestSignatureEncoders(t *testing.T) {
 func TestSignatureManager(t *testing.T) {
 	t.Run("no secrets", func(t *testing.T) {
 		manager := destwebhook.NewSignatureManager(nil)
-		signatures := manager.GenerateSignatures(destwebhook.SignaturePayload{
+		signatures, err := manager.GenerateSignatures(destwebhook.SignaturePayload{
 			Timestamp: time.Now(),
 			Body:      "test",
 		})
+		require.NoError(t, err)
 		assert.Nil(t, signatures)
 
-		header := manager.GenerateSignatureHeader(destwebhook.SignaturePayload{
+		header, err := manager.GenerateSignatureHeader(destwebhook.SignaturePayload{
 			Timestamp: time.Now(),
 			Body:      "test",
 		})
+		require.NoError(t, err)
 		assert.Empty(t, header)
 	})
 
@@ -218,7 +222,8 @@ func TestSignatureManager(t *testing.T) {
 		}
 
 		manager := destwebhook.NewSignatureManager([]destwebhook.WebhookSecret{oldSecret})
-		signatures := manager.GenerateSignatures(payload)
+		signatures, err := manager.GenerateSignatures(payload)
+		require.NoError(t, err)
 		assert.Len(t, signatures, 1, "should generate signature for single secret regardless of age")
 
 		// Verify signature is valid with correct key
@@ -246,7 +251,8 @@ func TestSignatureManager(t *testing.T) {
 		}
 
 		manager := destwebhook.NewSignatureManager(secrets)
-		signatures := manager.GenerateSignatures(payload)
+		signatures, err := manager.GenerateSignatures(payload)
+		require.NoError(t, err)
 		assert.Len(t, signatures, 1, "should only use latest secret")
 
 		// Verify signature is valid with latest key
@@ -277,12 +283,13 @@ func TestSignatureManager(t *testing.T) {
 		timestamp := time.Unix(1234567890, 0)
 		body := `{"hello":"world"}`
 
-		signatures := manager.GenerateSignatures(destwebhook.SignaturePayload{
+		signatures, err := manager.GenerateSignatures(destwebhook.SignaturePayload{
 			Timestamp: timestamp,
 			Body:      body,
 			EventID:   "test-id",
 			Topic:     "test-topic",
 		})
+		require.NoError(t, err)
 		assert.Len(t, signatures, 3, "should include latest + 2 recent secrets")
 
 		// Verify each signature is valid with its corresponding key
@@ -312,12 +319,13 @@ func TestSignatureManager(t *testing.T) {
 			},
 		), "signature should be invalid with expired key")
 
-		header := manager.GenerateSignatureHeader(destwebhook.SignaturePayload{
+		header, err := manager.GenerateSignatureHeader(destwebhook.SignaturePayload{
 			Timestamp: timestamp,
 			Body:      body,
 			EventID:   "test-id",
 			Topic:     "test-topic",
 		})
+		require.NoError(t, err)
 		assert.Contains(t, header, "t=1234567890")
 		assert.Equal(t, 3, strings.Count(header, ","), "should have correct number of commas in header")
 	})
@@ -344,7 +352,8 @@ func TestSignatureManager(t *testing.T) {
 			Topic:     "test-topic",
 		}
 
-		signatures := manager.GenerateSignatures(payload)
+		signatures, err := manager.GenerateSignatures(payload)
+		require.NoError(t, err)
 		assert.Len(t, signatures, 3, "should include latest + valid secrets")
 
 		// Verify each signature is valid with its corresponding key
@@ -377,12 +386,13 @@ func TestSignatureManager(t *testing.T) {
 			}
 
 			manager := destwebhook.NewSignatureManager(secrets)
-			signatures := manager.GenerateSignatures(destwebhook.SignaturePayload{
+			signatures, err := manager.GenerateSignatures(destwebhook.SignaturePayload{
 				Timestamp: time.Unix(1234567890, 0),
 				Body:      "test",
 				EventID:   "test-id",
 				Topic:     "test-topic",
 			})
+			require.NoError(t, err)
 			assert.Empty(t, signatures, "should return empty signatures when latest is invalid and no other valid secrets")
 		})
 
@@ -394,12 +404,13 @@ func TestSignatureManager(t *testing.T) {
 			}
 
 			manager := destwebhook.NewSignatureManager(secrets)
-			signatures := manager.GenerateSignatures(destwebhook.SignaturePayload{
+			signatures, err := manager.GenerateSignatures(destwebhook.SignaturePayload{
 				Timestamp: time.Unix(1234567890, 0),
 				Body:      "test",
 				EventID:   "test-id",
 				Topic:     "test-topic",
 			})
+			require.NoError(t, err)
 			assert.Len(t, signatures, 1, "should only include valid non-latest secrets")
 
 			// Verify signature is valid with the recent key
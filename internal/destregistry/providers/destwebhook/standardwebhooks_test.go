@@ -0,0 +1,174 @@
+package destwebhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verifyStandardWebhook verifies the request the way the Standard Webhooks
+// libraries do and returns the number of valid signatures.
+func verifyStandardWebhook(t *testing.T, req *http.Request, secret string) int {
+	t.Helper()
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, "whsec_"))
+	require.NoError(t, err)
+
+	content := fmt.Sprintf("%s.%s.%s", req.Header.Get("webhook-id"), req.Header.Get("webhook-timestamp"), body)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(content))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	valid := 0
+	for _, signature := range strings.Split(req.Header.Get("webhook-signature"), " ") {
+		version, sig, found := strings.Cut(signature, ",")
+		require.True(t, found, "signature should be versioned")
+		assert.Equal(t, "v1", version)
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			valid++
+		}
+	}
+	return valid
+}
+
+func TestStandardWebhooks(t *testing.T) {
+	t.Parallel()
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader(), destwebhook.WithMode(destwebhook.ModeStandard))
+	require.NoError(t, err)
+
+	t.Run("should generate whsec_ secrets", func(t *testing.T) {
+		t.Parallel()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "http://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{}),
+		)
+		err := provider.Preprocess(&destination, nil, &destregistry.PreprocessDestinationOpts{})
+		require.NoError(t, err)
+
+		secret := destination.Credentials["secret"]
+		require.True(t, strings.HasPrefix(secret, "whsec_"))
+		key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, "whsec_"))
+		require.NoError(t, err)
+		assert.Len(t, key, 32)
+	})
+
+	t.Run("should send standard headers", func(t *testing.T) {
+		t.Parallel()
+		secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "http://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"secret": secret}),
+		)
+		publisher, err := provider.CreatePublisher(context.Background(), &destination)
+		require.NoError(t, err)
+
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTopic("order.created"),
+			testutil.EventFactory.WithData(map[string]interface{}{"hello": "world"}),
+		)
+		req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+		require.NoError(t, err)
+
+		assert.Equal(t, event.ID, req.Header.Get("webhook-id"))
+		timestamp := req.Header.Get("webhook-timestamp")
+		assert.Equal(t, fmt.Sprintf("%d", time.Now().Unix()), timestamp)
+		assert.Empty(t, req.Header.Get("x-outpost-signature"))
+		assert.Empty(t, req.Header.Get("x-outpost-event-id"))
+		assert.Equal(t, "order.created", req.Header.Get("x-outpost-topic"))
+		assert.Equal(t, 1, verifyStandardWebhook(t, req, secret))
+	})
+
+	t.Run("should sign with the previous secret during rotation", func(t *testing.T) {
+		t.Parallel()
+		previousSecret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+		secret := "whsec_C2FVsBQIhrscChlQIMV+b5sSYspob7oD"
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "http://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"secret":                     secret,
+				"previous_secret":            previousSecret,
+				"previous_secret_invalid_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+			}),
+		)
+		publisher, err := provider.CreatePublisher(context.Background(), &destination)
+		require.NoError(t, err)
+
+		event := testutil.EventFactory.Any()
+		req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Len(t, strings.Split(req.Header.Get("webhook-signature"), " "), 2)
+
+		req, err = publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, 1, verifyStandardWebhook(t, req, secret))
+		req, err = publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, 1, verifyStandardWebhook(t, req, previousSecret))
+	})
+
+	t.Run("should sign batches with a stable id", func(t *testing.T) {
+		t.Parallel()
+		secret := "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "http://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"secret": secret}),
+		)
+		publisher, err := provider.CreatePublisher(context.Background(), &destination)
+		require.NoError(t, err)
+
+		event1 := testutil.EventFactory.Any()
+		event2 := testutil.EventFactory.Any()
+		events := []*models.Event{&event1, &event2}
+		req, err := publisher.(*destwebhook.WebhookPublisher).FormatBatch(context.Background(), events)
+		require.NoError(t, err)
+		retryReq, err := publisher.(*destwebhook.WebhookPublisher).FormatBatch(context.Background(), events)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(req.Header.Get("webhook-id"), "batch_"))
+		assert.Equal(t, req.Header.Get("webhook-id"), retryReq.Header.Get("webhook-id"))
+		assert.Equal(t, 1, verifyStandardWebhook(t, req, secret))
+	})
+
+	t.Run("should reject malformed secrets", func(t *testing.T) {
+		t.Parallel()
+		secrets := map[string]string{
+			"not base64": "whsec_not base64!",
+			"no prefix":  "MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw",
+			"short key":  "whsec_" + base64.StdEncoding.EncodeToString([]byte("too-short")),
+			"empty key":  "whsec_",
+		}
+		for name, secret := range secrets {
+			destination := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("webhook"),
+				testutil.DestinationFactory.WithConfig(map[string]string{"url": "http://example.com"}),
+				testutil.DestinationFactory.WithCredentials(map[string]string{"secret": secret}),
+			)
+			err := provider.Validate(context.Background(), &destination)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr, name)
+			assert.Equal(t, "credentials.secret", validationErr.Errors[0].Field, name)
+			assert.Equal(t, "pattern", validationErr.Errors[0].Type, name)
+		}
+	})
+}
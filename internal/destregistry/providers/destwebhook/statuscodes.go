@@ -0,0 +1,125 @@
+package destwebhook
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+var ErrInvalidStatusCodes = errors.New("invalid status codes")
+
+// StatusCodes is a set of HTTP status codes, written as a comma-separated list
+// of codes and ranges, e.g. "200-299,304".
+type StatusCodes []statusCodeRange
+
+type statusCodeRange struct {
+	low  int
+	high int
+}
+
+// ParseStatusCodes parses a comma-separated list of status codes and ranges.
+// An empty value is an empty set.
+func ParseStatusCodes(value string) (StatusCodes, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var codes StatusCodes
+	for _, part := range strings.Split(value, ",") {
+		lowStr, highStr, isRange := strings.Cut(part, "-")
+		low, err := parseStatusCode(lowStr)
+		if err != nil {
+			return nil, err
+		}
+		high := low
+		if isRange {
+			if high, err = parseStatusCode(highStr); err != nil {
+				return nil, err
+			}
+			if high < low {
+				return nil, ErrInvalidStatusCodes
+			}
+		}
+		codes = append(codes, statusCodeRange{low: low, high: high})
+	}
+	return codes, nil
+}
+
+func parseStatusCode(value string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || code < 100 || code > 599 {
+		return 0, ErrInvalidStatusCodes
+	}
+	return code, nil
+}
+
+// Contains reports whether the code is in the set.
+func (s StatusCodes) Contains(code int) bool {
+	for _, r := range s {
+		if code >= r.low && code <= r.high {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookResponseConfig classifies the responses of the receiver.
+type WebhookResponseConfig struct {
+	// SuccessStatusCodes are the statuses of successful deliveries. Statuses
+	// below 400 are successful if it's empty.
+	SuccessStatusCodes StatusCodes
+	// NonRetryableStatusCodes are failures that aren't retried.
+	NonRetryableStatusCodes StatusCodes
+	// DisableStatusCodes are failures that disable the destination, such as
+	// 410 Gone. They aren't retried either.
+	DisableStatusCodes StatusCodes
+}
+
+func (c *WebhookResponseConfig) isSuccess(code int) bool {
+	if c == nil || len(c.SuccessStatusCodes) == 0 {
+		return code < 400
+	}
+	return c.SuccessStatusCodes.Contains(code)
+}
+
+func (c *WebhookResponseConfig) isNonRetryable(code int) bool {
+	return c != nil && c.NonRetryableStatusCodes.Contains(code)
+}
+
+func (c *WebhookResponseConfig) disablesDestination(code int) bool {
+	return c != nil && c.DisableStatusCodes.Contains(code)
+}
+
+// resolveResponseConfig parses the status codes of the destination, which
+// default to the provider's.
+func (d *WebhookDestination) resolveResponseConfig(destination *models.Destination) (*WebhookResponseConfig, error) {
+	resolve := func(key, fallback string) (StatusCodes, error) {
+		value := destination.Config[key]
+		if value == "" {
+			value = fallback
+		}
+		codes, err := ParseStatusCodes(value)
+		if err != nil {
+			return nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+				Field: "config." + key,
+				Type:  "pattern",
+			}})
+		}
+		return codes, nil
+	}
+
+	var config WebhookResponseConfig
+	var err error
+	if config.SuccessStatusCodes, err = resolve("success_status_codes", d.successStatusCodes); err != nil {
+		return nil, err
+	}
+	if config.NonRetryableStatusCodes, err = resolve("non_retryable_status_codes", d.nonRetryableStatusCodes); err != nil {
+		return nil, err
+	}
+	if config.DisableStatusCodes, err = resolve("disable_status_codes", d.disableStatusCodes); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -0,0 +1,138 @@
+package destwebhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	codes, err := destwebhook.ParseStatusCodes(" 200-299, 409 ,410- 412")
+	require.NoError(t, err)
+	for _, code := range []int{200, 250, 299, 409, 410, 412} {
+		assert.True(t, codes.Contains(code), code)
+	}
+	for _, code := range []int{199, 300, 408, 413} {
+		assert.False(t, codes.Contains(code), code)
+	}
+
+	codes, err = destwebhook.ParseStatusCodes("")
+	require.NoError(t, err)
+	assert.Empty(t, codes)
+
+	for _, value := range []string{"4xx", "200,", "99", "600", "299-200", "200-"} {
+		_, err := destwebhook.ParseStatusCodes(value)
+		assert.ErrorIs(t, err, destwebhook.ErrInvalidStatusCodes, value)
+	}
+}
+
+func TestWebhookPublisher_ResponseClassification(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader(),
+		destwebhook.WithNonRetryableStatusCodes("400,422"),
+		destwebhook.WithDisableStatusCodes("410"),
+	)
+	require.NoError(t, err)
+
+	publish := func(t *testing.T, status int, config map[string]string) (*destregistry.Delivery, error) {
+		t.Helper()
+		config["url"] = server.URL + "/" + strconv.Itoa(status)
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"secret": "test-secret"}),
+		)
+		require.NoError(t, provider.Validate(context.Background(), &dest))
+		publisher, err := provider.CreatePublisher(context.Background(), &dest)
+		require.NoError(t, err)
+		event := testutil.EventFactory.Any()
+		return publisher.Publish(context.Background(), &event)
+	}
+
+	t.Run("should fail on statuses from 400 by default", func(t *testing.T) {
+		t.Parallel()
+		_, err := publish(t, http.StatusConflict, map[string]string{})
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.False(t, publishErr.NonRetryable)
+		assert.False(t, publishErr.DisableDestination)
+	})
+
+	t.Run("should succeed on the destination's success codes", func(t *testing.T) {
+		t.Parallel()
+		delivery, err := publish(t, http.StatusConflict, map[string]string{"success_status_codes": "200-299,409"})
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+
+		_, err = publish(t, http.StatusAccepted, map[string]string{"success_status_codes": "200"})
+		require.Error(t, err)
+	})
+
+	t.Run("should not retry the non-retryable codes", func(t *testing.T) {
+		t.Parallel()
+		_, err := publish(t, http.StatusUnprocessableEntity, map[string]string{})
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.True(t, publishErr.NonRetryable)
+		assert.False(t, publishErr.DisableDestination)
+
+		// The destination's codes replace the provider's
+		_, err = publish(t, http.StatusUnprocessableEntity, map[string]string{"non_retryable_status_codes": "400"})
+		require.ErrorAs(t, err, &publishErr)
+		assert.False(t, publishErr.NonRetryable)
+	})
+
+	t.Run("should disable the destination on the disable codes", func(t *testing.T) {
+		t.Parallel()
+		_, err := publish(t, http.StatusGone, map[string]string{})
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.True(t, publishErr.DisableDestination)
+		assert.Equal(t, http.StatusGone, publishErr.Data["status"])
+	})
+}
+
+func TestWebhookDestination_ValidateStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+	require.NoError(t, err)
+
+	for _, key := range []string{"success_status_codes", "non_retryable_status_codes", "disable_status_codes"} {
+		t.Run(key, func(t *testing.T) {
+			t.Parallel()
+			dest := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("webhook"),
+				testutil.DestinationFactory.WithConfig(map[string]string{
+					"url": "https://example.com",
+					key:   "299-200",
+				}),
+				testutil.DestinationFactory.WithCredentials(map[string]string{"secret": "test-secret"}),
+			)
+
+			err := provider.Validate(context.Background(), &dest)
+			var validationErr *destregistry.ErrDestinationValidation
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "config."+key, validationErr.Errors[0].Field)
+			assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+		})
+	}
+}
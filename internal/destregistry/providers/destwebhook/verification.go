@@ -0,0 +1,183 @@
+package destwebhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+const (
+	// VerificationMethodPost sends the challenge in a signed POST request.
+	VerificationMethodPost = "post"
+	// VerificationMethodGet sends the challenge as the `challenge` query
+	// parameter of a GET request.
+	VerificationMethodGet = "get"
+)
+
+// verificationBodyMaxBytes limits the response read when checking the echoed
+// challenge.
+const verificationBodyMaxBytes = 4096
+
+var _ destregistry.VerifiableProvider = (*WebhookDestination)(nil)
+
+// WithVerification requires new destinations, and destinations whose URL
+// changes, to echo a challenge sent with the method before receiving events.
+func WithVerification(method string) Option {
+	return func(w *WebhookDestination) {
+		w.verificationMethod = method
+	}
+}
+
+// RequiresVerification reports whether a verification method is configured.
+func (d *WebhookDestination) RequiresVerification() bool {
+	return d.verificationMethod != ""
+}
+
+// VerificationRequest is the body of POST verification requests.
+type VerificationRequest struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+// preprocessVerification marks the destination as pending verification when
+// it's created or its URL changes.
+func (d *WebhookDestination) preprocessVerification(newDestination *models.Destination, originalDestination *models.Destination) {
+	if d.verificationMethod == "" {
+		return
+	}
+	if originalDestination != nil && originalDestination.Config["url"] == newDestination.Config["url"] {
+		return
+	}
+	newDestination.VerificationStatus = models.DestinationVerificationPending
+	newDestination.VerifiedAt = nil
+}
+
+// Verify sends a challenge to the destination's URL, which must respond with
+// a 2xx status and echo it back, either as the body or as the `challenge`
+// field of a JSON body. Destinations pass if verification isn't required.
+func (d *WebhookDestination) Verify(ctx context.Context, destination *models.Destination) error {
+	if d.verificationMethod == "" {
+		return nil
+	}
+	publisher, err := d.CreatePublisher(ctx, destination)
+	if err != nil {
+		return err
+	}
+	defer publisher.Close()
+
+	challenge, err := generateChallenge()
+	if err != nil {
+		return err
+	}
+	return publisher.(*WebhookPublisher).verify(ctx, d.verificationMethod, challenge)
+}
+
+func (p *WebhookPublisher) verify(ctx context.Context, method string, challenge string) error {
+	req, err := p.formatVerification(ctx, method, challenge)
+	if err != nil {
+		return err
+	}
+	if p.auth != nil {
+		if err := p.auth.authenticate(ctx, req); err != nil {
+			return fmt.Errorf("%w: %w", destregistry.ErrVerificationFailed, err)
+		}
+	}
+
+	resp, err := p.do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", destregistry.ErrVerificationFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: endpoint responded with status %d", destregistry.ErrVerificationFailed, resp.StatusCode)
+	}
+	body, _ := readResponseBody(resp, verificationBodyMaxBytes)
+	if !echoesChallenge(body, challenge) {
+		return fmt.Errorf("%w: endpoint didn't echo the challenge", destregistry.ErrVerificationFailed)
+	}
+	return nil
+}
+
+func (p *WebhookPublisher) formatVerification(ctx context.Context, method string, challenge string) (*http.Request, error) {
+	if method == VerificationMethodGet {
+		verificationURL, err := url.Parse(p.url)
+		if err != nil {
+			return nil, err
+		}
+		query := verificationURL.Query()
+		query.Set("challenge", challenge)
+		verificationURL.RawQuery = query.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, verificationURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		p.customHeaders.apply(req, HeaderTemplatePayload{})
+		return req, nil
+	}
+
+	now := time.Now()
+	rawBody, err := json.Marshal(VerificationRequest{
+		Type:      "verification",
+		Challenge: challenge,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewBuffer(rawBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	p.customHeaders.apply(req, HeaderTemplatePayload{})
+	if p.mode == ModeStandard {
+		if err := p.setStandardHeaders(req, "verify_"+challenge, now, rawBody); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}
+	if !p.disableTimestampHeader {
+		req.Header.Set(p.headerPrefix+"timestamp", fmt.Sprintf("%d", now.UnixMilli()))
+	}
+	if !p.disableSignatureHeader {
+		signatureHeader, err := p.sm.GenerateSignatureHeader(SignaturePayload{
+			Timestamp: now,
+			Body:      string(rawBody),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errSigningFailed, err)
+		}
+		if signatureHeader != "" {
+			req.Header.Set(p.headerPrefix+"signature", signatureHeader)
+		}
+	}
+	return req, nil
+}
+
+func echoesChallenge(body []byte, challenge string) bool {
+	if strings.TrimSpace(string(body)) == challenge {
+		return true
+	}
+	var response struct {
+		Challenge string `json:"challenge"`
+	}
+	return json.Unmarshal(body, &response) == nil && response.Challenge == challenge
+}
+
+func generateChallenge() (string, error) {
+	randomBytes := make([]byte, 24)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	return hex.EncodeToString(randomBytes), nil
+}
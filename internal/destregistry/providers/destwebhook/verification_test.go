@@ -0,0 +1,136 @@
+package destwebhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDestination_PreprocessVerification(t *testing.T) {
+	t.Parallel()
+
+	newDestination := func() models.Destination {
+		return testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{}),
+		)
+	}
+
+	t.Run("should not require verification by default", func(t *testing.T) {
+		t.Parallel()
+		provider, err := destwebhook.New(testutil.Registry.MetadataLoader())
+		require.NoError(t, err)
+
+		dest := newDestination()
+		require.NoError(t, provider.Preprocess(&dest, nil, &destregistry.PreprocessDestinationOpts{}))
+		assert.Empty(t, dest.VerificationStatus)
+		assert.False(t, provider.RequiresVerification())
+	})
+
+	provider, err := destwebhook.New(testutil.Registry.MetadataLoader(), destwebhook.WithVerification(destwebhook.VerificationMethodPost))
+	require.NoError(t, err)
+
+	t.Run("should mark new destinations as pending", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, provider.RequiresVerification())
+		dest := newDestination()
+		require.NoError(t, provider.Preprocess(&dest, nil, &destregistry.PreprocessDestinationOpts{}))
+		assert.True(t, dest.PendingVerification())
+	})
+
+	t.Run("should keep the status if the URL doesn't change", func(t *testing.T) {
+		t.Parallel()
+		original := newDestination()
+		original.VerificationStatus = models.DestinationVerificationVerified
+		updated := original
+		updated.Topics = []string{"user.created"}
+		require.NoError(t, provider.Preprocess(&updated, &original, &destregistry.PreprocessDestinationOpts{}))
+		assert.Equal(t, models.DestinationVerificationVerified, updated.VerificationStatus)
+	})
+
+	t.Run("should mark as pending when the URL changes", func(t *testing.T) {
+		t.Parallel()
+		original := newDestination()
+		original.VerificationStatus = models.DestinationVerificationVerified
+		updated := original
+		updated.Config = map[string]string{"url": "https://example.org"}
+		require.NoError(t, provider.Preprocess(&updated, &original, &destregistry.PreprocessDestinationOpts{}))
+		assert.True(t, updated.PendingVerification())
+	})
+}
+
+func TestWebhookDestination_Verify(t *testing.T) {
+	t.Parallel()
+
+	var lastRequest *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastRequest = r
+		var challenge string
+		if r.Method == http.MethodGet {
+			challenge = r.URL.Query().Get("challenge")
+		} else {
+			var body destwebhook.VerificationRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			challenge = body.Challenge
+		}
+		switch r.URL.Path {
+		case "/plain":
+			w.Write([]byte(challenge))
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]string{"challenge": challenge})
+		case "/wrong":
+			w.Write([]byte("ok"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	verify := func(t *testing.T, method, path string) error {
+		t.Helper()
+		provider, err := destwebhook.New(testutil.Registry.MetadataLoader(), destwebhook.WithVerification(method))
+		require.NoError(t, err)
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": server.URL + path}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"secret": "test-secret"}),
+		)
+		return provider.Verify(context.Background(), &dest)
+	}
+
+	t.Run("should pass a signed POST challenge echoed as the body", func(t *testing.T) {
+		require.NoError(t, verify(t, destwebhook.VerificationMethodPost, "/plain"))
+		assert.Equal(t, http.MethodPost, lastRequest.Method)
+		assert.NotEmpty(t, lastRequest.Header.Get("x-outpost-signature"))
+	})
+
+	t.Run("should pass a GET challenge echoed as JSON", func(t *testing.T) {
+		require.NoError(t, verify(t, destwebhook.VerificationMethodGet, "/json"))
+		assert.Equal(t, http.MethodGet, lastRequest.Method)
+	})
+
+	t.Run("should fail if the challenge isn't echoed", func(t *testing.T) {
+		err := verify(t, destwebhook.VerificationMethodPost, "/wrong")
+		assert.ErrorIs(t, err, destregistry.ErrVerificationFailed)
+	})
+
+	t.Run("should fail on error statuses", func(t *testing.T) {
+		err := verify(t, destwebhook.VerificationMethodGet, "/missing")
+		assert.ErrorIs(t, err, destregistry.ErrVerificationFailed)
+	})
+
+	t.Run("should pass without verification", func(t *testing.T) {
+		assert.NoError(t, verify(t, "", "/missing"))
+	})
+}
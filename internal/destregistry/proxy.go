@@ -0,0 +1,94 @@
+package destregistry
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"golang.org/x/net/http/httpproxy"
+)
+
+var ErrInvalidProxyURL = errors.New("invalid proxy URL")
+
+// ProxyConfig routes HTTP requests through a forward proxy. HTTPS requests
+// are tunneled with CONNECT.
+type ProxyConfig struct {
+	// URL of the proxy, e.g. http://proxy.internal:3128. It can include the
+	// credentials as user info.
+	URL      string
+	Username string
+	Password string
+	// NoProxy is a comma-separated list of hosts, domains and CIDRs reached
+	// without the proxy, in the NO_PROXY environment variable format.
+	NoProxy string
+}
+
+func (c *ProxyConfig) IsZero() bool {
+	return c == nil || c.URL == ""
+}
+
+// Build validates the settings and returns the Proxy function of the
+// destination's HTTP transport.
+func (c *ProxyConfig) Build() (func(*http.Request) (*url.URL, error), error) {
+	proxyURL, err := parseProxyURL(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	if c.Username != "" || c.Password != "" {
+		proxyURL.User = url.UserPassword(c.Username, c.Password)
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    c.NoProxy,
+	}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
+
+func parseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || proxyURL.Host == "" {
+		return nil, ErrInvalidProxyURL
+	}
+	return proxyURL, nil
+}
+
+// ResolveProxyConfig returns the proxy of a destination. A destination with a
+// `proxy_url` config uses it with the `proxy_username` and `proxy_password`
+// credentials instead of the global proxy. The global NoProxy list applies to
+// both.
+func ResolveProxyConfig(global *ProxyConfig, destination *models.Destination) (*ProxyConfig, error) {
+	rawURL := destination.Config["proxy_url"]
+	if rawURL == "" {
+		return global, nil
+	}
+
+	// Credentials in the URL would be displayed with the config
+	proxyURL, err := parseProxyURL(rawURL)
+	if err != nil || proxyURL.User != nil {
+		return nil, NewErrDestinationValidation([]ValidationErrorDetail{{
+			Field: "config.proxy_url",
+			Type:  "pattern",
+		}})
+	}
+	if destination.Credentials["proxy_password"] != "" && destination.Credentials["proxy_username"] == "" {
+		return nil, NewErrDestinationValidation([]ValidationErrorDetail{{
+			Field: "credentials.proxy_username",
+			Type:  "required",
+		}})
+	}
+
+	config := &ProxyConfig{
+		URL:      rawURL,
+		Username: destination.Credentials["proxy_username"],
+		Password: destination.Credentials["proxy_password"],
+	}
+	if global != nil {
+		config.NoProxy = global.NoProxy
+	}
+	return config, nil
+}
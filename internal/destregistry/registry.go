@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"sort"
 	"strconv"
 	"time"
 
@@ -73,6 +74,7 @@ type registry struct {
 	providers      map[string]Provider
 	providerList   []string
 	publishers     *lru.Cache[string, Publisher]
+	batcher        *batcher
 	config         Config
 }
 
@@ -111,6 +113,7 @@ func NewRegistry(cfg *Config, logger *logging.Logger) Registry {
 		metadata:       make(map[string]*metadata.ProviderMetadata),
 		providers:      make(map[string]Provider),
 		publishers:     cache,
+		batcher:        newBatcher(),
 		config:         *cfg,
 	}
 }
@@ -120,6 +123,16 @@ func (r *registry) ValidateDestination(ctx context.Context, destination *models.
 	if err != nil {
 		return err
 	}
+	if !destination.Batch.IsZero() {
+		if batchProvider, ok := provider.(BatchProvider); !ok || !batchProvider.SupportsBatch() {
+			return NewErrDestinationValidation([]ValidationErrorDetail{
+				{
+					Field: "batch",
+					Type:  "unsupported",
+				},
+			})
+		}
+	}
 	if err := provider.Validate(ctx, destination); err != nil {
 		var validateErr *ErrDestinationValidation
 		if errors.As(err, &validateErr) {
@@ -147,11 +160,42 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 		EventID:       event.ID,
 	}
 
-	// Create a new context with timeout
-	timeoutCtx, cancel := context.WithTimeout(ctx, r.config.DeliveryTimeout)
-	defer cancel()
+	// Apply the destination transformation, if any, so that every provider
+	// publishes the reshaped event.
+	if destination.Transformation != nil {
+		transformedEvent, err := destination.Transformation.Apply(event)
+		if err != nil {
+			delivery.Time = time.Now()
+			delivery.Status = models.DeliveryStatusFailed
+			delivery.Code = "ERR"
+			delivery.ResponseData = map[string]interface{}{
+				"error": err.Error(),
+			}
+			// The transformation fails the same way on every attempt, so
+			// retrying it would only delay the failure.
+			return delivery, &ErrDestinationPublishAttempt{
+				Err:      fmt.Errorf("%w: %w", ErrTransformationFailed, err),
+				Provider: destination.Type,
+				Data: map[string]interface{}{
+					"error":   "transformation_failed",
+					"message": err.Error(),
+				},
+				NonRetryable: true,
+			}
+		}
+		event = transformedEvent
+	}
 
-	deliveryData, err := publisher.Publish(timeoutCtx, event)
+	deliveryTimeout := r.deliveryTimeout(destination)
+	var deliveryData *Delivery
+	if batchPublisher, ok := publisher.(BatchPublisher); ok && !destination.Batch.IsZero() {
+		deliveryData, err = r.batcher.publish(ctx, MakePublisherKey(destination), batchPublisher, destination.Batch, event, deliveryTimeout)
+	} else {
+		// Create a new context with timeout
+		timeoutCtx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+		defer cancel()
+		deliveryData, err = publisher.Publish(timeoutCtx, event)
+	}
 	if err != nil {
 		if deliveryData != nil {
 			delivery.Time = time.Now()
@@ -163,6 +207,9 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 		}
 		var publishErr *ErrDestinationPublishAttempt
 		if errors.As(err, &publishErr) {
+			if publishErr.RetryAfter > 0 && delivery != nil {
+				delivery.ResponseData = withRetryAfter(delivery.ResponseData, publishErr.RetryAfter)
+			}
 			// Check if the wrapped error is a timeout
 			if errors.Is(publishErr.Err, context.DeadlineExceeded) {
 				return delivery, &ErrDestinationPublishAttempt{
@@ -170,7 +217,7 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 					Provider: destination.Type,
 					Data: map[string]interface{}{
 						"error":   "timeout",
-						"timeout": r.config.DeliveryTimeout.String(),
+						"timeout": deliveryTimeout.String(),
 					},
 				}
 			}
@@ -183,7 +230,7 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 				Provider: destination.Type,
 				Data: map[string]interface{}{
 					"error":   "timeout",
-					"timeout": r.config.DeliveryTimeout.String(),
+					"timeout": deliveryTimeout.String(),
 				},
 			}
 		}
@@ -218,6 +265,26 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 	return delivery, nil
 }
 
+// deliveryTimeout returns the destination's delivery timeout if its retry
+// policy sets one, otherwise the global delivery timeout.
+func (r *registry) deliveryTimeout(destination *models.Destination) time.Duration {
+	if destination.RetryPolicy != nil && destination.RetryPolicy.DeliveryTimeout > 0 {
+		return time.Duration(destination.RetryPolicy.DeliveryTimeout)
+	}
+	return r.config.DeliveryTimeout
+}
+
+// withRetryAfter returns a copy of the response data recording the retry hint.
+// The response data may be shared by the deliveries of a batch, so it's not modified in place.
+func withRetryAfter(responseData map[string]interface{}, retryAfter time.Duration) map[string]interface{} {
+	result := make(map[string]interface{}, len(responseData)+1)
+	for k, v := range responseData {
+		result[k] = v
+	}
+	result["retry_after"] = retryAfter.String()
+	return result
+}
+
 func (r *registry) RegisterProvider(destinationType string, provider Provider) error {
 	r.providers[destinationType] = provider
 	r.metadata[destinationType] = provider.Metadata()
@@ -233,13 +300,27 @@ func (r *registry) ResolveProvider(destination *models.Destination) (Provider, e
 	return provider, nil
 }
 
-// MakePublisherKey creates a unique key for a destination that includes type and config
+// MakePublisherKey creates a unique key for a destination that includes type,
+// config and credentials. The key is stable for an unchanged destination so
+// its publisher, and the connection it holds, is reused from the cache.
 func MakePublisherKey(dest *models.Destination) string {
 	h := fnv.New64a()
-	for k, v := range dest.Config {
-		h.Write([]byte(k))
-		h.Write([]byte(v))
+	writeMap := func(m map[string]string) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			h.Write([]byte(k))
+			h.Write([]byte{0})
+			h.Write([]byte(m[k]))
+			h.Write([]byte{0})
+		}
+		h.Write([]byte{0})
 	}
+	writeMap(dest.Config)
+	writeMap(dest.Credentials)
 	h.Write([]byte(dest.Type))
 	return dest.ID + "." + strconv.FormatUint(h.Sum64(), 36)
 }
@@ -307,10 +388,18 @@ func (r *registry) DisplayDestination(destination *models.Destination) (*Destina
 	// Then compute the target
 	target := provider.ComputeTarget(destination)
 
-	return &DestinationDisplay{
+	display := &DestinationDisplay{
 		Destination:       obfuscated,
 		DestinationTarget: target,
-	}, nil
+	}
+	if certProvider, ok := provider.(CertificateExpiryProvider); ok {
+		// Certificates are validated when the destination is saved, so an
+		// invalid one only omits the expiry.
+		if expiresAt, err := certProvider.CertificateExpiry(destination); err == nil {
+			display.CertificateExpiresAt = expiresAt
+		}
+	}
+	return display, nil
 }
 
 // PreprocessDestination resolves the provider and calls its Preprocess method
@@ -28,6 +28,7 @@ type mockPublisher struct {
 	closed       bool
 	publishDelay time.Duration
 	mockError    error
+	lastEvent    *models.Event
 }
 
 var mockPublisherID int64
@@ -109,6 +110,7 @@ func (p *mockProvider) ComputeTarget(dest *models.Destination) destregistry.Dest
 }
 
 func (p *mockPublisher) Publish(ctx context.Context, event *models.Event) (*destregistry.Delivery, error) {
+	p.lastEvent = event
 	select {
 	case <-time.After(p.publishDelay):
 		if p.mockError != nil {
@@ -533,6 +535,51 @@ func TestPublisherEviction(t *testing.T) {
 	assert.True(t, mp1.closed, "Expected evicted publisher to be closed")
 }
 
+func TestMakePublisherKey(t *testing.T) {
+	t.Parallel()
+
+	newDest := func() *models.Destination {
+		return &models.Destination{
+			ID:   "test-dest",
+			Type: "mock",
+			Config: map[string]string{
+				"server_url": "localhost:1883",
+				"topic":      "events",
+				"qos":        "1",
+				"retain":     "false",
+				"tls":        "true",
+			},
+			Credentials: map[string]string{
+				"username": "outpost",
+				"password": "secret",
+			},
+		}
+	}
+
+	t.Run("should be stable for the same destination", func(t *testing.T) {
+		t.Parallel()
+		key := destregistry.MakePublisherKey(newDest())
+		for i := 0; i < 100; i++ {
+			require.Equal(t, key, destregistry.MakePublisherKey(newDest()))
+		}
+	})
+
+	t.Run("should change with credentials", func(t *testing.T) {
+		t.Parallel()
+		dest := newDest()
+		key := destregistry.MakePublisherKey(dest)
+		dest.Credentials["password"] = "rotated"
+		assert.NotEqual(t, key, destregistry.MakePublisherKey(dest))
+	})
+
+	t.Run("should not collide across keys and values", func(t *testing.T) {
+		t.Parallel()
+		a := &models.Destination{ID: "test-dest", Type: "mock", Config: map[string]string{"ab": "c"}}
+		b := &models.Destination{ID: "test-dest", Type: "mock", Config: map[string]string{"a": "bc"}}
+		assert.NotEqual(t, destregistry.MakePublisherKey(a), destregistry.MakePublisherKey(b))
+	})
+}
+
 func TestObfuscateValue(t *testing.T) {
 	t.Parallel()
 
@@ -700,6 +747,207 @@ func TestPublishEventTimeout(t *testing.T) {
 	})
 }
 
+func TestPublishEventTransformation(t *testing.T) {
+	t.Parallel()
+
+	logger := testutil.CreateTestLogger(t)
+	registry := destregistry.NewRegistry(&destregistry.Config{}, logger)
+	provider, err := newMockProvider()
+	require.NoError(t, err)
+	require.NoError(t, registry.RegisterProvider("test", provider))
+
+	event := &models.Event{
+		ID:       "evt_123",
+		Topic:    "order.created",
+		Metadata: map[string]string{"region": "eu"},
+		Data:     map[string]interface{}{"amount": float64(100), "customer": map[string]interface{}{"id": "cus_123"}},
+	}
+
+	t.Run("should publish transformed event", func(t *testing.T) {
+		destination := &models.Destination{
+			ID:   "dest_transformed",
+			Type: "test",
+			Transformation: &models.Transformation{
+				Data:     "{text: join(' ', [topic, customer_id || 'unknown']), customer_id: data.customer.id}",
+				Metadata: "{customer: data.customer.id}",
+			},
+		}
+
+		delivery, err := registry.PublishEvent(context.Background(), destination, event)
+		require.NoError(t, err)
+		assert.Equal(t, "success", delivery.Status)
+
+		publisher, err := registry.ResolvePublisher(context.Background(), destination)
+		require.NoError(t, err)
+		published := publisher.(*mockPublisher).lastEvent
+		assert.Equal(t, models.Data{"text": "order.created unknown", "customer_id": "cus_123"}, published.Data)
+		assert.Equal(t, models.Metadata{"region": "eu", "customer": "cus_123"}, published.Metadata)
+
+		// The original event is left untouched
+		assert.Equal(t, float64(100), event.Data["amount"])
+		assert.Equal(t, models.Metadata{"region": "eu"}, event.Metadata)
+	})
+
+	t.Run("should fail delivery when transformation fails", func(t *testing.T) {
+		destination := &models.Destination{
+			ID:   "dest_invalid",
+			Type: "test",
+			Transformation: &models.Transformation{
+				Data: "data.amount",
+			},
+		}
+
+		delivery, err := registry.PublishEvent(context.Background(), destination, event)
+		require.Error(t, err)
+		require.NotNil(t, delivery)
+		assert.Equal(t, models.DeliveryStatusFailed, delivery.Status)
+
+		var publishErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &publishErr)
+		assert.Equal(t, "transformation_failed", publishErr.Data["error"])
+		assert.True(t, publishErr.NonRetryable, "transformation failures should not be retried")
+		assert.ErrorIs(t, publishErr.Err, destregistry.ErrTransformationFailed)
+	})
+}
+
+type mockBatchPublisher struct {
+	*mockPublisher
+	mu        sync.Mutex
+	batches   [][]*models.Event
+	mockError error
+}
+
+func (p *mockBatchPublisher) PublishBatch(ctx context.Context, events []*models.Event) (*destregistry.Delivery, error) {
+	p.mu.Lock()
+	p.batches = append(p.batches, events)
+	p.mu.Unlock()
+	if p.mockError != nil {
+		return &destregistry.Delivery{Status: "failed", Code: "500"}, destregistry.NewErrDestinationPublishAttempt(p.mockError, "batch", nil)
+	}
+	return &destregistry.Delivery{Status: "success", Code: "200"}, nil
+}
+
+type mockBatchProvider struct {
+	*mockProvider
+	publisher *mockBatchPublisher
+}
+
+func (p *mockBatchProvider) CreatePublisher(ctx context.Context, dest *models.Destination) (destregistry.Publisher, error) {
+	return p.publisher, nil
+}
+
+func (p *mockBatchProvider) SupportsBatch() bool { return true }
+
+func TestPublishEventBatch(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (destregistry.Registry, *mockBatchPublisher) {
+		logger := testutil.CreateTestLogger(t)
+		registry := destregistry.NewRegistry(&destregistry.Config{}, logger)
+		provider, err := newMockProvider()
+		require.NoError(t, err)
+		batchProvider := &mockBatchProvider{
+			mockProvider: provider,
+			publisher:    &mockBatchPublisher{mockPublisher: newMockPublisher()},
+		}
+		require.NoError(t, registry.RegisterProvider("batch", batchProvider))
+		require.NoError(t, registry.RegisterProvider("mock", provider))
+		return registry, batchProvider.publisher
+	}
+
+	publishConcurrently := func(registry destregistry.Registry, destination *models.Destination, count int) ([]*models.Delivery, []error) {
+		deliveries := make([]*models.Delivery, count)
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				event := testutil.EventFactory.Any()
+				deliveries[i], errs[i] = registry.PublishEvent(context.Background(), destination, &event)
+			}(i)
+		}
+		wg.Wait()
+		return deliveries, errs
+	}
+
+	t.Run("should send a batch once it's full", func(t *testing.T) {
+		t.Parallel()
+		registry, publisher := setup(t)
+		destination := &models.Destination{
+			ID:    "dest_full",
+			Type:  "batch",
+			Batch: &models.Batch{MaxSize: 3, Window: models.Duration(time.Minute)},
+		}
+
+		start := time.Now()
+		deliveries, errs := publishConcurrently(registry, destination, 3)
+		assert.Less(t, time.Since(start), time.Minute)
+		require.Len(t, publisher.batches, 1)
+		assert.Len(t, publisher.batches[0], 3)
+
+		ids := map[string]bool{}
+		for i, delivery := range deliveries {
+			require.NoError(t, errs[i])
+			assert.Equal(t, "success", delivery.Status)
+			ids[delivery.ID] = true
+		}
+		assert.Len(t, ids, 3, "each event should get its own delivery")
+	})
+
+	t.Run("should send a partial batch once the window elapses", func(t *testing.T) {
+		t.Parallel()
+		registry, publisher := setup(t)
+		destination := &models.Destination{
+			ID:    "dest_window",
+			Type:  "batch",
+			Batch: &models.Batch{MaxSize: 10, Window: models.Duration(50 * time.Millisecond)},
+		}
+
+		_, errs := publishConcurrently(registry, destination, 4)
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		total := 0
+		for _, batch := range publisher.batches {
+			total += len(batch)
+		}
+		assert.Equal(t, 4, total)
+	})
+
+	t.Run("should fail every event of a failed batch", func(t *testing.T) {
+		t.Parallel()
+		registry, publisher := setup(t)
+		publisher.mockError = errors.New("batch failed")
+		destination := &models.Destination{
+			ID:    "dest_failed",
+			Type:  "batch",
+			Batch: &models.Batch{MaxSize: 2, Window: models.Duration(time.Minute)},
+		}
+
+		deliveries, errs := publishConcurrently(registry, destination, 2)
+		for i := range deliveries {
+			var publishErr *destregistry.ErrDestinationPublishAttempt
+			require.ErrorAs(t, errs[i], &publishErr)
+			require.NotNil(t, deliveries[i])
+			assert.Equal(t, "failed", deliveries[i].Status)
+		}
+	})
+
+	t.Run("should reject batching for unsupported providers", func(t *testing.T) {
+		t.Parallel()
+		registry, _ := setup(t)
+
+		err := registry.ValidateDestination(context.Background(), &models.Destination{
+			Type:  "mock",
+			Batch: &models.Batch{MaxSize: 10},
+		})
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "batch", validationErr.Errors[0].Field)
+	})
+}
+
 func TestDisplayDestination(t *testing.T) {
 	t.Parallel()
 
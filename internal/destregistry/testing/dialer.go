@@ -0,0 +1,22 @@
+package testing
+
+import (
+	"context"
+	"net"
+)
+
+// DialFunc connects to an address, like net.Dialer.DialContext
+type DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// RedirectDialer returns a DialFunc that connects to host instead of the dialed
+// host, keeping the port. Server URL patterns reject loopback addresses, so tests
+// configure destinations with a public hostname and redirect it to the local server.
+func RedirectDialer(dial DialFunc, host string) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		return dial(ctx, network, net.JoinHostPort(host, port))
+	}
+}
@@ -0,0 +1,138 @@
+package destregistry
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+var (
+	ErrInvalidClientCertificate  = errors.New("invalid client certificate")
+	ErrInvalidClientKey          = errors.New("invalid client key")
+	ErrInvalidCACertificate      = errors.New("invalid CA certificate")
+	ErrInvalidServerFingerprint  = errors.New("invalid server certificate fingerprint")
+	ErrServerFingerprintMismatch = errors.New("server certificate doesn't match the pinned fingerprint")
+)
+
+// TLSClientConfig holds the PEM-encoded TLS settings of a destination.
+type TLSClientConfig struct {
+	// ClientCert and ClientKey are the client certificate chain and private key
+	// presented for mutual TLS.
+	ClientCert string
+	ClientKey  string
+	// CACerts is a bundle of certificates trusted instead of the system roots.
+	CACerts string
+	// ServerFingerprint is the SHA-256 hex fingerprint of the server's leaf
+	// certificate, optionally separated by colons.
+	ServerFingerprint string
+}
+
+// CertificateExpiryProvider is implemented by providers whose destinations
+// can carry a client certificate.
+type CertificateExpiryProvider interface {
+	// CertificateExpiry returns when the destination's client certificate
+	// expires, or nil if it has none.
+	CertificateExpiry(destination *models.Destination) (*time.Time, error)
+}
+
+func (c *TLSClientConfig) IsZero() bool {
+	return c == nil || (c.ClientCert == "" && c.ClientKey == "" && c.CACerts == "" && c.ServerFingerprint == "")
+}
+
+// Build validates the settings and returns the TLS configuration of the
+// destination's HTTP client.
+func (c *TLSClientConfig) Build() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if _, err := parseCertificates(c.ClientCert); err != nil {
+			return nil, ErrInvalidClientCertificate
+		}
+		cert, err := tls.X509KeyPair([]byte(c.ClientCert), []byte(c.ClientKey))
+		if err != nil {
+			return nil, ErrInvalidClientKey
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CACerts != "" {
+		certs, err := parseCertificates(c.CACerts)
+		if err != nil {
+			return nil, ErrInvalidCACertificate
+		}
+		pool := x509.NewCertPool()
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+		config.RootCAs = pool
+	}
+
+	if c.ServerFingerprint != "" {
+		fingerprint, err := hex.DecodeString(strings.ReplaceAll(c.ServerFingerprint, ":", ""))
+		if err != nil || len(fingerprint) != sha256.Size {
+			return nil, ErrInvalidServerFingerprint
+		}
+		// The chain is still verified, the pin only narrows which server
+		// certificate is accepted.
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return ErrServerFingerprintMismatch
+			}
+			sum := sha256.Sum256(cs.PeerCertificates[0].Raw)
+			if subtle.ConstantTimeCompare(sum[:], fingerprint) != 1 {
+				return ErrServerFingerprintMismatch
+			}
+			return nil
+		}
+	}
+
+	return config, nil
+}
+
+// ClientCertExpiry returns when the leaf client certificate expires, or nil if
+// there's no client certificate.
+func (c *TLSClientConfig) ClientCertExpiry() (*time.Time, error) {
+	if c.ClientCert == "" {
+		return nil, nil
+	}
+	certs, err := parseCertificates(c.ClientCert)
+	if err != nil {
+		return nil, ErrInvalidClientCertificate
+	}
+	expiresAt := certs[0].NotAfter
+	return &expiresAt, nil
+}
+
+// parseCertificates parses a PEM bundle that must only hold certificates.
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, errors.New("unexpected PEM block " + block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 || len(bytes.TrimSpace(rest)) > 0 {
+		return nil, errors.New("malformed PEM")
+	}
+	return certs, nil
+}
@@ -0,0 +1,24 @@
+package destregistry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// ErrVerificationFailed is returned when a destination's endpoint doesn't
+// complete the verification handshake.
+var ErrVerificationFailed = errors.New("destination verification failed")
+
+// VerifiableProvider is implemented by providers that verify tenants own the
+// endpoint of a destination before delivering to it. Preprocess marks the
+// destinations that need it as pending verification.
+type VerifiableProvider interface {
+	// RequiresVerification reports whether destinations must pass the
+	// verification handshake before receiving events.
+	RequiresVerification() bool
+	// Verify runs the verification handshake with the destination's endpoint.
+	// The error wraps ErrVerificationFailed if the endpoint failed the check.
+	Verify(ctx context.Context, destination *models.Destination) error
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultBatchMaxSize = 100
+	DefaultBatchWindow  = time.Second
+	MaxBatchMaxSize     = 1000
+	MaxBatchWindow      = 5 * time.Second
+)
+
+var (
+	ErrInvalidBatch = errors.New("validation failed: invalid batch")
+)
+
+// Batch groups deliveries to a destination into a single request. A batch is
+// sent once it reaches MaxSize events or Window has elapsed since its first event.
+type Batch struct {
+	// MaxSize is the maximum number of events in a batch. Defaults to DefaultBatchMaxSize.
+	MaxSize int `json:"max_size,omitempty"`
+	// Window is how long events are collected before the batch is sent. Defaults to DefaultBatchWindow.
+	Window Duration `json:"window,omitempty"`
+}
+
+var _ encoding.BinaryMarshaler = &Batch{}
+var _ encoding.BinaryUnmarshaler = &Batch{}
+
+func (b *Batch) MarshalBinary() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b *Batch) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, b)
+}
+
+// IsZero returns true if batching isn't configured.
+func (b *Batch) IsZero() bool {
+	return b == nil || (b.MaxSize == 0 && b.Window == 0)
+}
+
+func (b *Batch) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if b.MaxSize < 0 || b.MaxSize > MaxBatchMaxSize {
+		return ErrInvalidBatch
+	}
+	if b.Window < 0 || time.Duration(b.Window) > MaxBatchWindow {
+		return ErrInvalidBatch
+	}
+	return nil
+}
+
+func (b *Batch) ResolvedMaxSize() int {
+	if b.MaxSize == 0 {
+		return DefaultBatchMaxSize
+	}
+	return b.MaxSize
+}
+
+func (b *Batch) ResolvedWindow() time.Duration {
+	if b.Window == 0 {
+		return DefaultBatchWindow
+	}
+	return time.Duration(b.Window)
+}
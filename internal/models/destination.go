@@ -19,14 +19,37 @@ var (
 )
 
 type Destination struct {
-	ID          string      `json:"id" redis:"id"`
-	TenantID    string      `json:"tenant_id" redis:"-"`
-	Type        string      `json:"type" redis:"type"`
-	Topics      Topics      `json:"topics" redis:"-"`
-	Config      Config      `json:"config" redis:"-"`
-	Credentials Credentials `json:"credentials" redis:"-"`
-	CreatedAt   time.Time   `json:"created_at" redis:"created_at"`
-	DisabledAt  *time.Time  `json:"disabled_at" redis:"disabled_at"`
+	ID             string          `json:"id" redis:"id"`
+	TenantID       string          `json:"tenant_id" redis:"-"`
+	Type           string          `json:"type" redis:"type"`
+	Topics         Topics          `json:"topics" redis:"-"`
+	Filter         string          `json:"filter,omitempty" redis:"filter"`
+	OrderingKey    string          `json:"ordering_key,omitempty" redis:"ordering_key"`
+	Transformation *Transformation `json:"transformation,omitempty" redis:"-"`
+	RetryPolicy    *RetryPolicy    `json:"retry_policy,omitempty" redis:"-"`
+	RateLimit      *RateLimit      `json:"rate_limit,omitempty" redis:"-"`
+	Batch          *Batch          `json:"batch,omitempty" redis:"-"`
+	Config         Config          `json:"config" redis:"-"`
+	Credentials    Credentials     `json:"credentials" redis:"-"`
+	CreatedAt      time.Time       `json:"created_at" redis:"created_at"`
+	DisabledAt     *time.Time      `json:"disabled_at" redis:"disabled_at"`
+	// VerificationStatus is set for destinations that must pass a verification
+	// handshake before receiving events
+	VerificationStatus string     `json:"verification_status,omitempty" redis:"verification_status"`
+	VerifiedAt         *time.Time `json:"verified_at,omitempty" redis:"verified_at"`
+}
+
+const (
+	// DestinationVerificationPending destinations don't receive events until
+	// they are verified
+	DestinationVerificationPending  = "pending"
+	DestinationVerificationVerified = "verified"
+)
+
+// PendingVerification reports whether the destination hasn't passed its
+// verification handshake yet
+func (d *Destination) PendingVerification() bool {
+	return d.VerificationStatus == DestinationVerificationPending
 }
 
 func (d *Destination) parseRedisHash(cmd *redis.MapStringStringCmd, cipher Cipher) error {
@@ -48,6 +71,30 @@ func (d *Destination) parseRedisHash(cmd *redis.MapStringStringCmd, cipher Ciphe
 	if err != nil {
 		return fmt.Errorf("invalid topics: %w", err)
 	}
+	if transformation := hash["transformation"]; transformation != "" {
+		d.Transformation = &Transformation{}
+		if err := d.Transformation.UnmarshalBinary([]byte(transformation)); err != nil {
+			return fmt.Errorf("invalid transformation: %w", err)
+		}
+	}
+	if retryPolicy := hash["retry_policy"]; retryPolicy != "" {
+		d.RetryPolicy = &RetryPolicy{}
+		if err := d.RetryPolicy.UnmarshalBinary([]byte(retryPolicy)); err != nil {
+			return fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
+	if rateLimit := hash["rate_limit"]; rateLimit != "" {
+		d.RateLimit = &RateLimit{}
+		if err := d.RateLimit.UnmarshalBinary([]byte(rateLimit)); err != nil {
+			return fmt.Errorf("invalid rate limit: %w", err)
+		}
+	}
+	if batch := hash["batch"]; batch != "" {
+		d.Batch = &Batch{}
+		if err := d.Batch.UnmarshalBinary([]byte(batch)); err != nil {
+			return fmt.Errorf("invalid batch: %w", err)
+		}
+	}
 	err = d.Config.UnmarshalBinary([]byte(hash["config"]))
 	if err != nil {
 		return fmt.Errorf("invalid config: %w", err)
@@ -67,6 +114,26 @@ func (d *Destination) Validate(topics []string) error {
 	if err := d.Topics.Validate(topics); err != nil {
 		return err
 	}
+	if err := ValidateFilter(d.Filter); err != nil {
+		return err
+	}
+	if err := d.Transformation.Validate(); err != nil {
+		return err
+	}
+	if err := d.RetryPolicy.Validate(); err != nil {
+		return err
+	}
+	if d.OrderingKey != "" {
+		if err := d.RetryPolicy.ValidateOrdered(); err != nil {
+			return err
+		}
+	}
+	if err := d.RateLimit.Validate(); err != nil {
+		return err
+	}
+	if err := d.Batch.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -74,7 +141,10 @@ type DestinationSummary struct {
 	ID       string `json:"id"`
 	Type     string `json:"type"`
 	Topics   Topics `json:"topics"`
+	Filter   string `json:"filter,omitempty"`
 	Disabled bool   `json:"disabled"`
+	// PendingVerification destinations are skipped like disabled ones
+	PendingVerification bool `json:"pending_verification,omitempty"`
 }
 
 var _ encoding.BinaryMarshaler = &DestinationSummary{}
@@ -93,7 +163,10 @@ func (d *Destination) ToSummary() *DestinationSummary {
 		ID:       d.ID,
 		Type:     d.Type,
 		Topics:   d.Topics,
+		Filter:   d.Filter,
 		Disabled: d.DisabledAt != nil,
+
+		PendingVerification: d.PendingVerification(),
 	}
 }
 
@@ -110,6 +183,18 @@ func (t *Topics) MatchesAll() bool {
 	return len(*t) == 1 && (*t)[0] == "*"
 }
 
+// Match returns true if the given event topic is covered by the topics,
+// either through the "*" catch-all, an exact topic, or a topic pattern.
+func (t *Topics) Match(topic string) bool {
+	for _, pattern := range *t {
+		// A bare "*" is the catch-all, even alongside other topics.
+		if pattern == "*" || pattern == topic || (IsTopicPattern(pattern) && MatchTopic(pattern, topic)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *Topics) Validate(availableTopics []string) error {
 	if len(*t) == 0 {
 		return ErrInvalidTopics
@@ -117,15 +202,29 @@ func (t *Topics) Validate(availableTopics []string) error {
 	if t.MatchesAll() {
 		return nil
 	}
-	// If no available topics are configured, allow any topics
-	if len(availableTopics) == 0 {
-		return nil
-	}
 	for _, topic := range *t {
 		if topic == "*" {
-			return ErrInvalidTopics
+			// Kept for compatibility with destinations created before topics
+			// were configured, which accepted "*" alongside other topics.
+			if len(availableTopics) > 0 {
+				return ErrInvalidTopics
+			}
+			continue
 		}
-		if !slices.Contains(availableTopics, topic) {
+		if IsTopicPattern(topic) {
+			if !isValidTopicPattern(topic) {
+				return ErrInvalidTopics
+			}
+			// A pattern must match at least one of the available topics
+			if len(availableTopics) > 0 && !slices.ContainsFunc(availableTopics, func(availableTopic string) bool {
+				return MatchTopic(topic, availableTopic)
+			}) {
+				return ErrInvalidTopics
+			}
+			continue
+		}
+		// If no available topics are configured, allow any topics
+		if len(availableTopics) > 0 && !slices.Contains(availableTopics, topic) {
 			return ErrInvalidTopics
 		}
 	}
@@ -164,6 +263,76 @@ func TopicsFromString(s string) Topics {
 	return Topics(strings.Split(s, ","))
 }
 
+// Topic patterns are made of "."-separated segments where "*" matches exactly
+// one segment and "#" matches zero or more segments, e.g. "order.*" matches
+// "order.created" while "order.#" also matches "order" and "order.item.added".
+const (
+	topicSegmentSeparator = "."
+	topicSingleWildcard   = "*"
+	topicMultiWildcard    = "#"
+)
+
+// IsTopicPattern returns true if the topic contains a wildcard segment.
+func IsTopicPattern(topic string) bool {
+	for _, segment := range strings.Split(topic, topicSegmentSeparator) {
+		if segment == topicSingleWildcard || segment == topicMultiWildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchTopic returns true if the topic matches the pattern.
+func MatchTopic(pattern, topic string) bool {
+	return matchTopicSegments(
+		strings.Split(pattern, topicSegmentSeparator),
+		strings.Split(topic, topicSegmentSeparator),
+	)
+}
+
+func matchTopicSegments(pattern, topic []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case topicMultiWildcard:
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(topic); i++ {
+				if matchTopicSegments(pattern[1:], topic[i:]) {
+					return true
+				}
+			}
+			return false
+		case topicSingleWildcard:
+			if len(topic) == 0 {
+				return false
+			}
+		default:
+			if len(topic) == 0 || pattern[0] != topic[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		topic = topic[1:]
+	}
+	return len(topic) == 0
+}
+
+// isValidTopicPattern returns false for patterns with empty segments or with
+// wildcards mixed into a segment, such as "order..created" or "order.cre*".
+func isValidTopicPattern(pattern string) bool {
+	for _, segment := range strings.Split(pattern, topicSegmentSeparator) {
+		if segment == "" {
+			return false
+		}
+		if segment != topicSingleWildcard && segment != topicMultiWildcard &&
+			strings.ContainsAny(segment, topicSingleWildcard+topicMultiWildcard) {
+			return false
+		}
+	}
+	return true
+}
+
 type Config = MapStringString
 type Credentials = MapStringString
 type MapStringString map[string]string
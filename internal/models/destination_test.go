@@ -70,11 +70,52 @@ func TestDestinationTopics_Validate(t *testing.T) {
 			availableTopics: []string{},
 			validated:       true,
 		},
+		{
+			topics:          []string{"*", "any.topic"},
+			availableTopics: []string{},
+			validated:       true,
+		},
 		{
 			topics:          []string{},
 			availableTopics: []string{},
 			validated:       false, // still require at least one topic
 		},
+		// Test cases for topic patterns
+		{
+			topics:          []string{"user.*"},
+			availableTopics: testutil.TestTopics,
+			validated:       true,
+		},
+		{
+			topics:          []string{"user.#", "user.created"},
+			availableTopics: testutil.TestTopics,
+			validated:       true,
+		},
+		{
+			topics:          []string{"#"},
+			availableTopics: testutil.TestTopics,
+			validated:       true,
+		},
+		{
+			topics:          []string{"order.*"},
+			availableTopics: testutil.TestTopics,
+			validated:       false, // pattern doesn't match any available topic
+		},
+		{
+			topics:          []string{"user.cre*"},
+			availableTopics: testutil.TestTopics,
+			validated:       false,
+		},
+		{
+			topics:          []string{"order.*"},
+			availableTopics: []string{},
+			validated:       true,
+		},
+		{
+			topics:          []string{"order..*"},
+			availableTopics: []string{},
+			validated:       false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -84,3 +125,51 @@ func TestDestinationTopics_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchTopic(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		pattern string
+		topic   string
+		matched bool
+	}{
+		{pattern: "order.created", topic: "order.created", matched: true},
+		{pattern: "order.created", topic: "order.refunded", matched: false},
+		{pattern: "order.*", topic: "order.created", matched: true},
+		{pattern: "order.*", topic: "order", matched: false},
+		{pattern: "order.*", topic: "order.item.added", matched: false},
+		{pattern: "order.*", topic: "invoice.paid", matched: false},
+		{pattern: "*.paid", topic: "invoice.paid", matched: true},
+		{pattern: "order.#", topic: "order", matched: true},
+		{pattern: "order.#", topic: "order.created", matched: true},
+		{pattern: "order.#", topic: "order.item.added", matched: true},
+		{pattern: "order.#", topic: "invoice.paid", matched: false},
+		{pattern: "#.added", topic: "order.item.added", matched: true},
+		{pattern: "order.#.added", topic: "order.added", matched: true},
+		{pattern: "order.#.added", topic: "order.item.removed", matched: false},
+		{pattern: "#", topic: "invoice.paid", matched: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("match %s with %s", tc.pattern, tc.topic), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.matched, models.MatchTopic(tc.pattern, tc.topic))
+		})
+	}
+}
+
+func TestDestinationTopics_Match(t *testing.T) {
+	t.Parallel()
+
+	topics := models.Topics{"order.*", "invoice.paid"}
+	assert.True(t, topics.Match("order.created"))
+	assert.True(t, topics.Match("invoice.paid"))
+	assert.False(t, topics.Match("invoice.created"))
+
+	all := models.Topics{"*"}
+	assert.True(t, all.Match("invoice.created"))
+
+	legacy := models.Topics{"*", "x"}
+	assert.True(t, legacy.Match("order.created"))
+}
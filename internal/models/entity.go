@@ -8,7 +8,9 @@ import (
 	"sort"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/redis"
+	"go.uber.org/zap"
 )
 
 const defaultMaxDestinationsPerTenant = 20
@@ -51,6 +53,7 @@ type entityStoreImpl struct {
 	cipher                   Cipher
 	availableTopics          []string
 	maxDestinationsPerTenant int
+	logger                   *logging.Logger
 }
 
 var _ EntityStore = (*entityStoreImpl)(nil)
@@ -75,6 +78,12 @@ func WithMaxDestinationsPerTenant(maxDestinationsPerTenant int) EntityStoreOptio
 	}
 }
 
+func WithLogger(logger *logging.Logger) EntityStoreOption {
+	return func(s *entityStoreImpl) {
+		s.logger = logger
+	}
+}
+
 func NewEntityStore(redisClient *redis.Client, opts ...EntityStoreOption) EntityStore {
 	store := &entityStoreImpl{
 		redisClient:              redisClient,
@@ -309,6 +318,36 @@ func (m *entityStoreImpl) UpsertDestination(ctx context.Context, destination Des
 		r.HSet(ctx, key, "id", destination.ID)
 		r.HSet(ctx, key, "type", destination.Type)
 		r.HSet(ctx, key, "topics", &destination.Topics)
+		if destination.Filter != "" {
+			r.HSet(ctx, key, "filter", destination.Filter)
+		} else {
+			r.HDel(ctx, key, "filter")
+		}
+		if destination.OrderingKey != "" {
+			r.HSet(ctx, key, "ordering_key", destination.OrderingKey)
+		} else {
+			r.HDel(ctx, key, "ordering_key")
+		}
+		if !destination.Transformation.IsZero() {
+			r.HSet(ctx, key, "transformation", destination.Transformation)
+		} else {
+			r.HDel(ctx, key, "transformation")
+		}
+		if !destination.RetryPolicy.IsZero() {
+			r.HSet(ctx, key, "retry_policy", destination.RetryPolicy)
+		} else {
+			r.HDel(ctx, key, "retry_policy")
+		}
+		if !destination.RateLimit.IsZero() {
+			r.HSet(ctx, key, "rate_limit", destination.RateLimit)
+		} else {
+			r.HDel(ctx, key, "rate_limit")
+		}
+		if !destination.Batch.IsZero() {
+			r.HSet(ctx, key, "batch", destination.Batch)
+		} else {
+			r.HDel(ctx, key, "batch")
+		}
 		r.HSet(ctx, key, "config", &destination.Config)
 		r.HSet(ctx, key, "credentials", encryptedCredentials)
 		r.HSet(ctx, key, "created_at", destination.CreatedAt)
@@ -317,6 +356,16 @@ func (m *entityStoreImpl) UpsertDestination(ctx context.Context, destination Des
 		} else {
 			r.HDel(ctx, key, "disabled_at")
 		}
+		if destination.VerificationStatus != "" {
+			r.HSet(ctx, key, "verification_status", destination.VerificationStatus)
+		} else {
+			r.HDel(ctx, key, "verification_status")
+		}
+		if destination.VerifiedAt != nil {
+			r.HSet(ctx, key, "verified_at", *destination.VerifiedAt)
+		} else {
+			r.HDel(ctx, key, "verified_at")
+		}
 		r.HSet(ctx, redisTenantDestinationSummaryKey(destination.TenantID), destination.ID, destination.ToSummary()).Val()
 		return nil
 	})
@@ -365,19 +414,23 @@ func (s *entityStoreImpl) matchEventWithAllDestination(ctx context.Context, even
 	}
 
 	if event.Topic == "" {
-		return destinationSummaryList, nil
+		return slices.DeleteFunc(destinationSummaryList, func(destinationSummary DestinationSummary) bool {
+			return !s.matchesEventFilter(ctx, destinationSummary.ID, destinationSummary.Filter, event)
+		}), nil
 	}
 
 	matchedDestinationSummaryList := []DestinationSummary{}
 
 	for _, destinationSummary := range destinationSummaryList {
-		if destinationSummary.Disabled {
+		if destinationSummary.Disabled || destinationSummary.PendingVerification {
 			continue
 		}
 		// If event topic is "*", match all destinations
-		// Otherwise, match if destination has "*" topic or matches the event topic
-		if event.Topic == "*" || destinationSummary.Topics.MatchesAll() || slices.Contains(destinationSummary.Topics, event.Topic) {
-			matchedDestinationSummaryList = append(matchedDestinationSummaryList, destinationSummary)
+		// Otherwise, match if destination has "*" topic, the event topic, or a pattern matching the event topic
+		if event.Topic == "*" || destinationSummary.Topics.Match(event.Topic) {
+			if s.matchesEventFilter(ctx, destinationSummary.ID, destinationSummary.Filter, event) {
+				matchedDestinationSummaryList = append(matchedDestinationSummaryList, destinationSummary)
+			}
 		}
 	}
 
@@ -392,12 +445,30 @@ func (s *entityStoreImpl) matchEventWithDestination(ctx context.Context, event E
 	if destination == nil {
 		return []DestinationSummary{}, nil
 	}
-	if event.Topic == "" || destination.Topics[0] == "*" || slices.Contains(destination.Topics, event.Topic) {
+	if (event.Topic == "" || destination.Topics.Match(event.Topic)) && s.matchesEventFilter(ctx, destination.ID, destination.Filter, event) {
 		return []DestinationSummary{*destination.ToSummary()}, nil
 	}
 	return []DestinationSummary{}, nil
 }
 
+// matchesEventFilter evaluates the destination filter against the event.
+// A filter that fails to evaluate is logged and treated as not matching so
+// that a single misbehaving filter doesn't block the event from other destinations.
+func (s *entityStoreImpl) matchesEventFilter(ctx context.Context, destinationID string, filter string, event Event) bool {
+	matched, err := MatchFilter(filter, event)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Ctx(ctx).Warn("failed to evaluate destination filter",
+				zap.Error(err),
+				zap.String("tenant_id", event.TenantID),
+				zap.String("destination_id", destinationID),
+				zap.String("event_id", event.ID))
+		}
+		return false
+	}
+	return matched
+}
+
 func (s *entityStoreImpl) parseTenantTopics(destinationSummaryList []DestinationSummary) []string {
 	all := false
 	topicsSet := make(map[string]struct{})
@@ -407,6 +478,16 @@ func (s *entityStoreImpl) parseTenantTopics(destinationSummaryList []Destination
 				all = true
 				break
 			}
+			// Expand patterns into the available topics they match. Without
+			// available topics there is nothing to expand, so keep the pattern.
+			if IsTopicPattern(topic) && len(s.availableTopics) > 0 {
+				for _, availableTopic := range s.availableTopics {
+					if MatchTopic(topic, availableTopic) {
+						topicsSet[availableTopic] = struct{}{}
+					}
+				}
+				continue
+			}
 			topicsSet[topic] = struct{}{}
 		}
 	}
@@ -449,7 +530,7 @@ func (filter DestinationFilter) match(destinationSummary DestinationSummary) boo
 				return false
 			}
 			for _, topic := range filter.Topics {
-				if !slices.Contains(destinationSummary.Topics, topic) {
+				if !destinationSummary.Topics.Match(topic) {
 					return false
 				}
 			}
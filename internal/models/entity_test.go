@@ -663,6 +663,50 @@ func TestMultiSuite_DisableAndMatch(t *testing.T) {
 	})
 }
 
+func TestMultiSuite_VerificationAndMatch(t *testing.T) {
+	t.Parallel()
+
+	suite := multiDestinationSuite{}
+	suite.SetupTest(t)
+
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(suite.tenant.ID),
+		testutil.EventFactory.WithTopic("user.deleted"),
+	)
+
+	t.Run("should not match destination pending verification", func(t *testing.T) {
+		destination := suite.destinations[0]
+		destination.VerificationStatus = models.DestinationVerificationPending
+		require.NoError(t, suite.entityStore.UpsertDestination(suite.ctx, destination))
+
+		actual, err := suite.entityStore.RetrieveDestination(suite.ctx, destination.TenantID, destination.ID)
+		require.NoError(t, err)
+		assert.True(t, actual.PendingVerification())
+
+		matchedDestinationSummaryList, err := suite.entityStore.MatchEvent(suite.ctx, event)
+		require.NoError(t, err)
+		require.Len(t, matchedDestinationSummaryList, 1)
+		assert.Equal(t, suite.destinations[3].ID, matchedDestinationSummaryList[0].ID)
+	})
+
+	t.Run("should match verified destination", func(t *testing.T) {
+		destination := suite.destinations[0]
+		now := time.Now()
+		destination.VerificationStatus = models.DestinationVerificationVerified
+		destination.VerifiedAt = &now
+		require.NoError(t, suite.entityStore.UpsertDestination(suite.ctx, destination))
+
+		actual, err := suite.entityStore.RetrieveDestination(suite.ctx, destination.TenantID, destination.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.DestinationVerificationVerified, actual.VerificationStatus)
+		assert.True(t, cmp.Equal(destination.VerifiedAt, actual.VerifiedAt))
+
+		matchedDestinationSummaryList, err := suite.entityStore.MatchEvent(suite.ctx, event)
+		require.NoError(t, err)
+		require.Len(t, matchedDestinationSummaryList, 2)
+	})
+}
+
 func TestEntityStore_DeleteDestination(t *testing.T) {
 	t.Parallel()
 
@@ -729,6 +773,163 @@ func TestMultiSuite_DeleteAndMatch(t *testing.T) {
 	})
 }
 
+func TestEntityStore_TopicPatterns(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+	entityStore := models.NewEntityStore(redisClient,
+		models.WithCipher(models.NewAESCipher("secret")),
+		models.WithAvailableTopics([]string{"invoice.paid", "order.created", "order.item.added", "order.refunded"}),
+	)
+
+	tenant := models.Tenant{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, entityStore.UpsertTenant(ctx, tenant))
+
+	singleSegment := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+		testutil.DestinationFactory.WithTopics([]string{"order.*"}),
+	)
+	multiSegment := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+		testutil.DestinationFactory.WithTopics([]string{"order.#"}),
+	)
+	require.NoError(t, entityStore.CreateDestination(ctx, singleSegment))
+	require.NoError(t, entityStore.CreateDestination(ctx, multiSegment))
+
+	t.Run("tenant topics expand patterns", func(t *testing.T) {
+		actual, err := entityStore.RetrieveTenant(ctx, tenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"order.created", "order.item.added", "order.refunded"}, actual.Topics)
+	})
+
+	t.Run("match event by pattern", func(t *testing.T) {
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithTopic("order.created"),
+		)
+		matched, err := entityStore.MatchEvent(ctx, event)
+		require.NoError(t, err)
+		assert.Len(t, matched, 2)
+
+		event = testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithTopic("order.item.added"),
+		)
+		matched, err = entityStore.MatchEvent(ctx, event)
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		assert.Equal(t, multiSegment.ID, matched[0].ID)
+
+		event = testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithTopic("invoice.paid"),
+		)
+		matched, err = entityStore.MatchEvent(ctx, event)
+		require.NoError(t, err)
+		assert.Len(t, matched, 0)
+	})
+
+	t.Run("match event by pattern & destination", func(t *testing.T) {
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithTopic("order.item.added"),
+			testutil.EventFactory.WithDestinationID(singleSegment.ID),
+		)
+		matched, err := entityStore.MatchEvent(ctx, event)
+		require.NoError(t, err)
+		assert.Len(t, matched, 0)
+	})
+
+	t.Run("filter by topic", func(t *testing.T) {
+		destinations, err := entityStore.ListDestinationByTenant(ctx, tenant.ID, models.WithDestinationFilter(models.DestinationFilter{
+			Topics: []string{"order.item.added"},
+		}))
+		require.NoError(t, err)
+		require.Len(t, destinations, 1)
+		assert.Equal(t, multiSegment.ID, destinations[0].ID)
+	})
+}
+
+func TestEntityStore_MatchEventWithFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+	entityStore := models.NewEntityStore(redisClient,
+		models.WithCipher(models.NewAESCipher("secret")),
+		models.WithAvailableTopics(testutil.TestTopics),
+	)
+
+	tenant := models.Tenant{
+		ID:        uuid.New().String(),
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, entityStore.UpsertTenant(ctx, tenant))
+
+	unfiltered := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	filtered := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	filtered.Filter = "data.amount > `100` && metadata.region == 'eu'"
+	require.NoError(t, entityStore.CreateDestination(ctx, unfiltered))
+	require.NoError(t, entityStore.CreateDestination(ctx, filtered))
+
+	t.Run("persists filter", func(t *testing.T) {
+		actual, err := entityStore.RetrieveDestination(ctx, tenant.ID, filtered.ID)
+		require.NoError(t, err)
+		assert.Equal(t, filtered.Filter, actual.Filter)
+	})
+
+	t.Run("match event passing filter", func(t *testing.T) {
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithMetadata(map[string]string{"region": "eu"}),
+			testutil.EventFactory.WithData(map[string]interface{}{"amount": float64(150)}),
+		)
+		matched, err := entityStore.MatchEvent(ctx, event)
+		require.NoError(t, err)
+		assert.Len(t, matched, 2)
+	})
+
+	t.Run("skip destination when filter doesn't match", func(t *testing.T) {
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithMetadata(map[string]string{"region": "us"}),
+			testutil.EventFactory.WithData(map[string]interface{}{"amount": float64(150)}),
+		)
+		matched, err := entityStore.MatchEvent(ctx, event)
+		require.NoError(t, err)
+		require.Len(t, matched, 1)
+		assert.Equal(t, unfiltered.ID, matched[0].ID)
+	})
+
+	t.Run("skip destination when filter doesn't match with destination", func(t *testing.T) {
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(filtered.ID),
+			testutil.EventFactory.WithData(map[string]interface{}{"amount": float64(50)}),
+		)
+		matched, err := entityStore.MatchEvent(ctx, event)
+		require.NoError(t, err)
+		assert.Len(t, matched, 0)
+	})
+
+	t.Run("clears filter", func(t *testing.T) {
+		filtered.Filter = ""
+		require.NoError(t, entityStore.UpsertDestination(ctx, filtered))
+
+		actual, err := entityStore.RetrieveDestination(ctx, tenant.ID, filtered.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "", actual.Filter)
+	})
+}
+
 func TestEntityStore_MaxDestinationsPerTenant(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/lru"
+	"github.com/jmespath/go-jmespath"
+)
+
+var (
+	ErrInvalidFilter = errors.New("validation failed: invalid filter")
+)
+
+// filterSyntaxHint is appended to filter validation errors as JMESPath literal
+// syntax differs from most expression languages.
+const filterSyntaxHint = "use backticks for numbers and booleans and single quotes for strings, " +
+	"e.g. data.amount > `100` && metadata.region == 'eu'"
+
+// filterCacheSize bounds the number of compiled filters kept in memory.
+const filterCacheSize = 1000
+
+// filterCache holds compiled filters so that they aren't parsed for every event.
+var filterCache = lru.New[string, *jmespath.JMESPath](filterCacheSize, 0, nil)
+
+// ValidateFilter ensures the filter is a valid JMESPath expression.
+// An empty filter is valid and matches every event.
+func ValidateFilter(filter string) error {
+	if filter == "" {
+		return nil
+	}
+	if _, err := compileFilter(filter); err != nil {
+		return fmt.Errorf("%w: %s (%s)", ErrInvalidFilter, err, filterSyntaxHint)
+	}
+	return nil
+}
+
+func compileFilter(filter string) (*jmespath.JMESPath, error) {
+	if compiled, ok := filterCache.Get(filter); ok {
+		return compiled, nil
+	}
+	compiled, err := jmespath.Compile(filter)
+	if err != nil {
+		return nil, err
+	}
+	filterCache.Add(filter, compiled)
+	return compiled, nil
+}
+
+// MatchFilter evaluates the filter against the event and returns true if the
+// result is truthy. The filter is a JMESPath expression evaluated against an
+// object with the event "id", "topic", "time", "data" and "metadata", for example
+// `data.amount > `100` && metadata.region == 'eu'`.
+// An empty filter matches every event.
+func MatchFilter(filter string, event Event) (bool, error) {
+	if filter == "" {
+		return true, nil
+	}
+
+	compiled, err := compileFilter(filter)
+	if err != nil {
+		return false, err
+	}
+	result, err := compiled.Search(eventSearchInput(event))
+	if err != nil {
+		return false, err
+	}
+	return isTruthy(result), nil
+}
+
+// eventSearchInput converts the event into the generic object JMESPath
+// expressions are evaluated against.
+func eventSearchInput(event Event) map[string]interface{} {
+	metadata := make(map[string]interface{}, len(event.Metadata))
+	for k, v := range event.Metadata {
+		metadata[k] = v
+	}
+	data := map[string]interface{}(event.Data)
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return map[string]interface{}{
+		"id":       event.ID,
+		"topic":    event.Topic,
+		"time":     event.Time.Format(time.RFC3339),
+		"data":     data,
+		"metadata": metadata,
+	}
+}
+
+// isTruthy follows the JMESPath definition of truthiness where false, null,
+// empty strings, empty arrays and empty objects are false.
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() > 0
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFilter(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, models.ValidateFilter(""))
+	assert.NoError(t, models.ValidateFilter("data.amount > `100`"))
+	assert.NoError(t, models.ValidateFilter("data.amount > `100` && metadata.region == 'eu'"))
+	assert.ErrorIs(t, models.ValidateFilter("data.amount >"), models.ErrInvalidFilter)
+	assert.ErrorIs(t, models.ValidateFilter("data.[amount"), models.ErrInvalidFilter)
+
+	err := models.ValidateFilter(`data.amount > 100 && metadata.region == "eu"`)
+	require.ErrorIs(t, err, models.ErrInvalidFilter)
+	assert.Contains(t, err.Error(), "backticks", "error should explain the JMESPath literal syntax")
+}
+
+func TestMatchFilter(t *testing.T) {
+	t.Parallel()
+
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTopic("order.created"),
+		testutil.EventFactory.WithMetadata(map[string]string{"region": "eu"}),
+		testutil.EventFactory.WithData(map[string]interface{}{
+			"amount": float64(150),
+			"items":  []interface{}{"a", "b"},
+			"customer": map[string]interface{}{
+				"tier": "gold",
+			},
+		}),
+	)
+
+	testCases := []struct {
+		filter  string
+		matched bool
+	}{
+		{filter: "", matched: true},
+		{filter: "data.amount > `100`", matched: true},
+		{filter: "data.amount > `200`", matched: false},
+		{filter: "metadata.region == 'eu'", matched: true},
+		{filter: "metadata.region == 'us'", matched: false},
+		{filter: "data.amount > `100` && metadata.region == 'eu'", matched: true},
+		{filter: "data.amount > `100` && metadata.region == 'us'", matched: false},
+		{filter: "data.amount > `200` || metadata.region == 'eu'", matched: true},
+		{filter: "data.customer.tier == 'gold'", matched: true},
+		{filter: "topic == 'order.created'", matched: true},
+		{filter: "contains(data.items, 'b')", matched: true},
+		{filter: "data.items", matched: true},
+		{filter: "data.missing", matched: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			t.Parallel()
+			matched, err := models.MatchFilter(tc.filter, event)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matched, matched)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrInvalidRateLimit = errors.New("validation failed: invalid rate limit")
+)
+
+// RateLimit limits the rate and concurrency of deliveries to a destination.
+// Deliveries over the limit are deferred rather than failed.
+// Zero values disable the corresponding limit.
+type RateLimit struct {
+	// MaxRequestsPerSecond is the maximum number of deliveries started per second.
+	MaxRequestsPerSecond int `json:"max_requests_per_second,omitempty"`
+	// MaxInFlight is the maximum number of deliveries in progress at once.
+	MaxInFlight int `json:"max_in_flight,omitempty"`
+}
+
+var _ encoding.BinaryMarshaler = &RateLimit{}
+var _ encoding.BinaryUnmarshaler = &RateLimit{}
+
+func (l *RateLimit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+func (l *RateLimit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, l)
+}
+
+// IsZero returns true if the rate limit doesn't limit deliveries.
+func (l *RateLimit) IsZero() bool {
+	return l == nil || (l.MaxRequestsPerSecond == 0 && l.MaxInFlight == 0)
+}
+
+func (l *RateLimit) Validate() error {
+	if l == nil {
+		return nil
+	}
+	if l.MaxRequestsPerSecond < 0 || l.MaxInFlight < 0 {
+		return ErrInvalidRateLimit
+	}
+	return nil
+}
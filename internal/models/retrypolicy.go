@@ -0,0 +1,194 @@
+package models
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidRetryPolicy = errors.New("validation failed: invalid retry policy")
+)
+
+const (
+	RetryStrategyExponential = "exponential"
+	RetryStrategyConstant    = "constant"
+	RetryStrategySchedule    = "schedule"
+)
+
+// MaxOrderedRetryInterval is the longest retry interval of destinations with
+// an ordering key. Deliveries held behind a retrying delivery are released
+// once it has been inactive for a day, so its retries must be scheduled well
+// before that.
+const MaxOrderedRetryInterval = 12 * time.Hour
+
+// RetryPolicy overrides the global retry settings for a destination.
+// Zero values fall back to the global configuration.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of retries after the initial attempt.
+	// Defaults to the length of the schedule when a schedule is set.
+	MaxAttempts *int `json:"max_attempts,omitempty"`
+	// Strategy is one of "exponential", "constant" or "schedule".
+	Strategy string `json:"strategy,omitempty"`
+	// Interval is the base interval of the exponential and constant strategies.
+	Interval Duration `json:"interval,omitempty"`
+	// MaxInterval caps the interval of the exponential strategy.
+	MaxInterval Duration `json:"max_interval,omitempty"`
+	// Schedule is an explicit list of intervals such as ["1m", "5m", "30m"].
+	Schedule []Duration `json:"schedule,omitempty"`
+	// Jitter randomizes each interval by up to this factor, between 0 and 1.
+	Jitter float64 `json:"jitter,omitempty"`
+	// DeliveryTimeout overrides the timeout of each delivery attempt.
+	DeliveryTimeout Duration `json:"delivery_timeout,omitempty"`
+}
+
+var _ encoding.BinaryMarshaler = &RetryPolicy{}
+var _ encoding.BinaryUnmarshaler = &RetryPolicy{}
+
+func (p *RetryPolicy) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *RetryPolicy) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// IsZero returns true if the policy doesn't override any setting.
+func (p *RetryPolicy) IsZero() bool {
+	return p == nil || (p.MaxAttempts == nil &&
+		p.Strategy == "" &&
+		p.Interval == 0 &&
+		p.MaxInterval == 0 &&
+		len(p.Schedule) == 0 &&
+		p.Jitter == 0 &&
+		p.DeliveryTimeout == 0)
+}
+
+// ResolvedStrategy returns the strategy, inferring "schedule" when only a schedule is set.
+func (p *RetryPolicy) ResolvedStrategy() string {
+	if p.Strategy == "" && len(p.Schedule) > 0 {
+		return RetryStrategySchedule
+	}
+	return p.Strategy
+}
+
+// ResolvedMaxAttempts returns the maximum number of retries and whether the
+// policy overrides the global limit.
+func (p *RetryPolicy) ResolvedMaxAttempts() (int, bool) {
+	if p.IsZero() {
+		return 0, false
+	}
+	if p.MaxAttempts != nil {
+		return *p.MaxAttempts, true
+	}
+	if p.ResolvedStrategy() == RetryStrategySchedule {
+		return len(p.Schedule), true
+	}
+	return 0, false
+}
+
+func (p *RetryPolicy) Validate() error {
+	if p.IsZero() {
+		return nil
+	}
+	if p.MaxAttempts != nil && *p.MaxAttempts < 0 {
+		return ErrInvalidRetryPolicy
+	}
+	if p.Interval < 0 || p.MaxInterval < 0 || p.DeliveryTimeout < 0 {
+		return ErrInvalidRetryPolicy
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return ErrInvalidRetryPolicy
+	}
+	switch p.ResolvedStrategy() {
+	case "":
+		if p.Interval != 0 || p.MaxInterval != 0 {
+			return ErrInvalidRetryPolicy
+		}
+	case RetryStrategyExponential:
+		if p.Interval == 0 || len(p.Schedule) > 0 {
+			return ErrInvalidRetryPolicy
+		}
+		if p.MaxInterval != 0 && p.MaxInterval < p.Interval {
+			return ErrInvalidRetryPolicy
+		}
+	case RetryStrategyConstant:
+		if p.Interval == 0 || p.MaxInterval != 0 || len(p.Schedule) > 0 {
+			return ErrInvalidRetryPolicy
+		}
+	case RetryStrategySchedule:
+		if len(p.Schedule) == 0 || p.Interval != 0 || p.MaxInterval != 0 {
+			return ErrInvalidRetryPolicy
+		}
+		for _, interval := range p.Schedule {
+			if interval <= 0 {
+				return ErrInvalidRetryPolicy
+			}
+		}
+	default:
+		return ErrInvalidRetryPolicy
+	}
+	return nil
+}
+
+// ValidateOrdered validates that no retry of the policy, including jitter, is
+// scheduled later than MaxOrderedRetryInterval. Policies without a strategy
+// use the global backoff, which is capped when the retry is scheduled.
+func (p *RetryPolicy) ValidateOrdered() error {
+	if p.IsZero() {
+		return nil
+	}
+	var longest time.Duration
+	switch p.ResolvedStrategy() {
+	case "":
+		return nil
+	case RetryStrategyExponential:
+		switch {
+		case p.MaxInterval > 0:
+			longest = time.Duration(p.MaxInterval)
+		case p.MaxAttempts != nil:
+			longest = time.Duration(p.Interval)
+			for i := 0; i < *p.MaxAttempts && longest <= MaxOrderedRetryInterval; i++ {
+				longest *= 2
+			}
+		default:
+			// Unbounded
+			longest = MaxOrderedRetryInterval + 1
+		}
+	case RetryStrategyConstant:
+		longest = time.Duration(p.Interval)
+	case RetryStrategySchedule:
+		for _, interval := range p.Schedule {
+			longest = max(longest, time.Duration(interval))
+		}
+	}
+	if time.Duration(float64(longest)*(1+p.Jitter)) > MaxOrderedRetryInterval {
+		return fmt.Errorf("%w: retry intervals of destinations with an ordering key can't exceed %s", ErrInvalidRetryPolicy, MaxOrderedRetryInterval)
+	}
+	return nil
+}
+
+// Duration is a time.Duration represented as a string such as "5m" in JSON.
+type Duration time.Duration
+
+var _ json.Marshaler = Duration(0)
+var _ json.Unmarshaler = (*Duration)(nil)
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return ErrInvalidRetryPolicy
+	}
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return ErrInvalidRetryPolicy
+	}
+	*d = Duration(duration)
+	return nil
+}
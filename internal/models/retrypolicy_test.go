@@ -0,0 +1,261 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		policy  *models.RetryPolicy
+		wantErr bool
+	}{
+		{
+			name:   "nil policy",
+			policy: nil,
+		},
+		{
+			name:   "max attempts only",
+			policy: &models.RetryPolicy{MaxAttempts: intPtr(3)},
+		},
+		{
+			name:   "zero max attempts disables retries",
+			policy: &models.RetryPolicy{MaxAttempts: intPtr(0)},
+		},
+		{
+			name:    "negative max attempts",
+			policy:  &models.RetryPolicy{MaxAttempts: intPtr(-1)},
+			wantErr: true,
+		},
+		{
+			name: "exponential",
+			policy: &models.RetryPolicy{
+				Strategy:    models.RetryStrategyExponential,
+				Interval:    models.Duration(time.Second),
+				MaxInterval: models.Duration(time.Hour),
+				Jitter:      0.2,
+			},
+		},
+		{
+			name: "exponential without interval",
+			policy: &models.RetryPolicy{
+				Strategy: models.RetryStrategyExponential,
+			},
+			wantErr: true,
+		},
+		{
+			name: "exponential max interval below interval",
+			policy: &models.RetryPolicy{
+				Strategy:    models.RetryStrategyExponential,
+				Interval:    models.Duration(time.Minute),
+				MaxInterval: models.Duration(time.Second),
+			},
+			wantErr: true,
+		},
+		{
+			name: "constant",
+			policy: &models.RetryPolicy{
+				Strategy: models.RetryStrategyConstant,
+				Interval: models.Duration(time.Minute),
+			},
+		},
+		{
+			name: "constant with max interval",
+			policy: &models.RetryPolicy{
+				Strategy:    models.RetryStrategyConstant,
+				Interval:    models.Duration(time.Minute),
+				MaxInterval: models.Duration(time.Hour),
+			},
+			wantErr: true,
+		},
+		{
+			name: "inferred schedule",
+			policy: &models.RetryPolicy{
+				Schedule: []models.Duration{models.Duration(time.Minute)},
+			},
+		},
+		{
+			name: "schedule without entries",
+			policy: &models.RetryPolicy{
+				Strategy: models.RetryStrategySchedule,
+			},
+			wantErr: true,
+		},
+		{
+			name: "schedule with non-positive entry",
+			policy: &models.RetryPolicy{
+				Schedule: []models.Duration{0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "interval without strategy",
+			policy: &models.RetryPolicy{
+				Interval: models.Duration(time.Minute),
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown strategy",
+			policy: &models.RetryPolicy{
+				Strategy: "linear",
+				Interval: models.Duration(time.Minute),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "jitter out of range",
+			policy:  &models.RetryPolicy{Jitter: 1.5},
+			wantErr: true,
+		},
+		{
+			name:    "negative delivery timeout",
+			policy:  &models.RetryPolicy{DeliveryTimeout: models.Duration(-time.Second)},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidRetryPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_ValidateOrdered(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		policy  *models.RetryPolicy
+		wantErr bool
+	}{
+		{
+			name:   "global backoff",
+			policy: &models.RetryPolicy{MaxAttempts: intPtr(3)},
+		},
+		{
+			name: "capped exponential",
+			policy: &models.RetryPolicy{
+				Strategy:    models.RetryStrategyExponential,
+				Interval:    models.Duration(time.Second),
+				MaxInterval: models.Duration(time.Hour),
+			},
+		},
+		{
+			name: "exponential bounded by max attempts",
+			policy: &models.RetryPolicy{
+				MaxAttempts: intPtr(5),
+				Strategy:    models.RetryStrategyExponential,
+				Interval:    models.Duration(time.Minute),
+			},
+		},
+		{
+			name: "unbounded exponential",
+			policy: &models.RetryPolicy{
+				Strategy: models.RetryStrategyExponential,
+				Interval: models.Duration(time.Minute),
+			},
+			wantErr: true,
+		},
+		{
+			name: "exponential exceeding the limit within max attempts",
+			policy: &models.RetryPolicy{
+				MaxAttempts: intPtr(20),
+				Strategy:    models.RetryStrategyExponential,
+				Interval:    models.Duration(time.Minute),
+			},
+			wantErr: true,
+		},
+		{
+			name: "schedule exceeding the limit",
+			policy: &models.RetryPolicy{
+				Schedule: []models.Duration{models.Duration(time.Hour), models.Duration(24 * time.Hour)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "constant exceeding the limit with jitter",
+			policy: &models.RetryPolicy{
+				Strategy: models.RetryStrategyConstant,
+				Interval: models.Duration(10 * time.Hour),
+				Jitter:   0.5,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.ValidateOrdered()
+			if tc.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidRetryPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_ResolvedMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	var nilPolicy *models.RetryPolicy
+	_, ok := nilPolicy.ResolvedMaxAttempts()
+	assert.False(t, ok)
+
+	maxAttempts, ok := (&models.RetryPolicy{MaxAttempts: intPtr(2)}).ResolvedMaxAttempts()
+	assert.True(t, ok)
+	assert.Equal(t, 2, maxAttempts)
+
+	maxAttempts, ok = (&models.RetryPolicy{
+		Schedule: []models.Duration{models.Duration(time.Minute), models.Duration(time.Hour)},
+	}).ResolvedMaxAttempts()
+	assert.True(t, ok)
+	assert.Equal(t, 2, maxAttempts)
+
+	_, ok = (&models.RetryPolicy{
+		Strategy: models.RetryStrategyConstant,
+		Interval: models.Duration(time.Minute),
+	}).ResolvedMaxAttempts()
+	assert.False(t, ok)
+}
+
+func TestRetryPolicy_JSON(t *testing.T) {
+	t.Parallel()
+
+	var policy models.RetryPolicy
+	err := json.Unmarshal([]byte(`{"max_attempts":3,"schedule":["1m","5m","30m"],"jitter":0.1,"delivery_timeout":"10s"}`), &policy)
+	require.NoError(t, err)
+	assert.Equal(t, 3, *policy.MaxAttempts)
+	assert.Equal(t, []models.Duration{
+		models.Duration(time.Minute),
+		models.Duration(5 * time.Minute),
+		models.Duration(30 * time.Minute),
+	}, policy.Schedule)
+	assert.Equal(t, models.Duration(10*time.Second), policy.DeliveryTimeout)
+
+	data, err := json.Marshal(&policy)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"max_attempts":3,"schedule":["1m0s","5m0s","30m0s"],"jitter":0.1,"delivery_timeout":"10s"}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"interval":"soon"}`), &policy)
+	assert.ErrorIs(t, err, models.ErrInvalidRetryPolicy)
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmespath/go-jmespath"
+)
+
+var (
+	ErrInvalidTransformation = errors.New("validation failed: invalid transformation")
+)
+
+// Transformation reshapes an event before it's published to a destination.
+// Both fields are JMESPath expressions evaluated against an object with the
+// event "id", "topic", "time", "data" and "metadata".
+type Transformation struct {
+	// Data is an expression whose result replaces the event data.
+	Data string `json:"data,omitempty"`
+	// Metadata is an expression returning an object whose entries are merged
+	// into the event metadata, which providers map to headers or attributes.
+	Metadata string `json:"metadata,omitempty"`
+}
+
+var _ encoding.BinaryMarshaler = &Transformation{}
+var _ encoding.BinaryUnmarshaler = &Transformation{}
+
+func (t *Transformation) MarshalBinary() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (t *Transformation) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, t)
+}
+
+// IsZero returns true if the transformation doesn't define any expression.
+func (t *Transformation) IsZero() bool {
+	return t == nil || (t.Data == "" && t.Metadata == "")
+}
+
+func (t *Transformation) Validate() error {
+	if t.IsZero() {
+		return nil
+	}
+	for _, expression := range []string{t.Data, t.Metadata} {
+		if expression == "" {
+			continue
+		}
+		if _, err := jmespath.Compile(expression); err != nil {
+			return ErrInvalidTransformation
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the event with the transformation applied.
+// The original event is left untouched so that it can still be logged as is.
+func (t *Transformation) Apply(event *Event) (*Event, error) {
+	transformed := *event
+	if t.IsZero() {
+		return &transformed, nil
+	}
+
+	input := eventSearchInput(*event)
+
+	if t.Data != "" {
+		result, err := jmespath.Search(t.Data, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform data: %w", err)
+		}
+		data, ok := result.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("failed to transform data: result is not an object")
+		}
+		transformed.Data = data
+	}
+
+	if t.Metadata != "" {
+		result, err := jmespath.Search(t.Metadata, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to transform metadata: %w", err)
+		}
+		values, ok := result.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("failed to transform metadata: result is not an object")
+		}
+		metadata := make(Metadata, len(event.Metadata)+len(values))
+		for k, v := range event.Metadata {
+			metadata[k] = v
+		}
+		for k, v := range values {
+			switch val := v.(type) {
+			case nil:
+				continue
+			case string:
+				metadata[k] = val
+			default:
+				b, err := json.Marshal(val)
+				if err != nil {
+					return nil, fmt.Errorf("failed to transform metadata: %w", err)
+				}
+				metadata[k] = string(b)
+			}
+		}
+		transformed.Metadata = metadata
+	}
+
+	return &transformed, nil
+}
@@ -0,0 +1,64 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformation_Validate(t *testing.T) {
+	t.Parallel()
+
+	var empty *models.Transformation
+	assert.NoError(t, empty.Validate())
+	assert.NoError(t, (&models.Transformation{Data: "{text: topic}"}).Validate())
+	assert.NoError(t, (&models.Transformation{Metadata: "{customer: data.customer.id}"}).Validate())
+	assert.ErrorIs(t, (&models.Transformation{Data: "{text: "}).Validate(), models.ErrInvalidTransformation)
+	assert.ErrorIs(t, (&models.Transformation{Metadata: "data.["}).Validate(), models.ErrInvalidTransformation)
+}
+
+func TestTransformation_Apply(t *testing.T) {
+	t.Parallel()
+
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithID("evt_123"),
+		testutil.EventFactory.WithTopic("order.created"),
+		testutil.EventFactory.WithMetadata(map[string]string{"region": "eu"}),
+		testutil.EventFactory.WithData(map[string]interface{}{
+			"amount": float64(150),
+			"items":  []interface{}{"a", "b"},
+		}),
+	)
+
+	t.Run("reshapes data", func(t *testing.T) {
+		transformation := &models.Transformation{Data: "{event: id, total: data.amount}"}
+		transformed, err := transformation.Apply(&event)
+		require.NoError(t, err)
+		assert.Equal(t, models.Data{"event": "evt_123", "total": float64(150)}, transformed.Data)
+		assert.Equal(t, event.Metadata, transformed.Metadata)
+	})
+
+	t.Run("merges metadata", func(t *testing.T) {
+		transformation := &models.Transformation{Metadata: "{topic: topic, count: length(data.items), region: 'us', missing: data.missing}"}
+		transformed, err := transformation.Apply(&event)
+		require.NoError(t, err)
+		assert.Equal(t, models.Metadata{"topic": "order.created", "count": "2", "region": "us"}, transformed.Metadata)
+		assert.Equal(t, event.Data, transformed.Data)
+		assert.Equal(t, "eu", event.Metadata["region"])
+	})
+
+	t.Run("fails when data is not an object", func(t *testing.T) {
+		transformation := &models.Transformation{Data: "data.items"}
+		_, err := transformation.Apply(&event)
+		assert.Error(t, err)
+	})
+
+	t.Run("fails when metadata is not an object", func(t *testing.T) {
+		transformation := &models.Transformation{Metadata: "topic"}
+		_, err := transformation.Apply(&event)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,209 @@
+package ordering
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+const (
+	keyPrefixOrdering = "ordering"
+
+	// DefaultHeadTTL is how long the head of a key is kept without activity
+	// by default. Retries of ordered deliveries must be scheduled sooner.
+	DefaultHeadTTL = 24 * time.Hour
+)
+
+// Queue serializes deliveries that share an ordering key. Only the delivery
+// at the head of the queue may be processed. Later deliveries are held until
+// the head is released.
+//
+// Keys belong to a group, e.g. a destination, so that the state of every key
+// can be cleared at once.
+type Queue interface {
+	// Acquire makes the delivery the head of the key if there's no head yet,
+	// or holds it after the current head otherwise. The payload is returned
+	// by a later Acquire or Release when the delivery becomes the head.
+	//
+	// When acquired is false, next may contain the payload of an earlier
+	// delivery that became the head in its place and must be dispatched.
+	Acquire(ctx context.Context, group, key, id, payload string) (acquired bool, next string, err error)
+	// Release removes the delivery from the head of the key and returns the
+	// payload of the next held delivery, which is now the head, if any.
+	Release(ctx context.Context, group, key, id string) (next string, err error)
+	// Clear discards the head and held deliveries of every key in the group,
+	// e.g. when the destination is disabled or deleted.
+	Clear(ctx context.Context, group string) error
+}
+
+// DestinationGroup returns the group of the ordering keys of a destination.
+// Destination IDs are only unique within a tenant.
+func DestinationGroup(tenantID, destinationID string) string {
+	return tenantID + ":" + destinationID
+}
+
+// Option configures a Queue
+type Option func(q *redisQueue)
+
+// WithHeadTTL sets how long the head is kept without activity before later
+// deliveries can proceed, for example when the head is lost to a crash.
+// It should exceed the longest retry interval. Defaults to DefaultHeadTTL.
+func WithHeadTTL(ttl time.Duration) Option {
+	return func(q *redisQueue) {
+		if ttl > 0 {
+			q.headTTL = ttl
+		}
+	}
+}
+
+type redisQueue struct {
+	client  *redis.Client
+	headTTL time.Duration
+}
+
+// NewRedisQueue creates a new Redis-backed ordering queue
+func NewRedisQueue(client *redis.Client, opts ...Option) Queue {
+	q := &redisQueue{
+		client:  client,
+		headTTL: DefaultHeadTTL,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// KEYS[1]: head, the ID of the delivery being processed
+// KEYS[2]: list of held delivery IDs in arrival order
+// KEYS[3]: hash of held delivery IDs to payloads
+// KEYS[4]: set of the keys of the group with a head or held deliveries
+// ARGV: ID, payload, head TTL (ms), key
+//
+// Returns {1} when acquired, {0} when held or {0, payload} when held and an
+// earlier delivery became the head.
+var acquireScript = redis.NewScript(`
+-- Held deliveries and the group index outlive the head so that an expired
+-- head can still be replaced by the earliest held delivery
+local function touch()
+	local ttl = tonumber(ARGV[3]) * 2
+	redis.call("PEXPIRE", KEYS[2], ttl)
+	redis.call("PEXPIRE", KEYS[3], ttl)
+	redis.call("PEXPIRE", KEYS[4], ttl)
+end
+
+redis.call("SADD", KEYS[4], ARGV[4])
+
+local head = redis.call("GET", KEYS[1])
+if head == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+	touch()
+	return {1}
+end
+
+if not head and redis.call("LLEN", KEYS[2]) == 0 then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[3])
+	touch()
+	return {1}
+end
+
+if redis.call("HSETNX", KEYS[3], ARGV[1], ARGV[2]) == 1 then
+	redis.call("RPUSH", KEYS[2], ARGV[1])
+end
+touch()
+if head then
+	return {0}
+end
+
+-- The head expired while deliveries were held, promote the earliest one
+local next = redis.call("LPOP", KEYS[2])
+local payload = redis.call("HGET", KEYS[3], next)
+redis.call("HDEL", KEYS[3], next)
+redis.call("SET", KEYS[1], next, "PX", ARGV[3])
+return {0, payload}
+`)
+
+// KEYS: same as acquireScript
+// ARGV: ID, head TTL (ms), key
+//
+// Returns {} when there's no held delivery or {payload} of the new head.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return {}
+end
+
+local next = redis.call("LPOP", KEYS[2])
+if not next then
+	redis.call("DEL", KEYS[1])
+	redis.call("SREM", KEYS[4], ARGV[3])
+	return {}
+end
+
+local payload = redis.call("HGET", KEYS[3], next)
+redis.call("HDEL", KEYS[3], next)
+redis.call("SET", KEYS[1], next, "PX", ARGV[2])
+local ttl = tonumber(ARGV[2]) * 2
+redis.call("PEXPIRE", KEYS[2], ttl)
+redis.call("PEXPIRE", KEYS[3], ttl)
+redis.call("PEXPIRE", KEYS[4], ttl)
+return {payload}
+`)
+
+func (q *redisQueue) Acquire(ctx context.Context, group, key, id, payload string) (bool, string, error) {
+	result, err := acquireScript.Run(ctx, q.client, q.keys(group, key), id, payload, q.headTTL.Milliseconds(), key).Slice()
+	if err != nil {
+		return false, "", fmt.Errorf("failed to acquire ordering: %w", err)
+	}
+	if acquired, _ := result[0].(int64); acquired == 1 {
+		return true, "", nil
+	}
+	if len(result) > 1 {
+		next, _ := result[1].(string)
+		return false, next, nil
+	}
+	return false, "", nil
+}
+
+func (q *redisQueue) Release(ctx context.Context, group, key, id string) (string, error) {
+	result, err := releaseScript.Run(ctx, q.client, q.keys(group, key), id, q.headTTL.Milliseconds(), key).Slice()
+	if err != nil {
+		return "", fmt.Errorf("failed to release ordering: %w", err)
+	}
+	if len(result) == 0 {
+		return "", nil
+	}
+	next, _ := result[0].(string)
+	return next, nil
+}
+
+func (q *redisQueue) Clear(ctx context.Context, group string) error {
+	keysKey := q.groupKeysKey(group)
+	keys, err := q.client.SMembers(ctx, keysKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to clear ordering: %w", err)
+	}
+	toDelete := []string{keysKey}
+	for _, key := range keys {
+		toDelete = append(toDelete, q.keys(group, key)[:3]...)
+	}
+	if err := q.client.Del(ctx, toDelete...).Err(); err != nil {
+		return fmt.Errorf("failed to clear ordering: %w", err)
+	}
+	return nil
+}
+
+// The group is wrapped in a hash tag so that the keys of the scripts, and
+// every key of the group, map to the same slot on Redis Cluster.
+func (q *redisQueue) keys(group, key string) []string {
+	return []string{
+		fmt.Sprintf("%s:{%s}:%s:head", keyPrefixOrdering, group, key),
+		fmt.Sprintf("%s:{%s}:%s:queue", keyPrefixOrdering, group, key),
+		fmt.Sprintf("%s:{%s}:%s:payloads", keyPrefixOrdering, group, key),
+		q.groupKeysKey(group),
+	}
+}
+
+func (q *redisQueue) groupKeysKey(group string) string {
+	return fmt.Sprintf("%s:{%s}:keys", keyPrefixOrdering, group)
+}
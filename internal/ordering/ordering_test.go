@@ -0,0 +1,178 @@
+package ordering_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/ordering"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("holds later deliveries until release", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		queue := ordering.NewRedisQueue(testutil.CreateTestRedisClient(t))
+		group := uuid.New().String()
+		key := uuid.New().String()
+
+		acquired, next, err := queue.Acquire(ctx, group, key, "1", "payload_1")
+		require.NoError(t, err)
+		assert.True(t, acquired)
+		assert.Empty(t, next)
+
+		// The head can acquire again, e.g. on retry
+		acquired, _, err = queue.Acquire(ctx, group, key, "1", "payload_1")
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, _, err = queue.Acquire(ctx, group, key, "2", "payload_2")
+		require.NoError(t, err)
+		assert.False(t, acquired)
+		acquired, _, err = queue.Acquire(ctx, group, key, "3", "payload_3")
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		// Duplicates are held once
+		acquired, _, err = queue.Acquire(ctx, group, key, "2", "payload_2")
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		// Other keys aren't affected
+		acquired, _, err = queue.Acquire(ctx, group, uuid.New().String(), "4", "payload_4")
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		// Only the head can release
+		next, err = queue.Release(ctx, group, key, "2")
+		require.NoError(t, err)
+		assert.Empty(t, next)
+
+		next, err = queue.Release(ctx, group, key, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "payload_2", next)
+
+		acquired, _, err = queue.Acquire(ctx, group, key, "2", "payload_2")
+		require.NoError(t, err)
+		assert.True(t, acquired, "released delivery should be the new head")
+
+		next, err = queue.Release(ctx, group, key, "2")
+		require.NoError(t, err)
+		assert.Equal(t, "payload_3", next)
+
+		next, err = queue.Release(ctx, group, key, "3")
+		require.NoError(t, err)
+		assert.Empty(t, next)
+
+		acquired, _, err = queue.Acquire(ctx, group, key, "5", "payload_5")
+		require.NoError(t, err)
+		assert.True(t, acquired, "should acquire once the queue is drained")
+	})
+
+	t.Run("promotes the earliest held delivery when the head expires", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mr := miniredis.RunT(t)
+		t.Cleanup(func() {
+			mr.Close()
+		})
+		queue := ordering.NewRedisQueue(redis.NewClient(&redis.Options{Addr: mr.Addr()}), ordering.WithHeadTTL(time.Minute))
+		group := uuid.New().String()
+		key := uuid.New().String()
+
+		acquired, _, err := queue.Acquire(ctx, group, key, "1", "payload_1")
+		require.NoError(t, err)
+		require.True(t, acquired)
+		acquired, _, err = queue.Acquire(ctx, group, key, "2", "payload_2")
+		require.NoError(t, err)
+		require.False(t, acquired)
+
+		mr.FastForward(90 * time.Second)
+
+		acquired, next, err := queue.Acquire(ctx, group, key, "3", "payload_3")
+		require.NoError(t, err)
+		assert.False(t, acquired, "should hold behind the earlier delivery")
+		assert.Equal(t, "payload_2", next)
+
+		acquired, _, err = queue.Acquire(ctx, group, key, "2", "payload_2")
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("isolates groups", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		queue := ordering.NewRedisQueue(testutil.CreateTestRedisClient(t))
+		key := uuid.New().String()
+
+		acquired, _, err := queue.Acquire(ctx, ordering.DestinationGroup("tenant_1", "des_1"), key, "1", "payload_1")
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, _, err = queue.Acquire(ctx, ordering.DestinationGroup("tenant_2", "des_1"), key, "2", "payload_2")
+		require.NoError(t, err)
+		assert.True(t, acquired, "same destination ID of another tenant shouldn't be held")
+	})
+
+	t.Run("clears every key of the group", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		queue := ordering.NewRedisQueue(testutil.CreateTestRedisClient(t))
+		group := uuid.New().String()
+		otherGroup := uuid.New().String()
+
+		for _, g := range []string{group, otherGroup} {
+			for _, key := range []string{"a", "b"} {
+				acquired, _, err := queue.Acquire(ctx, g, key, "1", "payload_1")
+				require.NoError(t, err)
+				require.True(t, acquired)
+				acquired, _, err = queue.Acquire(ctx, g, key, "2", "payload_2")
+				require.NoError(t, err)
+				require.False(t, acquired)
+			}
+		}
+
+		require.NoError(t, queue.Clear(ctx, group))
+
+		for _, key := range []string{"a", "b"} {
+			acquired, _, err := queue.Acquire(ctx, group, key, "3", "payload_3")
+			require.NoError(t, err)
+			assert.True(t, acquired, "should acquire once the group is cleared")
+
+			acquired, _, err = queue.Acquire(ctx, otherGroup, key, "3", "payload_3")
+			require.NoError(t, err)
+			assert.False(t, acquired, "other groups shouldn't be cleared")
+		}
+
+		// Clearing an empty group is a no-op
+		require.NoError(t, queue.Clear(ctx, uuid.New().String()))
+	})
+
+	t.Run("expires held deliveries", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		mr := miniredis.RunT(t)
+		t.Cleanup(func() {
+			mr.Close()
+		})
+		queue := ordering.NewRedisQueue(redis.NewClient(&redis.Options{Addr: mr.Addr()}), ordering.WithHeadTTL(time.Minute))
+		group := uuid.New().String()
+
+		_, _, err := queue.Acquire(ctx, group, "a", "1", "payload_1")
+		require.NoError(t, err)
+		_, _, err = queue.Acquire(ctx, group, "a", "2", "payload_2")
+		require.NoError(t, err)
+		require.NotEmpty(t, mr.Keys())
+
+		mr.FastForward(3 * time.Minute)
+		assert.Empty(t, mr.Keys())
+	})
+}
@@ -0,0 +1,173 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+const (
+	keyPrefixRateLimit = "ratelimit"
+
+	defaultLeaseTTL          = 30 * time.Second
+	defaultInFlightRetryWait = time.Second
+)
+
+// Limit is the maximum rate and concurrency allowed for a key.
+// Zero values disable the corresponding limit.
+type Limit struct {
+	MaxRequestsPerSecond int
+	MaxInFlight          int
+	// LeaseTTL overrides how long the in-flight slot is held if it's never
+	// released. It should exceed the longest the delivery can take.
+	LeaseTTL time.Duration
+}
+
+// Lease holds an in-flight slot until it's released.
+type Lease struct {
+	limiter *redisLimiter
+	key     string
+	id      string
+}
+
+// Release frees the in-flight slot held by the lease.
+func (l *Lease) Release(ctx context.Context) error {
+	if l == nil || l.id == "" {
+		return nil
+	}
+	return l.limiter.client.ZRem(ctx, l.limiter.inFlightKey(l.key), l.id).Err()
+}
+
+// Limiter enforces limits shared across every process using the same Redis.
+type Limiter interface {
+	// Acquire reserves a slot for the key. When the limit is reached, it
+	// returns a nil lease and how long to wait before trying again.
+	Acquire(ctx context.Context, key string, limit Limit) (*Lease, time.Duration, error)
+}
+
+// Option configures a Limiter
+type Option func(l *redisLimiter)
+
+// WithLeaseTTL sets how long an in-flight slot is held if it's never released,
+// for example when the process crashes mid-delivery. It should exceed the
+// delivery timeout. Defaults to 30 seconds.
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(l *redisLimiter) {
+		if ttl > 0 {
+			l.leaseTTL = ttl
+		}
+	}
+}
+
+type redisLimiter struct {
+	client   *redis.Client
+	leaseTTL time.Duration
+}
+
+// NewRedisLimiter creates a new Redis-backed limiter
+func NewRedisLimiter(client *redis.Client, opts ...Option) Limiter {
+	l := &redisLimiter{
+		client:   client,
+		leaseTTL: defaultLeaseTTL,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// acquireScript checks the in-flight and per-second limits and only reserves
+// a slot when both allow it, so denied attempts don't consume capacity.
+//
+// KEYS[1]: in-flight sorted set of lease IDs scored by expiry
+// KEYS[2]: request counter of the current one-second window
+// ARGV: now (ms), lease ID, lease TTL (ms), max in-flight, max requests per second
+//
+// Returns {0, reason, excess} when denied where reason is "in_flight" or
+// "rate", or {1, ""} when allowed. Attempts denied by the rate are counted in
+// the window, excess being how far over the limit the attempt is.
+var acquireScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local maxInFlight = tonumber(ARGV[4])
+local maxRPS = tonumber(ARGV[5])
+
+if maxInFlight > 0 then
+	redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+	if redis.call("ZCARD", KEYS[1]) >= maxInFlight then
+		return {0, "in_flight"}
+	end
+end
+
+if maxRPS > 0 then
+	local count = tonumber(redis.call("GET", KEYS[2]) or "0")
+	if count >= maxRPS then
+		return {0, "rate", redis.call("INCR", KEYS[2]) - maxRPS}
+	end
+	redis.call("INCR", KEYS[2])
+	redis.call("PEXPIRE", KEYS[2], 2000)
+end
+
+if maxInFlight > 0 then
+	redis.call("ZADD", KEYS[1], now + tonumber(ARGV[3]), ARGV[2])
+	redis.call("PEXPIRE", KEYS[1], tonumber(ARGV[3]))
+end
+
+return {1, ""}
+`)
+
+func (l *redisLimiter) Acquire(ctx context.Context, key string, limit Limit) (*Lease, time.Duration, error) {
+	if limit.MaxRequestsPerSecond <= 0 && limit.MaxInFlight <= 0 {
+		return &Lease{}, 0, nil
+	}
+
+	now := time.Now()
+	leaseID := ""
+	if limit.MaxInFlight > 0 {
+		leaseID = uuid.New().String()
+	}
+	window := now.Unix()
+	leaseTTL := l.leaseTTL
+	if limit.LeaseTTL > 0 {
+		leaseTTL = limit.LeaseTTL
+	}
+
+	result, err := acquireScript.Run(ctx, l.client,
+		[]string{l.inFlightKey(key), l.windowKey(key, window)},
+		now.UnixMilli(), leaseID, leaseTTL.Milliseconds(), limit.MaxInFlight, limit.MaxRequestsPerSecond,
+	).Slice()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to acquire rate limit: %w", err)
+	}
+
+	if allowed, _ := result[0].(int64); allowed == 1 {
+		return &Lease{limiter: l, key: key, id: leaseID}, 0, nil
+	}
+	if reason, _ := result[1].(string); reason == "rate" {
+		excess, _ := result[2].(int64)
+		return nil, rateRetryWait(excess, limit.MaxRequestsPerSecond), nil
+	}
+	return nil, defaultInFlightRetryWait, nil
+}
+
+// rateRetryWait spreads the attempts denied within a window over the following
+// windows at the allowed rate, so they aren't all retried in the next second.
+// The wait is at least a second, with jitter within the window.
+func rateRetryWait(excess int64, maxRequestsPerSecond int) time.Duration {
+	rate := int64(maxRequestsPerSecond)
+	seconds := max((excess+rate-1)/rate, 1)
+	return time.Duration(seconds)*time.Second + rand.N(time.Second)
+}
+
+// The key is wrapped in a hash tag so that both keys of the acquire script
+// map to the same slot on Redis Cluster.
+func (l *redisLimiter) inFlightKey(key string) string {
+	return fmt.Sprintf("%s:{%s}:inflight", keyPrefixRateLimit, key)
+}
+
+func (l *redisLimiter) windowKey(key string, window int64) string {
+	return fmt.Sprintf("%s:{%s}:rps:%d", keyPrefixRateLimit, key, window)
+}
@@ -0,0 +1,183 @@
+package ratelimit_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/ratelimit"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no limit", func(t *testing.T) {
+		t.Parallel()
+		limiter := ratelimit.NewRedisLimiter(testutil.CreateTestRedisClient(t))
+
+		lease, wait, err := limiter.Acquire(context.Background(), uuid.New().String(), ratelimit.Limit{})
+		require.NoError(t, err)
+		require.NotNil(t, lease)
+		assert.Zero(t, wait)
+		assert.NoError(t, lease.Release(context.Background()))
+	})
+
+	t.Run("max in flight", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := ratelimit.NewRedisLimiter(testutil.CreateTestRedisClient(t))
+		key := uuid.New().String()
+		limit := ratelimit.Limit{MaxInFlight: 2}
+
+		lease1, _, err := limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		require.NotNil(t, lease1)
+		lease2, _, err := limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		require.NotNil(t, lease2)
+
+		lease3, wait, err := limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		assert.Nil(t, lease3, "should deny when all slots are in flight")
+		assert.Positive(t, wait)
+
+		// Other keys aren't affected
+		other, _, err := limiter.Acquire(ctx, uuid.New().String(), limit)
+		require.NoError(t, err)
+		assert.NotNil(t, other)
+
+		require.NoError(t, lease1.Release(ctx))
+		lease3, _, err = limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		assert.NotNil(t, lease3, "should allow once a slot is released")
+	})
+
+	t.Run("expired leases are reclaimed", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := ratelimit.NewRedisLimiter(testutil.CreateTestRedisClient(t), ratelimit.WithLeaseTTL(50*time.Millisecond))
+		key := uuid.New().String()
+		limit := ratelimit.Limit{MaxInFlight: 1}
+
+		lease, _, err := limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		require.NotNil(t, lease)
+
+		time.Sleep(100 * time.Millisecond)
+		lease, _, err = limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		assert.NotNil(t, lease, "should reclaim the slot of a lease that was never released")
+	})
+
+	t.Run("lease ttl override", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := ratelimit.NewRedisLimiter(testutil.CreateTestRedisClient(t), ratelimit.WithLeaseTTL(time.Minute))
+		key := uuid.New().String()
+		limit := ratelimit.Limit{MaxInFlight: 1, LeaseTTL: 50 * time.Millisecond}
+
+		lease, _, err := limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		require.NotNil(t, lease)
+
+		time.Sleep(100 * time.Millisecond)
+		lease, _, err = limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		assert.NotNil(t, lease, "should reclaim the slot once the limit's lease TTL elapsed")
+	})
+
+	t.Run("keys share a hash tag", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		redisClient := testutil.CreateTestRedisClient(t)
+		limiter := ratelimit.NewRedisLimiter(redisClient)
+		key := "tenant_1:destination_1"
+
+		lease, _, err := limiter.Acquire(ctx, key, ratelimit.Limit{MaxInFlight: 1, MaxRequestsPerSecond: 1})
+		require.NoError(t, err)
+		require.NotNil(t, lease)
+
+		keys, err := redisClient.Keys(ctx, "ratelimit:*").Result()
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		for _, k := range keys {
+			assert.Contains(t, k, "ratelimit:{"+key+"}:", "keys should map to the same cluster slot")
+		}
+	})
+
+	t.Run("max requests per second", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := ratelimit.NewRedisLimiter(testutil.CreateTestRedisClient(t))
+		key := uuid.New().String()
+		limit := ratelimit.Limit{MaxRequestsPerSecond: 3}
+
+		allowed := 0
+		var wait time.Duration
+		for i := 0; i < 10; i++ {
+			lease, w, err := limiter.Acquire(ctx, key, limit)
+			require.NoError(t, err)
+			if lease != nil {
+				allowed++
+			} else {
+				wait = w
+			}
+		}
+		// The window may roll over mid-loop, allowing at most one more batch
+		assert.GreaterOrEqual(t, allowed, 3)
+		assert.LessOrEqual(t, allowed, 6)
+		// The last attempt is up to 7 over the limit, retried 3 seconds later
+		assert.GreaterOrEqual(t, wait, time.Second)
+		assert.Less(t, wait, 4*time.Second)
+	})
+
+	t.Run("denied attempts are spread at the allowed rate", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := ratelimit.NewRedisLimiter(testutil.CreateTestRedisClient(t))
+		key := uuid.New().String()
+		limit := ratelimit.Limit{MaxRequestsPerSecond: 1}
+
+		var waits []time.Duration
+		for i := 0; i < 5; i++ {
+			lease, wait, err := limiter.Acquire(ctx, key, limit)
+			require.NoError(t, err)
+			if lease == nil {
+				waits = append(waits, wait)
+			}
+		}
+		// The window may roll over mid-loop, restarting the backlog once
+		require.GreaterOrEqual(t, len(waits), 3)
+		for _, wait := range waits {
+			assert.GreaterOrEqual(t, wait, time.Second, "should never retry within the same second")
+		}
+		assert.GreaterOrEqual(t, slices.Max(waits), 2*time.Second, "should retry the backlog over several seconds")
+	})
+
+	t.Run("denied in flight doesn't consume rate", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		limiter := ratelimit.NewRedisLimiter(testutil.CreateTestRedisClient(t))
+		key := uuid.New().String()
+		limit := ratelimit.Limit{MaxInFlight: 1, MaxRequestsPerSecond: 2}
+
+		lease, _, err := limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		require.NotNil(t, lease)
+		for i := 0; i < 10; i++ {
+			denied, _, err := limiter.Acquire(ctx, key, limit)
+			require.NoError(t, err)
+			assert.Nil(t, denied)
+		}
+
+		require.NoError(t, lease.Release(ctx))
+		lease, _, err = limiter.Acquire(ctx, key, limit)
+		require.NoError(t, err)
+		assert.NotNil(t, lease, "should allow as denied attempts don't count towards the rate")
+	})
+}
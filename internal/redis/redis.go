@@ -17,11 +17,16 @@ const (
 type (
 	Client             = r.Client
 	Cmdable            = r.Cmdable
+	IntCmd             = r.IntCmd
 	MapStringStringCmd = r.MapStringStringCmd
 	Pipeliner          = r.Pipeliner
 	Tx                 = r.Tx
+	Z                  = r.Z
+	Script             = r.Script
 )
 
+var NewScript = r.NewScript
+
 const (
 	TxFailedErr = r.TxFailedErr
 )
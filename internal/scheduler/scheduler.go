@@ -82,8 +82,12 @@ func (s *schedulerImpl) Schedule(ctx context.Context, task string, delay time.Du
 		opt(options)
 	}
 
-	// Convert delay to seconds and round up
+	// Convert delay to seconds, rounding to the nearest second. Positive delays
+	// wait at least a second so that they're never sent straight back.
 	delaySeconds := uint(delay.Seconds() + 0.5)
+	if delaySeconds == 0 && delay > 0 {
+		delaySeconds = 1
+	}
 
 	// Generate RSMQ ID if not provided
 	var rsmqOpts []rsmq.SendMessageOption
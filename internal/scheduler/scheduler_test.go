@@ -53,6 +53,35 @@ func TestScheduler_Basic(t *testing.T) {
 	require.Equal(t, ids[2], msgs[2])
 }
 
+func TestScheduler_SubSecondDelay(t *testing.T) {
+	t.Parallel()
+
+	redisConfig := testutil.CreateTestRedisConfig(t)
+
+	msgs := []string{}
+	exec := func(_ context.Context, id string) error {
+		msgs = append(msgs, id)
+		return nil
+	}
+
+	ctx := context.Background()
+	s := scheduler.New("scheduler", redisConfig, exec)
+	require.NoError(t, s.Init(ctx))
+	defer s.Shutdown()
+	go s.Monitor(ctx)
+
+	// Act
+	id := uuid.New().String()
+	require.NoError(t, s.Schedule(ctx, id, 200*time.Millisecond))
+
+	// Assert: the delay is rounded up to a second rather than down to none
+	time.Sleep(time.Second / 2)
+	require.Len(t, msgs, 0)
+	time.Sleep(time.Second)
+	require.Len(t, msgs, 1)
+	require.Equal(t, id, msgs[0])
+}
+
 func TestScheduler_ParallelMonitor(t *testing.T) {
 	t.Parallel()
 
@@ -215,8 +244,8 @@ func TestScheduler_CustomID(t *testing.T) {
 		// Schedule first task
 		require.NoError(t, s.Schedule(ctx, task1, 100*time.Millisecond, scheduler.WithTaskID(id)))
 
-		// Wait for first task to execute
-		time.Sleep(200 * time.Millisecond)
+		// Wait for first task to execute, sub-second delays wait a second
+		time.Sleep(1500 * time.Millisecond)
 		require.Len(t, *msgs, 1)
 		require.Equal(t, task1, (*msgs)[0])
 
@@ -224,7 +253,7 @@ func TestScheduler_CustomID(t *testing.T) {
 		require.NoError(t, s.Schedule(ctx, task2, 100*time.Millisecond, scheduler.WithTaskID(id)))
 
 		// Wait for second task to execute
-		time.Sleep(200 * time.Millisecond)
+		time.Sleep(1500 * time.Millisecond)
 		require.Len(t, *msgs, 2)
 		require.Equal(t, task2, (*msgs)[1])
 	})
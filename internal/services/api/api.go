@@ -8,8 +8,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/circuitbreaker"
 	"github.com/hookdeck/outpost/internal/config"
 	"github.com/hookdeck/outpost/internal/consumer"
+	"github.com/hookdeck/outpost/internal/deadletter"
 	"github.com/hookdeck/outpost/internal/deliverymq"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	destregistrydefault "github.com/hookdeck/outpost/internal/destregistry/providers"
@@ -17,6 +19,7 @@ import (
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/logstore"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/ordering"
 	"github.com/hookdeck/outpost/internal/publishmq"
 	"github.com/hookdeck/outpost/internal/redis"
 	"github.com/hookdeck/outpost/internal/scheduler"
@@ -97,23 +100,37 @@ func NewService(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, log
 		models.WithCipher(models.NewAESCipher(cfg.AESEncryptionSecret)),
 		models.WithAvailableTopics(cfg.Topics),
 		models.WithMaxDestinationsPerTenant(cfg.MaxDestinationsPerTenant),
+		models.WithLogger(logger),
 	)
 	eventHandler := publishmq.NewEventHandler(logger, redisClient, deliveryMQ, entityStore, eventTracer, cfg.Topics)
+	var breaker circuitbreaker.CircuitBreaker
+	if cfg.CircuitBreakerFailureThreshold > 0 {
+		breaker = circuitbreaker.NewRedisCircuitBreaker(redisClient,
+			circuitbreaker.WithFailureThreshold(cfg.CircuitBreakerFailureThreshold),
+			circuitbreaker.WithOpenTimeout(time.Duration(cfg.CircuitBreakerOpenSeconds)*time.Second),
+		)
+	}
 	router := NewRouter(
 		RouterConfig{
-			ServiceName:  cfg.OpenTelemetry.GetServiceName(),
-			APIKey:       cfg.APIKey,
-			JWTSecret:    cfg.APIJWTSecret,
-			Topics:       cfg.Topics,
-			Registry:     registry,
-			PortalConfig: cfg.GetPortalConfig(),
-			GinMode:      cfg.GinMode,
+			ServiceName:    cfg.OpenTelemetry.GetServiceName(),
+			APIKey:         cfg.APIKey,
+			JWTSecret:      cfg.APIJWTSecret,
+			Topics:         cfg.Topics,
+			Registry:       registry,
+			CircuitBreaker: breaker,
+			OrderingQueue:  ordering.NewRedisQueue(redisClient),
+			PortalConfig:   cfg.GetPortalConfig(),
+			GinMode:        cfg.GinMode,
 		},
 		logger,
 		redisClient,
 		deliveryMQ,
 		entityStore,
 		logStore,
+		deadletter.NewRedisStore(redisClient,
+			deadletter.WithRetention(time.Duration(cfg.DeadLetterRetentionDays)*24*time.Hour),
+			deadletter.WithLogger(logger),
+		),
 		eventHandler,
 		telemetry,
 	)
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/deadletter"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+type DeadLetterHandlers struct {
+	logger          *logging.Logger
+	entityStore     models.EntityStore
+	deadLetterStore deadletter.Store
+	deliveryMQ      *deliverymq.DeliveryMQ
+}
+
+func NewDeadLetterHandlers(logger *logging.Logger, entityStore models.EntityStore, deadLetterStore deadletter.Store, deliveryMQ *deliverymq.DeliveryMQ) *DeadLetterHandlers {
+	return &DeadLetterHandlers{
+		logger:          logger,
+		entityStore:     entityStore,
+		deadLetterStore: deadLetterStore,
+		deliveryMQ:      deliveryMQ,
+	}
+}
+
+type DeadLetterResponse struct {
+	ID            string                 `json:"id"`
+	TenantID      string                 `json:"tenant_id"`
+	DestinationID string                 `json:"destination_id"`
+	Attempts      int                    `json:"attempts"`
+	Provider      string                 `json:"provider"`
+	Error         map[string]interface{} `json:"error"`
+	Event         models.Event           `json:"event"`
+	Delivery      *models.Delivery       `json:"delivery"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+func toDeadLetterResponse(deadLetter deadletter.DeadLetter) DeadLetterResponse {
+	return DeadLetterResponse{
+		ID:            deadLetter.ID,
+		TenantID:      deadLetter.TenantID,
+		DestinationID: deadLetter.DestinationID,
+		Attempts:      deadLetter.Attempts,
+		Provider:      deadLetter.Provider,
+		Error:         deadLetter.Error,
+		Event:         deadLetter.DeliveryEvent.Event,
+		Delivery:      deadLetter.DeliveryEvent.Delivery,
+		CreatedAt:     deadLetter.CreatedAt,
+	}
+}
+
+func (h *DeadLetterHandlers) List(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+	if tenant == nil {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = 100
+	}
+	response, err := h.deadLetterStore.List(c.Request.Context(), deadletter.ListRequest{
+		TenantID:      tenant.ID,
+		DestinationID: c.Query("destination_id"),
+		Next:          c.Query("next"),
+		Limit:         limit,
+	})
+	if err != nil {
+		if errors.Is(err, deadletter.ErrInvalidCursor) {
+			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(err))
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	data := make([]DeadLetterResponse, len(response.Data))
+	for i, deadLetter := range response.Data {
+		data[i] = toDeadLetterResponse(deadLetter)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"next":  response.Next,
+		"count": response.Count,
+	})
+}
+
+func (h *DeadLetterHandlers) Retrieve(c *gin.Context) {
+	deadLetter := h.mustDeadLetter(c)
+	if deadLetter == nil {
+		return
+	}
+	c.JSON(http.StatusOK, toDeadLetterResponse(*deadLetter))
+}
+
+// Redeliver publishes the dead-lettered event as a new delivery event so it
+// goes through the destination's retry policy again, then discards the dead letter.
+func (h *DeadLetterHandlers) Redeliver(c *gin.Context) {
+	deadLetter := h.mustDeadLetter(c)
+	if deadLetter == nil {
+		return
+	}
+
+	destination, err := h.entityStore.RetrieveDestination(c, deadLetter.TenantID, deadLetter.DestinationID)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	if destination == nil {
+		AbortWithError(c, http.StatusNotFound, NewErrNotFound("destination"))
+		return
+	}
+	if destination.DisabledAt != nil {
+		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(ErrDestinationDisabled))
+		return
+	}
+	if destination.PendingVerification() {
+		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(ErrDestinationPendingVerification))
+		return
+	}
+
+	deliveryEvent := models.NewDeliveryEvent(deadLetter.DeliveryEvent.Event, destination.ID)
+	if err := h.deliveryMQ.Publish(c, deliveryEvent); err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	if err := h.deadLetterStore.Delete(c, deadLetter.TenantID, deadLetter.ID); err != nil && !errors.Is(err, deadletter.ErrDeadLetterNotFound) {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	h.logger.Ctx(c).Audit("dead letter redelivered",
+		zap.String("dead_letter_id", deadLetter.ID),
+		zap.String("event_id", deadLetter.EventID),
+		zap.String("destination_id", destination.ID))
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+	})
+}
+
+func (h *DeadLetterHandlers) Discard(c *gin.Context) {
+	deadLetter := h.mustDeadLetter(c)
+	if deadLetter == nil {
+		return
+	}
+	if err := h.deadLetterStore.Delete(c, deadLetter.TenantID, deadLetter.ID); err != nil {
+		if errors.Is(err, deadletter.ErrDeadLetterNotFound) {
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("dead letter"))
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	h.logger.Ctx(c).Audit("dead letter discarded",
+		zap.String("dead_letter_id", deadLetter.ID),
+		zap.String("event_id", deadLetter.EventID),
+		zap.String("destination_id", deadLetter.DestinationID))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+func (h *DeadLetterHandlers) mustDeadLetter(c *gin.Context) *deadletter.DeadLetter {
+	tenant := mustTenantFromContext(c)
+	if tenant == nil {
+		return nil
+	}
+	deadLetter, err := h.deadLetterStore.Retrieve(c.Request.Context(), tenant.ID, c.Param("deadLetterID"))
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return nil
+	}
+	if deadLetter == nil {
+		AbortWithError(c, http.StatusNotFound, NewErrNotFound("dead letter"))
+		return nil
+	}
+	return deadLetter
+}
@@ -0,0 +1,193 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/deadletter"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/mqs"
+	"github.com/hookdeck/outpost/internal/services/api"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterHandlers(t *testing.T) {
+	t.Parallel()
+
+	router, _, redisClient := setupTestRouter(t, "", "")
+	entityStore := setupTestEntityStore(t, redisClient, nil)
+	deadLetterStore := deadletter.NewRedisStore(redisClient)
+
+	serve := func(t *testing.T, router http.Handler, method, path string) (int, map[string]any) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, baseAPIPath+path, nil)
+		router.ServeHTTP(w, req)
+		var response map[string]any
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	// setup creates a tenant and destination with a dead letter
+	setup := func(t *testing.T, opts ...func(*models.Destination)) (models.Destination, deadletter.DeadLetter) {
+		t.Helper()
+		ctx := context.Background()
+		tenant := models.Tenant{ID: uuid.New().String(), CreatedAt: time.Now()}
+		require.NoError(t, entityStore.UpsertTenant(ctx, tenant))
+		destination := testutil.DestinationFactory.Any(append([]func(*models.Destination){
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+		}, opts...)...)
+		require.NoError(t, entityStore.CreateDestination(ctx, destination))
+		return destination, addDeadLetter(t, deadLetterStore, destination, time.Now())
+	}
+
+	t.Run("should scope dead letters to the tenant", func(t *testing.T) {
+		t.Parallel()
+		_, deadLetterA := setup(t)
+		destinationB, _ := setup(t)
+		tenantB := destinationB.TenantID
+
+		code, response := serve(t, router, "GET", "/"+tenantB+"/dead-letters")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, float64(1), response["count"])
+		assert.NotEqual(t, deadLetterA.ID, response["data"].([]any)[0].(map[string]any)["id"])
+
+		code, _ = serve(t, router, "GET", "/"+tenantB+"/dead-letters/"+deadLetterA.ID)
+		assert.Equal(t, http.StatusNotFound, code)
+		code, _ = serve(t, router, "POST", "/"+tenantB+"/dead-letters/"+deadLetterA.ID+"/redeliver")
+		assert.Equal(t, http.StatusNotFound, code)
+		code, _ = serve(t, router, "DELETE", "/"+tenantB+"/dead-letters/"+deadLetterA.ID)
+		assert.Equal(t, http.StatusNotFound, code)
+
+		code, response = serve(t, router, "GET", "/"+deadLetterA.TenantID+"/dead-letters/"+deadLetterA.ID)
+		assert.Equal(t, http.StatusOK, code, "should keep the dead letter of the other tenant")
+		assert.Equal(t, deadLetterA.ID, response["id"])
+	})
+
+	t.Run("should paginate with the cursor", func(t *testing.T) {
+		t.Parallel()
+		destination, first := setup(t)
+		second := addDeadLetter(t, deadLetterStore, destination, first.CreatedAt.Add(time.Second))
+		third := addDeadLetter(t, deadLetterStore, destination, first.CreatedAt.Add(2*time.Second))
+
+		code, response := serve(t, router, "GET", "/"+destination.TenantID+"/dead-letters?limit=2")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, float64(3), response["count"])
+		data := response["data"].([]any)
+		require.Len(t, data, 2)
+		assert.Equal(t, third.ID, data[0].(map[string]any)["id"])
+		assert.Equal(t, second.ID, data[1].(map[string]any)["id"])
+		next := response["next"].(string)
+		require.NotEmpty(t, next)
+
+		code, response = serve(t, router, "GET", "/"+destination.TenantID+"/dead-letters?limit=2&next="+next)
+		require.Equal(t, http.StatusOK, code)
+		data = response["data"].([]any)
+		require.Len(t, data, 1)
+		assert.Equal(t, first.ID, data[0].(map[string]any)["id"])
+		assert.Empty(t, response["next"])
+	})
+
+	t.Run("should reject an invalid cursor", func(t *testing.T) {
+		t.Parallel()
+		destination, _ := setup(t)
+
+		code, _ := serve(t, router, "GET", "/"+destination.TenantID+"/dead-letters?next=invalid")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("should redeliver and discard the dead letter", func(t *testing.T) {
+		t.Parallel()
+		destination, deadLetter := setup(t)
+
+		code, _ := serve(t, router, "POST", "/"+destination.TenantID+"/dead-letters/"+deadLetter.ID+"/redeliver")
+		assert.Equal(t, http.StatusAccepted, code)
+
+		code, _ = serve(t, router, "GET", "/"+destination.TenantID+"/dead-letters/"+deadLetter.ID)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("should not redeliver to disabled destinations", func(t *testing.T) {
+		t.Parallel()
+		destination, deadLetter := setup(t, testutil.DestinationFactory.WithDisabledAt(time.Now()))
+
+		code, _ := serve(t, router, "POST", "/"+destination.TenantID+"/dead-letters/"+deadLetter.ID+"/redeliver")
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = serve(t, router, "GET", "/"+destination.TenantID+"/dead-letters/"+deadLetter.ID)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("should not redeliver to destinations pending verification", func(t *testing.T) {
+		t.Parallel()
+		destination, deadLetter := setup(t, func(d *models.Destination) {
+			d.VerificationStatus = models.DestinationVerificationPending
+		})
+
+		code, _ := serve(t, router, "POST", "/"+destination.TenantID+"/dead-letters/"+deadLetter.ID+"/redeliver")
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = serve(t, router, "GET", "/"+destination.TenantID+"/dead-letters/"+deadLetter.ID)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("should keep the dead letter when the redelivery can't be published", func(t *testing.T) {
+		t.Parallel()
+		// A queue of its own, as in-memory topics are shared by name
+		deliveryMQ := deliverymq.New(deliverymq.WithQueue(&mqs.QueueConfig{
+			InMemory: &mqs.InMemoryConfig{Name: uuid.New().String()},
+		}))
+		shutdown, err := deliveryMQ.Init(context.Background())
+		require.NoError(t, err)
+		shutdown()
+		router, _, redisClient := setupTestRouterWithConfig(t, api.RouterConfig{}, deliveryMQ)
+		entityStore := setupTestEntityStore(t, redisClient, nil)
+		deadLetterStore := deadletter.NewRedisStore(redisClient)
+
+		ctx := context.Background()
+		tenant := models.Tenant{ID: uuid.New().String(), CreatedAt: time.Now()}
+		require.NoError(t, entityStore.UpsertTenant(ctx, tenant))
+		destination := testutil.DestinationFactory.Any(testutil.DestinationFactory.WithTenantID(tenant.ID))
+		require.NoError(t, entityStore.CreateDestination(ctx, destination))
+		deadLetter := addDeadLetter(t, deadLetterStore, destination, time.Now())
+
+		code, _ := serve(t, router, "POST", "/"+tenant.ID+"/dead-letters/"+deadLetter.ID+"/redeliver")
+		assert.Equal(t, http.StatusInternalServerError, code)
+
+		code, _ = serve(t, router, "GET", "/"+tenant.ID+"/dead-letters/"+deadLetter.ID)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("should discard the dead letter", func(t *testing.T) {
+		t.Parallel()
+		destination, deadLetter := setup(t)
+
+		code, _ := serve(t, router, "DELETE", "/"+destination.TenantID+"/dead-letters/"+deadLetter.ID)
+		assert.Equal(t, http.StatusOK, code)
+
+		code, _ = serve(t, router, "GET", "/"+destination.TenantID+"/dead-letters/"+deadLetter.ID)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func addDeadLetter(t *testing.T, store deadletter.Store, destination models.Destination, createdAt time.Time) deadletter.DeadLetter {
+	t.Helper()
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(destination.TenantID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+	deadLetter := deadletter.New(models.NewDeliveryEvent(event, destination.ID), destination.Type, map[string]interface{}{
+		"error": "publish_failed",
+	})
+	deadLetter.CreatedAt = createdAt
+	require.NoError(t, store.Add(context.Background(), deadLetter))
+	return deadLetter
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strings"
@@ -8,31 +9,51 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/circuitbreaker"
+	"github.com/hookdeck/outpost/internal/deadletter"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/ordering"
 	"github.com/hookdeck/outpost/internal/telemetry"
 	"github.com/hookdeck/outpost/internal/util/maputil"
+	"go.uber.org/zap"
 )
 
+// verificationTimeout bounds the verification handshake run when a
+// destination is saved or verification is re-triggered.
+const verificationTimeout = 10 * time.Second
+
 type DestinationHandlers struct {
-	logger      *logging.Logger
-	telemetry   telemetry.Telemetry
-	entityStore models.EntityStore
-	topics      []string
-	registry    destregistry.Registry
+	logger        *logging.Logger
+	telemetry     telemetry.Telemetry
+	entityStore   models.EntityStore
+	topics        []string
+	registry      destregistry.Registry
+	breaker       circuitbreaker.CircuitBreaker
+	deadLetters   deadletter.Store
+	orderingQueue ordering.Queue
 }
 
-func NewDestinationHandlers(logger *logging.Logger, telemetry telemetry.Telemetry, entityStore models.EntityStore, topics []string, registry destregistry.Registry) *DestinationHandlers {
+func NewDestinationHandlers(logger *logging.Logger, telemetry telemetry.Telemetry, entityStore models.EntityStore, topics []string, registry destregistry.Registry, breaker circuitbreaker.CircuitBreaker, deadLetters deadletter.Store, orderingQueue ordering.Queue) *DestinationHandlers {
 	return &DestinationHandlers{
-		logger:      logger,
-		telemetry:   telemetry,
-		entityStore: entityStore,
-		topics:      topics,
-		registry:    registry,
+		logger:        logger,
+		telemetry:     telemetry,
+		entityStore:   entityStore,
+		topics:        topics,
+		registry:      registry,
+		breaker:       breaker,
+		deadLetters:   deadLetters,
+		orderingQueue: orderingQueue,
 	}
 }
 
+// DestinationResponse is the destination display along with its circuit breaker status.
+type DestinationResponse struct {
+	*destregistry.DestinationDisplay
+	CircuitBreaker *circuitbreaker.Status `json:"circuit_breaker,omitempty"`
+}
+
 func (h *DestinationHandlers) List(c *gin.Context) {
 	typeParams := c.QueryArray("type")
 	topicsParams := c.QueryArray("topics")
@@ -56,9 +77,9 @@ func (h *DestinationHandlers) List(c *gin.Context) {
 	}
 
 	// Convert destinations to display format
-	displayDestinations := make([]*destregistry.DestinationDisplay, len(destinations))
+	displayDestinations := make([]*DestinationResponse, len(destinations))
 	for i, dest := range destinations {
-		display, err := h.registry.DisplayDestination(&dest)
+		display, err := h.displayDestination(c, &dest)
 		if err != nil {
 			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 			return
@@ -101,6 +122,12 @@ func (h *DestinationHandlers) Create(c *gin.Context) {
 		return
 	}
 	h.telemetry.DestinationCreated(c.Request.Context(), destination.Type)
+	if destination.PendingVerification() {
+		if err := h.verifyDestination(c, &destination); err != nil && !errors.Is(err, destregistry.ErrVerificationFailed) {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+	}
 
 	display, err := h.registry.DisplayDestination(&destination)
 	if err != nil {
@@ -120,7 +147,7 @@ func (h *DestinationHandlers) Retrieve(c *gin.Context) {
 		return
 	}
 
-	display, err := h.registry.DisplayDestination(destination)
+	display, err := h.displayDestination(c, destination)
 	if err != nil {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 		return
@@ -149,8 +176,40 @@ func (h *DestinationHandlers) Update(c *gin.Context) {
 	updatedDestination := *originalDestination
 
 	// Validate.
-	if input.Topics != nil {
-		updatedDestination.Topics = input.Topics
+	if input.Topics != nil || input.Filter != nil || input.Transformation != nil || input.RetryPolicy != nil || input.RateLimit != nil || input.Batch != nil || input.OrderingKey != nil {
+		if input.Topics != nil {
+			updatedDestination.Topics = input.Topics
+		}
+		if input.Filter != nil {
+			updatedDestination.Filter = *input.Filter
+		}
+		if input.Transformation != nil {
+			updatedDestination.Transformation = input.Transformation
+			if input.Transformation.IsZero() {
+				updatedDestination.Transformation = nil
+			}
+		}
+		if input.RetryPolicy != nil {
+			updatedDestination.RetryPolicy = input.RetryPolicy
+			if input.RetryPolicy.IsZero() {
+				updatedDestination.RetryPolicy = nil
+			}
+		}
+		if input.RateLimit != nil {
+			updatedDestination.RateLimit = input.RateLimit
+			if input.RateLimit.IsZero() {
+				updatedDestination.RateLimit = nil
+			}
+		}
+		if input.Batch != nil {
+			updatedDestination.Batch = input.Batch
+			if input.Batch.IsZero() {
+				updatedDestination.Batch = nil
+			}
+		}
+		if input.OrderingKey != nil {
+			updatedDestination.OrderingKey = *input.OrderingKey
+		}
 		if err := updatedDestination.Validate(h.topics); err != nil {
 			AbortWithValidationError(c, err)
 			return
@@ -169,6 +228,10 @@ func (h *DestinationHandlers) Update(c *gin.Context) {
 		shouldRevalidate = true
 		updatedDestination.Credentials = maputil.MergeStringMaps(originalDestination.Credentials, input.Credentials)
 	}
+	if input.Batch != nil {
+		// Batching depends on the destination type
+		shouldRevalidate = true
+	}
 
 	// Always preprocess before updating
 	if err := h.registry.PreprocessDestination(&updatedDestination, originalDestination, &destregistry.PreprocessDestinationOpts{
@@ -190,6 +253,12 @@ func (h *DestinationHandlers) Update(c *gin.Context) {
 		h.handleUpsertDestinationError(c, err)
 		return
 	}
+	if updatedDestination.PendingVerification() {
+		if err := h.verifyDestination(c, &updatedDestination); err != nil && !errors.Is(err, destregistry.ErrVerificationFailed) {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+	}
 
 	display, err := h.registry.DisplayDestination(&updatedDestination)
 	if err != nil {
@@ -212,6 +281,17 @@ func (h *DestinationHandlers) Delete(c *gin.Context) {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 		return
 	}
+	// The destination is gone at this point, so leftover dead letters are
+	// logged rather than failing the request. They still expire on their own.
+	if h.deadLetters != nil {
+		if err := h.deadLetters.DeleteByDestination(c.Request.Context(), destination.TenantID, destination.ID); err != nil {
+			h.logger.Ctx(c.Request.Context()).Error("failed to delete destination dead letters",
+				zap.Error(err),
+				zap.String("tenant_id", destination.TenantID),
+				zap.String("destination_id", destination.ID))
+		}
+	}
+	h.clearOrdering(c, destination)
 
 	display, err := h.registry.DisplayDestination(destination)
 	if err != nil {
@@ -229,11 +309,71 @@ func (h *DestinationHandlers) Enable(c *gin.Context) {
 	h.setDisabilityHandler(c, false)
 }
 
+// Verify re-triggers the verification handshake of a destination. Pending
+// destinations start receiving events once it passes.
+func (h *DestinationHandlers) Verify(c *gin.Context) {
+	tenantID := mustTenantIDFromContext(c)
+	if tenantID == "" {
+		return
+	}
+	destination := h.mustRetrieveDestination(c, tenantID, c.Param("destinationID"))
+	if destination == nil {
+		return
+	}
+
+	if err := h.verifyDestination(c, destination); err != nil {
+		if errors.Is(err, destregistry.ErrVerificationFailed) {
+			AbortWithError(c, http.StatusUnprocessableEntity, ErrorResponse{
+				Code:    http.StatusUnprocessableEntity,
+				Message: err.Error(),
+			})
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	display, err := h.displayDestination(c, destination)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, display)
+}
+
 func (h *DestinationHandlers) ListProviderMetadata(c *gin.Context) {
 	metadata := h.registry.ListProviderMetadata()
 	c.JSON(http.StatusOK, metadata)
 }
 
+// RetrieveKeySet returns the public keys deliveries to the destination are
+// signed with, in the JWKS format.
+func (h *DestinationHandlers) RetrieveKeySet(c *gin.Context) {
+	tenantID := mustTenantIDFromContext(c)
+	if tenantID == "" {
+		return
+	}
+	destination := h.mustRetrieveDestination(c, tenantID, c.Param("destinationID"))
+	if destination == nil {
+		return
+	}
+
+	keys := []destregistry.JWK{}
+	provider, err := h.registry.ResolveProvider(destination)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	if keySetProvider, ok := provider.(destregistry.KeySetProvider); ok {
+		keys, err = keySetProvider.PublicKeys(destination)
+		if err != nil {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
 func (h *DestinationHandlers) RetrieveProviderMetadata(c *gin.Context) {
 	providerType := c.Param("type")
 	metadata, err := h.registry.RetrieveProviderMetadata(providerType)
@@ -244,6 +384,55 @@ func (h *DestinationHandlers) RetrieveProviderMetadata(c *gin.Context) {
 	c.JSON(http.StatusOK, metadata)
 }
 
+func (h *DestinationHandlers) displayDestination(c *gin.Context, destination *models.Destination) (*DestinationResponse, error) {
+	display, err := h.registry.DisplayDestination(destination)
+	if err != nil {
+		return nil, err
+	}
+	response := &DestinationResponse{DestinationDisplay: display}
+	if h.breaker != nil {
+		status, err := h.breaker.Status(c.Request.Context(), destination.TenantID, destination.ID)
+		if err != nil {
+			return nil, err
+		}
+		response.CircuitBreaker = status
+	}
+	return response, nil
+}
+
+// verifyDestination runs the verification handshake of the destination and
+// marks it as verified once it passes. The destination stays pending if the
+// endpoint fails the check, which is returned as ErrVerificationFailed.
+// Destinations are left as is when their provider doesn't require verification.
+func (h *DestinationHandlers) verifyDestination(c *gin.Context, destination *models.Destination) error {
+	provider, err := h.registry.ResolveProvider(destination)
+	if err != nil {
+		return err
+	}
+	verifiableProvider, ok := provider.(destregistry.VerifiableProvider)
+	if !ok || !verifiableProvider.RequiresVerification() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), verificationTimeout)
+	defer cancel()
+	if err := verifiableProvider.Verify(ctx, destination); err != nil {
+		h.logger.Ctx(c.Request.Context()).Info("destination verification failed",
+			zap.String("tenant_id", destination.TenantID),
+			zap.String("destination_id", destination.ID),
+			zap.Error(err))
+		return err
+	}
+
+	if destination.VerificationStatus == models.DestinationVerificationVerified {
+		return nil
+	}
+	now := time.Now()
+	destination.VerificationStatus = models.DestinationVerificationVerified
+	destination.VerifiedAt = &now
+	return h.entityStore.UpsertDestination(c.Request.Context(), *destination)
+}
+
 func (h *DestinationHandlers) setDisabilityHandler(c *gin.Context, disabled bool) {
 	tenantID := mustTenantIDFromContext(c)
 	if tenantID == "" {
@@ -268,6 +457,9 @@ func (h *DestinationHandlers) setDisabilityHandler(c *gin.Context, disabled bool
 			h.handleUpsertDestinationError(c, err)
 			return
 		}
+		if disabled {
+			h.clearOrdering(c, destination)
+		}
 	}
 
 	display, err := h.registry.DisplayDestination(destination)
@@ -278,6 +470,21 @@ func (h *DestinationHandlers) setDisabilityHandler(c *gin.Context, disabled bool
 	c.JSON(http.StatusOK, display)
 }
 
+// clearOrdering discards the deliveries held by the destination's ordering
+// keys once it no longer receives events. Failures are logged, the held
+// deliveries are released when their head expires.
+func (h *DestinationHandlers) clearOrdering(c *gin.Context, destination *models.Destination) {
+	if h.orderingQueue == nil {
+		return
+	}
+	if err := h.orderingQueue.Clear(c.Request.Context(), ordering.DestinationGroup(destination.TenantID, destination.ID)); err != nil {
+		h.logger.Ctx(c.Request.Context()).Error("failed to clear destination ordering",
+			zap.Error(err),
+			zap.String("tenant_id", destination.TenantID),
+			zap.String("destination_id", destination.ID))
+	}
+}
+
 func (h *DestinationHandlers) mustRetrieveDestination(c *gin.Context, tenantID, destinationID string) *models.Destination {
 	destination, err := h.entityStore.RetrieveDestination(c.Request.Context(), tenantID, destinationID)
 	if err != nil {
@@ -310,11 +517,17 @@ func (h *DestinationHandlers) handleUpsertDestinationError(c *gin.Context, err e
 // ===== Requests =====
 
 type CreateDestinationRequest struct {
-	ID          string             `json:"id" binding:"-"`
-	Type        string             `json:"type" binding:"required"`
-	Topics      models.Topics      `json:"topics" binding:"required"`
-	Config      models.Config      `json:"config" binding:"-"`
-	Credentials models.Credentials `json:"credentials" binding:"-"`
+	ID             string                 `json:"id" binding:"-"`
+	Type           string                 `json:"type" binding:"required"`
+	Topics         models.Topics          `json:"topics" binding:"required"`
+	Filter         string                 `json:"filter" binding:"-"`
+	Transformation *models.Transformation `json:"transformation" binding:"-"`
+	RetryPolicy    *models.RetryPolicy    `json:"retry_policy" binding:"-"`
+	RateLimit      *models.RateLimit      `json:"rate_limit" binding:"-"`
+	Batch          *models.Batch          `json:"batch" binding:"-"`
+	OrderingKey    string                 `json:"ordering_key" binding:"-"`
+	Config         models.Config          `json:"config" binding:"-"`
+	Credentials    models.Credentials     `json:"credentials" binding:"-"`
 }
 
 func (r *CreateDestinationRequest) ToDestination(tenantID string) models.Destination {
@@ -327,24 +540,52 @@ func (r *CreateDestinationRequest) ToDestination(tenantID string) models.Destina
 	if r.Credentials == nil {
 		r.Credentials = make(map[string]string)
 	}
+	transformation := r.Transformation
+	if transformation.IsZero() {
+		transformation = nil
+	}
+	retryPolicy := r.RetryPolicy
+	if retryPolicy.IsZero() {
+		retryPolicy = nil
+	}
+	rateLimit := r.RateLimit
+	if rateLimit.IsZero() {
+		rateLimit = nil
+	}
+	batch := r.Batch
+	if batch.IsZero() {
+		batch = nil
+	}
 
 	return models.Destination{
-		ID:          r.ID,
-		Type:        r.Type,
-		Topics:      r.Topics,
-		Config:      r.Config,
-		Credentials: r.Credentials,
-		CreatedAt:   time.Now(),
-		DisabledAt:  nil,
-		TenantID:    tenantID,
+		ID:             r.ID,
+		Type:           r.Type,
+		Topics:         r.Topics,
+		Filter:         r.Filter,
+		Transformation: transformation,
+		RetryPolicy:    retryPolicy,
+		RateLimit:      rateLimit,
+		Batch:          batch,
+		OrderingKey:    r.OrderingKey,
+		Config:         r.Config,
+		Credentials:    r.Credentials,
+		CreatedAt:      time.Now(),
+		DisabledAt:     nil,
+		TenantID:       tenantID,
 	}
 }
 
 type UpdateDestinationRequest struct {
-	Type        string             `json:"type" binding:"-"`
-	Topics      models.Topics      `json:"topics" binding:"-"`
-	Config      models.Config      `json:"config" binding:"-"`
-	Credentials models.Credentials `json:"credentials" binding:"-"`
+	Type           string                 `json:"type" binding:"-"`
+	Topics         models.Topics          `json:"topics" binding:"-"`
+	Filter         *string                `json:"filter" binding:"-"`
+	Transformation *models.Transformation `json:"transformation" binding:"-"`
+	RetryPolicy    *models.RetryPolicy    `json:"retry_policy" binding:"-"`
+	RateLimit      *models.RateLimit      `json:"rate_limit" binding:"-"`
+	Batch          *models.Batch          `json:"batch" binding:"-"`
+	OrderingKey    *string                `json:"ordering_key" binding:"-"`
+	Config         models.Config          `json:"config" binding:"-"`
+	Credentials    models.Credentials     `json:"credentials" binding:"-"`
 }
 
 func mustRoleFromContext(c *gin.Context) string {
@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	destregistrydefault "github.com/hookdeck/outpost/internal/destregistry/providers"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/services/api"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestinationVerification(t *testing.T) {
+	t.Parallel()
+
+	registry := destregistry.NewRegistry(&destregistry.Config{}, testutil.CreateTestLogger(t))
+	require.NoError(t, destregistrydefault.RegisterDefault(registry, destregistrydefault.RegisterDefaultDestinationOptions{
+		Webhook: &destregistrydefault.DestWebhookConfig{VerificationMethod: "post"},
+	}))
+	router, _, _ := setupTestRouterWithConfig(t, api.RouterConfig{Registry: registry}, nil)
+
+	// The endpoint echoes the challenge of verification requests to /echo.
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/echo" {
+			w.Write([]byte("ok"))
+			return
+		}
+		var body destwebhook.VerificationRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(body.Challenge))
+	}))
+	t.Cleanup(server.Close)
+
+	createTenant := func(t *testing.T) string {
+		t.Helper()
+		tenantID := uuid.New().String()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", baseAPIPath+"/"+tenantID, nil)
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+		return tenantID
+	}
+
+	serve := func(t *testing.T, method, path, body string) (int, map[string]any) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, baseAPIPath+path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var response map[string]any
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	t.Run("should verify destinations on create", func(t *testing.T) {
+		tenantID := createTenant(t)
+		before := requests.Load()
+
+		code, response := serve(t, "POST", "/"+tenantID+"/destinations",
+			`{"type":"webhook","topics":"*","config":{"url":"`+server.URL+`/echo"}}`)
+
+		assert.Equal(t, http.StatusCreated, code)
+		assert.Equal(t, models.DestinationVerificationVerified, response["verification_status"])
+		assert.Equal(t, int32(1), requests.Load()-before)
+	})
+
+	t.Run("should stay pending when the endpoint fails the check", func(t *testing.T) {
+		tenantID := createTenant(t)
+
+		code, response := serve(t, "POST", "/"+tenantID+"/destinations",
+			`{"type":"webhook","topics":"*","config":{"url":"`+server.URL+`/wrong"}}`)
+		require.Equal(t, http.StatusCreated, code)
+		assert.Equal(t, models.DestinationVerificationPending, response["verification_status"])
+
+		code, _ = serve(t, "PUT", "/"+tenantID+"/destinations/"+response["id"].(string)+"/verify", "")
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("should verify destinations when their URL changes", func(t *testing.T) {
+		tenantID := createTenant(t)
+		code, response := serve(t, "POST", "/"+tenantID+"/destinations",
+			`{"type":"webhook","topics":"*","config":{"url":"`+server.URL+`/wrong"}}`)
+		require.Equal(t, http.StatusCreated, code)
+		before := requests.Load()
+
+		code, response = serve(t, "PATCH", "/"+tenantID+"/destinations/"+response["id"].(string),
+			`{"config":{"url":"`+server.URL+`/echo"}}`)
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.DestinationVerificationVerified, response["verification_status"])
+		assert.Equal(t, int32(1), requests.Load()-before)
+	})
+}
@@ -13,7 +13,8 @@ import (
 )
 
 var (
-	ErrDestinationDisabled = errors.New("destination is disabled")
+	ErrDestinationDisabled            = errors.New("destination is disabled")
+	ErrDestinationPendingVerification = errors.New("destination is pending verification")
 )
 
 type RetryHandlers struct {
@@ -51,6 +52,10 @@ func (h *RetryHandlers) Retry(c *gin.Context) {
 		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(ErrDestinationDisabled))
 		return
 	}
+	if destination.PendingVerification() {
+		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(ErrDestinationPendingVerification))
+		return
+	}
 
 	event, err := h.logStore.RetrieveEvent(c, tenantID, eventID)
 	if err != nil {
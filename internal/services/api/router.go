@@ -9,11 +9,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/hookdeck/outpost/internal/circuitbreaker"
+	"github.com/hookdeck/outpost/internal/deadletter"
 	"github.com/hookdeck/outpost/internal/deliverymq"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/logstore"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/ordering"
 	"github.com/hookdeck/outpost/internal/portal"
 	"github.com/hookdeck/outpost/internal/publishmq"
 	"github.com/hookdeck/outpost/internal/redis"
@@ -47,13 +50,15 @@ type RouteDefinition struct {
 }
 
 type RouterConfig struct {
-	ServiceName  string
-	APIKey       string
-	JWTSecret    string
-	Topics       []string
-	Registry     destregistry.Registry
-	PortalConfig portal.PortalConfig
-	GinMode      string
+	ServiceName    string
+	APIKey         string
+	JWTSecret      string
+	Topics         []string
+	Registry       destregistry.Registry
+	CircuitBreaker circuitbreaker.CircuitBreaker
+	OrderingQueue  ordering.Queue
+	PortalConfig   portal.PortalConfig
+	GinMode        string
 }
 
 type routeDefinition struct {
@@ -115,6 +120,7 @@ func NewRouter(
 	deliveryMQ *deliverymq.DeliveryMQ,
 	entityStore models.EntityStore,
 	logStore logstore.LogStore,
+	deadLetterStore deadletter.Store,
 	publishmqEventHandler publishmq.EventHandler,
 	telemetry telemetry.Telemetry,
 ) http.Handler {
@@ -159,10 +165,11 @@ func NewRouter(
 	})
 
 	tenantHandlers := NewTenantHandlers(logger, telemetry, cfg.JWTSecret, entityStore)
-	destinationHandlers := NewDestinationHandlers(logger, telemetry, entityStore, cfg.Topics, cfg.Registry)
+	destinationHandlers := NewDestinationHandlers(logger, telemetry, entityStore, cfg.Topics, cfg.Registry, cfg.CircuitBreaker, deadLetterStore, cfg.OrderingQueue)
 	publishHandlers := NewPublishHandlers(logger, publishmqEventHandler)
 	retryHandlers := NewRetryHandlers(logger, entityStore, logStore, deliveryMQ)
 	logHandlers := NewLogHandlers(logger, logStore)
+	deadLetterHandlers := NewDeadLetterHandlers(logger, entityStore, deadLetterStore, deliveryMQ)
 	topicHandlers := NewTopicHandlers(logger, cfg.Topics)
 
 	// Admin routes
@@ -321,6 +328,17 @@ func NewRouter(
 				RequireTenantMiddleware(entityStore),
 			},
 		},
+		{
+			Method:             http.MethodGet,
+			Path:               "/:tenantID/destinations/:destinationID/jwks",
+			Handler:            destinationHandlers.RetrieveKeySet,
+			AuthScope:          AuthScopeAdminOrTenant,
+			Mode:               RouteModeAlways,
+			AllowTenantFromJWT: true,
+			Middlewares: []gin.HandlerFunc{
+				RequireTenantMiddleware(entityStore),
+			},
+		},
 		{
 			Method:             http.MethodPut,
 			Path:               "/:tenantID/destinations/:destinationID/enable",
@@ -332,6 +350,17 @@ func NewRouter(
 				RequireTenantMiddleware(entityStore),
 			},
 		},
+		{
+			Method:             http.MethodPut,
+			Path:               "/:tenantID/destinations/:destinationID/verify",
+			Handler:            destinationHandlers.Verify,
+			AuthScope:          AuthScopeAdminOrTenant,
+			Mode:               RouteModeAlways,
+			AllowTenantFromJWT: true,
+			Middlewares: []gin.HandlerFunc{
+				RequireTenantMiddleware(entityStore),
+			},
+		},
 		{
 			Method:             http.MethodPut,
 			Path:               "/:tenantID/destinations/:destinationID/disable",
@@ -410,6 +439,52 @@ func NewRouter(
 			Mode:               RouteModeAlways,
 			AllowTenantFromJWT: true,
 		},
+
+		// Dead-letter routes
+		{
+			Method:             http.MethodGet,
+			Path:               "/:tenantID/dead-letters",
+			Handler:            deadLetterHandlers.List,
+			AuthScope:          AuthScopeAdminOrTenant,
+			Mode:               RouteModeAlways,
+			AllowTenantFromJWT: true,
+			Middlewares: []gin.HandlerFunc{
+				RequireTenantMiddleware(entityStore),
+			},
+		},
+		{
+			Method:             http.MethodGet,
+			Path:               "/:tenantID/dead-letters/:deadLetterID",
+			Handler:            deadLetterHandlers.Retrieve,
+			AuthScope:          AuthScopeAdminOrTenant,
+			Mode:               RouteModeAlways,
+			AllowTenantFromJWT: true,
+			Middlewares: []gin.HandlerFunc{
+				RequireTenantMiddleware(entityStore),
+			},
+		},
+		{
+			Method:             http.MethodPost,
+			Path:               "/:tenantID/dead-letters/:deadLetterID/redeliver",
+			Handler:            deadLetterHandlers.Redeliver,
+			AuthScope:          AuthScopeAdminOrTenant,
+			Mode:               RouteModeAlways,
+			AllowTenantFromJWT: true,
+			Middlewares: []gin.HandlerFunc{
+				RequireTenantMiddleware(entityStore),
+			},
+		},
+		{
+			Method:             http.MethodDelete,
+			Path:               "/:tenantID/dead-letters/:deadLetterID",
+			Handler:            deadLetterHandlers.Discard,
+			AuthScope:          AuthScopeAdminOrTenant,
+			Mode:               RouteModeAlways,
+			AllowTenantFromJWT: true,
+			Middlewares: []gin.HandlerFunc{
+				RequireTenantMiddleware(entityStore),
+			},
+		},
 	}
 
 	// Register all routes to a single router
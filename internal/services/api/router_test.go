@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/hookdeck/outpost/internal/clickhouse"
+	"github.com/hookdeck/outpost/internal/deadletter"
 	"github.com/hookdeck/outpost/internal/deliverymq"
 	"github.com/hookdeck/outpost/internal/eventtracer"
 	"github.com/hookdeck/outpost/internal/logging"
@@ -32,27 +33,38 @@ func testRouterWithCHDB(t *testing.T, config *clickhouse.ClickHouseConfig) click
 }
 
 func setupTestRouter(t *testing.T, apiKey, jwtSecret string, funcs ...func(t *testing.T) clickhouse.DB) (http.Handler, *logging.Logger, *redis.Client) {
+	return setupTestRouterWithConfig(t, api.RouterConfig{
+		APIKey:    apiKey,
+		JWTSecret: jwtSecret,
+	}, nil, funcs...)
+}
+
+// setupTestRouterWithConfig sets up a router with the given config. The test
+// topics are used unless the config has its own, and an in-memory delivery
+// queue unless deliveryMQ is set.
+func setupTestRouterWithConfig(t *testing.T, cfg api.RouterConfig, deliveryMQ *deliverymq.DeliveryMQ, funcs ...func(t *testing.T) clickhouse.DB) (http.Handler, *logging.Logger, *redis.Client) {
 	gin.SetMode(gin.TestMode)
+	if cfg.Topics == nil {
+		cfg.Topics = testutil.TestTopics
+	}
 	logger := testutil.CreateTestLogger(t)
 	redisClient := testutil.CreateTestRedisClient(t)
-	deliveryMQ := deliverymq.New()
-	deliveryMQ.Init(context.Background())
+	if deliveryMQ == nil {
+		deliveryMQ = deliverymq.New()
+		deliveryMQ.Init(context.Background())
+	}
 	eventTracer := eventtracer.NewNoopEventTracer()
 	entityStore := setupTestEntityStore(t, redisClient, nil)
 	logStore := setupTestLogStore(t, funcs...)
-	eventHandler := publishmq.NewEventHandler(logger, redisClient, deliveryMQ, entityStore, eventTracer, testutil.TestTopics)
+	eventHandler := publishmq.NewEventHandler(logger, redisClient, deliveryMQ, entityStore, eventTracer, cfg.Topics)
 	router := api.NewRouter(
-		api.RouterConfig{
-			ServiceName: "",
-			APIKey:      apiKey,
-			JWTSecret:   jwtSecret,
-			Topics:      testutil.TestTopics,
-		},
+		cfg,
 		logger,
 		redisClient,
 		deliveryMQ,
 		entityStore,
 		logStore,
+		deadletter.NewRedisStore(redisClient),
 		eventHandler,
 		&telemetry.NoopTelemetry{},
 	)
@@ -8,8 +8,10 @@ import (
 
 	"github.com/hookdeck/outpost/internal/alert"
 	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/circuitbreaker"
 	"github.com/hookdeck/outpost/internal/config"
 	"github.com/hookdeck/outpost/internal/consumer"
+	"github.com/hookdeck/outpost/internal/deadletter"
 	"github.com/hookdeck/outpost/internal/deliverymq"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	destregistrydefault "github.com/hookdeck/outpost/internal/destregistry/providers"
@@ -18,6 +20,8 @@ import (
 	"github.com/hookdeck/outpost/internal/logmq"
 	"github.com/hookdeck/outpost/internal/logstore"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/ordering"
+	"github.com/hookdeck/outpost/internal/ratelimit"
 	"github.com/hookdeck/outpost/internal/redis"
 	"go.uber.org/zap"
 	_ "gocloud.dev/pubsub/mempubsub"
@@ -93,6 +97,7 @@ func NewService(ctx context.Context,
 			models.WithCipher(models.NewAESCipher(cfg.AESEncryptionSecret)),
 			models.WithAvailableTopics(cfg.Topics),
 			models.WithMaxDestinationsPerTenant(cfg.MaxDestinationsPerTenant),
+			models.WithLogger(logger),
 		)
 
 		logstoreDriverOpts, err := logstore.MakeDriverOpts(logstore.Config{
@@ -118,13 +123,15 @@ func NewService(ctx context.Context,
 			retryScheduler.Shutdown()
 		})
 
+		orderingQueue := ordering.NewRedisQueue(redisClient)
+
 		var alertNotifier alert.AlertNotifier
 		var destinationDisabler alert.DestinationDisabler
 		if cfg.Alert.CallbackURL != "" {
 			alertNotifier = alert.NewHTTPAlertNotifier(cfg.Alert.CallbackURL, alert.NotifierWithBearerToken(cfg.APIKey))
 		}
 		if cfg.Alert.AutoDisableDestination {
-			destinationDisabler = newDestinationDisabler(entityStore)
+			destinationDisabler = newDestinationDisabler(entityStore, orderingQueue)
 		}
 		alertMonitor := alert.NewAlertMonitor(
 			logger,
@@ -134,6 +141,42 @@ func NewService(ctx context.Context,
 			alert.WithAutoDisableFailureCount(cfg.Alert.ConsecutiveFailureCount),
 		)
 
+		deadLetterOpts := []deadletter.Option{
+			deadletter.WithRetention(time.Duration(cfg.DeadLetterRetentionDays) * 24 * time.Hour),
+			deadletter.WithLogger(logger),
+		}
+		if alertNotifier != nil && cfg.Alert.DeadLetter {
+			deadLetterOpts = append(deadLetterOpts, deadletter.WithNotifier(alertNotifier))
+		}
+		deadLetterStore := deadletter.NewRedisStore(redisClient, deadLetterOpts...)
+
+		// In-flight slots are released after each delivery. The lease TTL only
+		// reclaims slots of deliveries that never finished, e.g. on a crash.
+		// The message handler sizes it per destination from the delivery timeout
+		// that applies, this is the fallback.
+		rateLimiter := ratelimit.NewRedisLimiter(redisClient,
+			ratelimit.WithLeaseTTL(2*time.Duration(cfg.DeliveryTimeoutSeconds)*time.Second),
+		)
+
+		handlerOpts := []deliverymq.MessageHandlerOption{
+			deliverymq.WithDeadLetterStore(deadLetterStore),
+			deliverymq.WithRateLimiter(rateLimiter),
+			deliverymq.WithOrderingQueue(orderingQueue),
+			deliverymq.WithMaxRetryAfter(time.Duration(cfg.RetryAfterMaxSeconds) * time.Second),
+			deliverymq.WithDeliveryTimeout(time.Duration(cfg.DeliveryTimeoutSeconds) * time.Second),
+		}
+		if alertNotifier != nil && cfg.Alert.CertificateExpiryDays > 0 {
+			handlerOpts = append(handlerOpts, deliverymq.WithCertificateMonitor(newCertificateMonitor(registry,
+				alert.NewCertificateExpiryMonitor(redisClient, alertNotifier, time.Duration(cfg.Alert.CertificateExpiryDays)*24*time.Hour),
+			)))
+		}
+		if cfg.CircuitBreakerFailureThreshold > 0 {
+			handlerOpts = append(handlerOpts, deliverymq.WithCircuitBreaker(circuitbreaker.NewRedisCircuitBreaker(redisClient,
+				circuitbreaker.WithFailureThreshold(cfg.CircuitBreakerFailureThreshold),
+				circuitbreaker.WithOpenTimeout(time.Duration(cfg.CircuitBreakerOpenSeconds)*time.Second),
+			)))
+		}
+
 		handler = deliverymq.NewMessageHandler(
 			logger,
 			redisClient,
@@ -149,6 +192,7 @@ func NewService(ctx context.Context,
 			},
 			cfg.RetryMaxLimit,
 			alertMonitor,
+			handlerOpts...,
 		)
 	}
 
@@ -203,12 +247,14 @@ func (s *DeliveryService) Shutdown(ctx context.Context) {
 }
 
 type destinationDisabler struct {
-	entityStore models.EntityStore
+	entityStore   models.EntityStore
+	orderingQueue ordering.Queue
 }
 
-func newDestinationDisabler(entityStore models.EntityStore) alert.DestinationDisabler {
+func newDestinationDisabler(entityStore models.EntityStore, orderingQueue ordering.Queue) alert.DestinationDisabler {
 	return &destinationDisabler{
-		entityStore: entityStore,
+		entityStore:   entityStore,
+		orderingQueue: orderingQueue,
 	}
 }
 
@@ -222,5 +268,45 @@ func (d *destinationDisabler) DisableDestination(ctx context.Context, tenantID,
 	}
 	now := time.Now()
 	destination.DisabledAt = &now
-	return d.entityStore.UpsertDestination(ctx, *destination)
+	if err := d.entityStore.UpsertDestination(ctx, *destination); err != nil {
+		return err
+	}
+	// Held deliveries would otherwise wait for the head to expire
+	return d.orderingQueue.Clear(ctx, ordering.DestinationGroup(tenantID, destinationID))
+}
+
+type certificateMonitor struct {
+	registry destregistry.Registry
+	monitor  alert.CertificateExpiryMonitor
+}
+
+func newCertificateMonitor(registry destregistry.Registry, monitor alert.CertificateExpiryMonitor) deliverymq.CertificateMonitor {
+	return &certificateMonitor{
+		registry: registry,
+		monitor:  monitor,
+	}
+}
+
+func (m *certificateMonitor) Check(ctx context.Context, destination *models.Destination) error {
+	provider, err := m.registry.ResolveProvider(destination)
+	if err != nil {
+		return err
+	}
+	certProvider, ok := provider.(destregistry.CertificateExpiryProvider)
+	if !ok {
+		return nil
+	}
+	expiresAt, err := certProvider.CertificateExpiry(destination)
+	if err != nil || expiresAt == nil {
+		return err
+	}
+	return m.monitor.Check(ctx, &alert.AlertDestination{
+		ID:         destination.ID,
+		TenantID:   destination.TenantID,
+		Type:       destination.Type,
+		Topics:     destination.Topics,
+		Config:     destination.Config,
+		CreatedAt:  destination.CreatedAt,
+		DisabledAt: destination.DisabledAt,
+	}, *expiresAt)
 }
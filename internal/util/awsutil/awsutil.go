@@ -3,7 +3,10 @@ package awsutil
 import (
 	"context"
 	"errors"
+	"time"
 
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
@@ -99,3 +102,18 @@ func DeleteQueue(ctx context.Context, sqsClient *sqs.Client, queueURL string) er
 	})
 	return err
 }
+
+// throttlingRetryAfter is how long to wait before retrying a request throttled
+// by AWS. AWS doesn't tell when to retry, and the SDK already retried the
+// request with a short backoff.
+const throttlingRetryAfter = 30 * time.Second
+
+// ThrottlingRetryAfter returns how long to wait before retrying the request if
+// AWS throttled it, e.g. with a ThrottlingException or
+// ProvisionedThroughputExceededException, or zero for other errors.
+func ThrottlingRetryAfter(err error) time.Duration {
+	if retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == awssdk.TrueTernary {
+		return throttlingRetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package awsutil_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	kinesistypes "github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/aws/smithy-go"
+	"github.com/hookdeck/outpost/internal/util/awsutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottlingRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		throttled bool
+	}{
+		{name: "throttling exception", err: &smithy.GenericAPIError{Code: "ThrottlingException"}, throttled: true},
+		{name: "sqs request throttled", err: &smithy.GenericAPIError{Code: "RequestThrottled"}, throttled: true},
+		{name: "kinesis throughput exceeded", err: &kinesistypes.ProvisionedThroughputExceededException{}, throttled: true},
+		{name: "access denied", err: &smithy.GenericAPIError{Code: "AccessDeniedException"}},
+		{name: "other error", err: errors.New("connection refused")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			retryAfter := awsutil.ThrottlingRetryAfter(tt.err)
+			if tt.throttled {
+				assert.Equal(t, 30*time.Second, retryAfter)
+			} else {
+				assert.Zero(t, retryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package testinfra
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go/modules/kafka"
+)
+
+var kafkaOnce sync.Once
+
+// EnsureKafka returns the address of a single-broker Kafka cluster.
+func EnsureKafka() string {
+	cfg := ReadConfig()
+	if cfg.KafkaURL == "" {
+		kafkaOnce.Do(func() {
+			startKafkaTestContainer(cfg)
+		})
+	}
+	return cfg.KafkaURL
+}
+
+func startKafkaTestContainer(cfg *Config) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.Run(ctx,
+		"confluentinc/confluent-local:7.5.0",
+		kafka.WithClusterID("outpost-test"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("Kafka running at %s", brokers[0])
+	cfg.KafkaURL = brokers[0]
+	cfg.cleanupFns = append(cfg.cleanupFns, func() {
+		if err := kafkaContainer.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	})
+}
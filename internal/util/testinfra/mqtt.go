@@ -0,0 +1,52 @@
+package testinfra
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var mqttOnce sync.Once
+
+// EnsureMQTT returns the address of an MQTT broker accepting anonymous clients.
+func EnsureMQTT() string {
+	cfg := ReadConfig()
+	if cfg.MQTTURL == "" {
+		mqttOnce.Do(func() {
+			startMQTTTestContainer(cfg)
+		})
+	}
+	return cfg.MQTTURL
+}
+
+func startMQTTTestContainer(cfg *Config) {
+	ctx := context.Background()
+
+	mqttContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "eclipse-mosquitto:2",
+			Cmd:          []string{"mosquitto", "-c", "/mosquitto-no-auth.conf"},
+			ExposedPorts: []string{"1883/tcp"},
+			WaitingFor:   wait.ForListeningPort("1883/tcp"),
+		},
+		Started: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	endpoint, err := mqttContainer.PortEndpoint(ctx, "1883/tcp", "")
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("MQTT running at %s", endpoint)
+	cfg.MQTTURL = endpoint
+	cfg.cleanupFns = append(cfg.cleanupFns, func() {
+		if err := mqttContainer.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package testinfra
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go/modules/nats"
+)
+
+var natsOnce sync.Once
+
+// EnsureNATS returns the address of a NATS server with JetStream enabled.
+func EnsureNATS() string {
+	cfg := ReadConfig()
+	if cfg.NATSURL == "" {
+		natsOnce.Do(func() {
+			startNATSTestContainer(cfg)
+		})
+	}
+	return cfg.NATSURL
+}
+
+func startNATSTestContainer(cfg *Config) {
+	ctx := context.Background()
+
+	natsContainer, err := nats.Run(ctx,
+		"nats:2.10-alpine",
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	endpoint, err := natsContainer.PortEndpoint(ctx, "4222/tcp", "")
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("NATS running at %s", endpoint)
+	cfg.NATSURL = endpoint
+	cfg.cleanupFns = append(cfg.cleanupFns, func() {
+		if err := natsContainer.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	})
+}
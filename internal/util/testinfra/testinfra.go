@@ -26,6 +26,9 @@ type Config struct {
 	RabbitMQURL       string
 	MockServerURL     string
 	GCPURL            string
+	KafkaURL          string
+	NATSURL           string
+	MQTTURL           string
 	AzureSBConnString string
 	cleanupFns        []func()
 }
@@ -64,6 +67,9 @@ func initConfig() {
 			PostgresURL:       v.GetString("TEST_POSTGRES_URL"),
 			LocalStackURL:     localstackURL,
 			GCPURL:            v.GetString("TEST_GCP_URL"),
+			KafkaURL:          v.GetString("TEST_KAFKA_URL"),
+			NATSURL:           v.GetString("TEST_NATS_URL"),
+			MQTTURL:           v.GetString("TEST_MQTT_URL"),
 			AzureSBConnString: v.GetString("TEST_AZURE_SB_CONNSTRING"),
 			RabbitMQURL:       rabbitmqURL,
 			MockServerURL:     mockServerURL,
@@ -81,6 +87,9 @@ func initConfig() {
 		RabbitMQURL:       "",
 		LocalStackURL:     "",
 		GCPURL:            "",
+		KafkaURL:          "",
+		NATSURL:           "",
+		MQTTURL:           "",
 		AzureSBConnString: "",
 		// misc
 		MockServerURL: "",